@@ -23,13 +23,20 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/hoon-kr/weblin/config"
+	"github.com/hoon-kr/weblin/internal/logger"
+	"github.com/hoon-kr/weblin/internal/privsep"
 	"github.com/hoon-kr/weblin/internal/server"
 	"github.com/spf13/cobra"
 	"go.uber.org/automaxprocs/maxprocs"
 )
 
+// 서버 정지 대기 기본 타임아웃(초) (웹 서버 정지 타임아웃 기본 값 및 고루틴 종료 대기 시간보다 길게)
+const defaultStopTimeout = 60
+
 // weblinCmd 하위 명령어 없이 실행될 때, 최상위 명령어
 var weblinCmd = &cobra.Command{
 	Use:   "weblin",
@@ -38,6 +45,10 @@ var weblinCmd = &cobra.Command{
 and do all the work through the web terminal. In addition, various processes such as adding, 
 creating, and deleting files can be easily performed through the UI.`,
 	Version: config.Version,
+	// 파일 배치 방식, 설정 파일 경로 및 설정 항목 플래그 적용 (모든 하위 명령어 공통)
+	PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+		return applyConfigFlags(cmd)
+	},
 }
 
 // startCmd 서버 가동 명령어
@@ -61,11 +72,136 @@ var stopCmd = &cobra.Command{
 	RunE:  wrapCommandFuncForCobra(server.StopServer),
 }
 
+// restartCmd 서버 재가동 명령어
+var restartCmd = &cobra.Command{
+	Use:   "restart",
+	Short: "Restart weblin (normal mode)",
+	RunE:  wrapCommandFuncForCobra(server.RestartServer),
+}
+
+// reloadCmd 서버 설정 다시 읽기 명령어
+var reloadCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "Reload weblin configuration",
+	RunE:  wrapCommandFuncForCobra(server.ReloadServer),
+}
+
+// statusCmd 서버 상태 조회 명령어
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show weblin status (exit code: 0 running, 1 unhealthy, 3 not running)",
+	Args:  cobra.NoArgs,
+	// 상태 출력으로 결과를 전달하므로 에러 메시지와 사용법은 출력하지 않음
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE:          wrapCommandFuncForCobra(server.StatusServer),
+}
+
+// logLevelCmd 로그 레벨 조회 및 변경 명령어
+var logLevelCmd = &cobra.Command{
+	Use:   "log-level [debug|info|warn|error]",
+	Short: "Show or change the log level of running weblin (reset by restart or reload of the level settings)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  wrapCommandFuncForCobra(server.LogLevelServer),
+}
+
+// configCmd 설정 관리 명령어
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage weblin configuration",
+}
+
+// configCheckCmd 설정 파일 검증 명령어
+var configCheckCmd = &cobra.Command{
+	Use:   "check [file]",
+	Short: "Validate a configuration file (default: the file used by start)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  wrapCommandFuncForCobra(server.CheckConfig),
+}
+
+// configShowCmd 최종 설정 값 출력 명령어
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the effective configuration and the source of each value",
+	Args:  cobra.NoArgs,
+	RunE:  wrapCommandFuncForCobra(server.ShowConfig),
+}
+
+// configDefaultsCmd 기본 설정 파일 출력 명령어
+var configDefaultsCmd = &cobra.Command{
+	Use:   "defaults",
+	Short: "Print a fully commented default configuration file",
+	Args:  cobra.NoArgs,
+	RunE:  wrapCommandFuncForCobra(server.PrintDefaultConfig),
+}
+
+// auditCmd 감사 로그 관리 명령어
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Manage the weblin audit log",
+}
+
+// auditVerifyCmd 감사 로그 검증 명령어
+var auditVerifyCmd = &cobra.Command{
+	Use:           "verify [file]",
+	Short:         "Verify the hash chain of an audit log (default: the audit log written by start)",
+	Args:          cobra.MaximumNArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE:          wrapCommandFuncForCobra(server.VerifyAudit),
+}
+
+// helperCmd 계정 권한 파일 작업 헬퍼 프로세스 명령어 (서버 내부용)
+var helperCmd = &cobra.Command{
+	Use:    privsep.HelperCommand,
+	Short:  "Run privilege separated helper (internal use only)",
+	Hidden: true,
+	RunE:   wrapCommandFuncForCobra(privsep.RunHelper),
+}
+
 // init cmd 패키지 임포트 시 자동 초기화
 func init() {
+	// 파일 배치 방식 플래그 (모든 하위 명령어 공통)
+	weblinCmd.PersistentFlags().String("layout", "",
+		fmt.Sprintf("filesystem layout: %s (conf, var, log next to the binary) or %s (/etc/%s, /run, /var/log/%s) (env: %s, default: %s)",
+			config.LayoutLocal, config.LayoutFHS, config.ModuleName, config.ModuleName, config.EnvLayout, config.LayoutLocal))
+	weblinCmd.PersistentFlags().String("base-dir", "",
+		fmt.Sprintf("absolute directory replacing the binary directory (%s) or the root directory (%s) (env: %s)",
+			config.LayoutLocal, config.LayoutFHS, config.EnvBaseDir))
+	// 설정 파일 경로 플래그
+	addConfigFileFlag(startCmd)
+	addConfigFileFlag(debugCmd)
+	addConfigFileFlag(restartCmd)
+	addConfigFileFlag(configCmd)
+	// 설정 항목 플래그
+	addConfigFlags(startCmd)
+	addConfigFlags(debugCmd)
+	addConfigFlags(restartCmd)
+	// 정지 대기 플래그
+	addStopFlags(stopCmd)
+	addStopFlags(restartCmd)
+	// 포그라운드 가동 플래그 (systemd 등 서비스 관리자에서 실행)
+	startCmd.Flags().Bool("foreground", false,
+		"run in the foreground without daemonizing, log to stderr as well and notify systemd ($NOTIFY_SOCKET)")
+	addConfigFlags(configShowCmd)
+
 	weblinCmd.AddCommand(startCmd)
 	weblinCmd.AddCommand(debugCmd)
 	weblinCmd.AddCommand(stopCmd)
+	weblinCmd.AddCommand(restartCmd)
+	weblinCmd.AddCommand(reloadCmd)
+	statusCmd.Flags().Bool("json", false, "print status in JSON format")
+	weblinCmd.AddCommand(statusCmd)
+	logLevelCmd.Flags().String("sink", "",
+		fmt.Sprintf("log sink to change: %s, %s or %s (default: all)", logger.SinkText, logger.SinkJson, logger.SinkConsole))
+	weblinCmd.AddCommand(logLevelCmd)
+	configCmd.AddCommand(configCheckCmd)
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configDefaultsCmd)
+	weblinCmd.AddCommand(configCmd)
+	auditCmd.AddCommand(auditVerifyCmd)
+	weblinCmd.AddCommand(auditCmd)
+	weblinCmd.AddCommand(helperCmd)
 }
 
 // Execute 명령어 실행
@@ -88,6 +224,79 @@ func Execute() {
 	}
 }
 
+// addConfigFileFlag 설정 파일 경로 플래그(--config) 등록
+//
+// Parameters:
+//   - cmd: 명령어 정보
+func addConfigFileFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("config", "",
+		fmt.Sprintf("configuration file path, relative to the current directory (env: %s, default: %s in %s layout, %s in %s layout)",
+			config.EnvConfigFile, config.ConfFilePath, config.LayoutLocal,
+			"/etc/"+config.ModuleName+"/"+filepath.Base(config.ConfFilePath), config.LayoutFHS))
+}
+
+// addStopFlags 서버 정지 대기 플래그(--timeout, --force) 등록
+//
+// Parameters:
+//   - cmd: 명령어 정보
+func addStopFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("timeout", defaultStopTimeout, "seconds to wait for weblin to exit")
+	cmd.Flags().Bool("force", false, "send SIGKILL if weblin does not exit within the timeout")
+}
+
+// addConfigFlags 설정 항목별 플래그 등록 (예: --listen-port 9090, --enable-tls)
+//
+// 설정 값 우선 순위: 명령행 플래그 > 환경 변수(WEBLIN_*) > 설정 파일 > 기본 값
+//
+// Parameters:
+//   - cmd: 명령어 정보
+func addConfigFlags(cmd *cobra.Command) {
+	flags := cmd.PersistentFlags()
+	for i := range config.Options {
+		opt := &config.Options[i]
+		flags.String(opt.FlagName(), "", fmt.Sprintf("%s (env: %s)", opt.Desc, opt.EnvName()))
+		// 사용 여부 항목은 값 없이 지정하면 사용(yes)
+		if opt.Kind == config.KindBool {
+			flags.Lookup(opt.FlagName()).NoOptDefVal = "yes"
+		}
+	}
+}
+
+// applyConfigFlags 명령행에서 지정한 파일 배치 방식, 설정 파일 경로 및 설정 값을 config 패키지에 전달
+//
+// Parameters:
+//   - cmd: 명령어 정보
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func applyConfigFlags(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+	layout, _ := flags.GetString("layout")
+	baseDir, _ := flags.GetString("base-dir")
+	if err := config.SetLayout(layout, baseDir); err != nil {
+		return err
+	}
+
+	// 작업 경로 변경 전에 현재 경로 기준의 설정 파일 경로를 절대 경로로 변환
+	if flag := flags.Lookup("config"); flag != nil && flag.Changed {
+		filePath, err := filepath.Abs(flag.Value.String())
+		if err != nil {
+			return err
+		}
+		config.SetFilePath(filePath)
+	}
+
+	values := make(map[string]string)
+	for i := range config.Options {
+		opt := &config.Options[i]
+		if flag := flags.Lookup(opt.FlagName()); flag != nil && flag.Changed {
+			values[opt.Key] = strings.TrimSpace(flag.Value.String())
+		}
+	}
+	config.SetFlagValues(values)
+	return nil
+}
+
 // wrapCommandFuncForCobra cobra.Command의 RunE 필드 랩핑 함수
 //
 // Parameters:
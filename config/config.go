@@ -21,10 +21,16 @@ package config
 
 import (
 	"bufio"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"io/fs"
+	"net"
 	"os"
-	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 var (
@@ -38,6 +44,10 @@ const (
 	PidFilePath        = "var/weblin.pid"
 	ConsoleLogFilePath = "log/weblin.log"
 	JsonLogFilePath    = "log/weblin_json.log"
+	AuditLogFilePath   = "log/weblin_audit.log"
+	AuditKeyFilePath   = "var/weblin_audit.key"
+	AuditAnchorPath    = "var/weblin_audit.anchor"
+	ControlSocketPath  = "var/weblin.sock"
 )
 
 // 종료 코드 정의
@@ -45,6 +55,8 @@ const (
 	ExitCodeSuccess = iota
 	ExitCodeFailure
 	ExitCodeFatal
+	// ExitCodeNotRunning 서버가 동작 중이지 않음 (status 명령어, LSB 규약)
+	ExitCodeNotRunning
 )
 
 // 종료 메시지 정의
@@ -71,7 +83,7 @@ func (e *ExitError) Error() string {
 	return e.Err.Error()
 }
 
-// Config 전역 설정 정보 구조체
+// Config 전역 설정 정보 구조체 (필드 추가 시 schema.go의 Options에 설정 항목 정의 추가)
 type Config struct {
 	// 최대 로그 파일 사이즈 (DEF:100MB, MIN:1MB, MAX:1000MB)
 	MaxLogFileSize int
@@ -81,88 +93,444 @@ type Config struct {
 	MaxLogFileAge int
 	// 백업 로그 파일 압축 여부 (DEF:true, ENABLE:true, DISABLE:false)
 	CompBakLogFile bool
+	// 텍스트 로그 파일 최소 로그 레벨 (DEF:info, VALUES:debug, info, warn, error)
+	TextLogLevel string
+	// JSON 로그 파일 최소 로그 레벨 (DEF:info, VALUES:debug, info, warn, error)
+	JsonLogLevel string
+	// 포그라운드 가동 시 stderr 최소 로그 레벨 (DEF:info, VALUES:debug, info, warn, error)
+	ConsoleLogLevel string
+	// 웹 서버 수신 주소 (DEF:127.0.0.1, 루프백이 아닌 주소는 EnableTLS 또는 AllowInsecureHTTP 필요)
+	ListenAddress string
+	// 웹 서버 수신 포트 (DEF:8080, MIN:1, MAX:65535)
+	ListenPort int
+	// HTTPS 사용 여부 (DEF:false, ENABLE:true, DISABLE:false)
+	EnableTLS bool
+	// 루프백이 아닌 수신 주소에서 HTTP 허용 여부 (DEF:false, ENABLE:true, DISABLE:false)
+	AllowInsecureHTTP bool
+	// HTTPS 인증서 파일 경로 (DEF:conf/weblin.crt)
+	TLSCertFile string
+	// HTTPS 개인키 파일 경로 (DEF:conf/weblin.key)
+	TLSKeyFile string
+	// 요청 헤더 읽기 타임아웃(초) (DEF:10, MIN:1, MAX:600)
+	ReadHeaderTimeout int
+	// 요청 읽기 타임아웃(초) (DEF:0(무제한), MIN:0, MAX:86400)
+	ReadTimeout int
+	// 응답 쓰기 타임아웃(초) (DEF:0(무제한), MIN:0, MAX:86400)
+	WriteTimeout int
+	// Keep-Alive 유휴 연결 타임아웃(초) (DEF:120, MIN:1, MAX:3600)
+	IdleTimeout int
+	// 서버 종료 시 처리 중인 요청 대기 타임아웃(초) (DEF:30, MIN:1, MAX:600)
+	ShutdownTimeout int
+	// 로그인 세션 최대 유지 시간(분) (DEF:720, MIN:1, MAX:10080)
+	SessionTimeout int
+	// 로그인 세션 유휴 만료 시간(분) (DEF:30, MIN:1, MAX:1440)
+	SessionIdleTimeout int
+	// 중단된 파일 업로드 만료 시간(분) (DEF:60, MIN:1, MAX:10080)
+	UploadTimeout int
+	// 압축 해제 최대 크기(MB) (DEF:10240, MIN:1, MAX:1048576)
+	ExtractMaxSize int
+	// 압축 해제 최대 항목 수 (DEF:100000, MIN:1, MAX:10000000)
+	ExtractMaxEntries int
+	// 텍스트 편집기 최대 파일 크기(MB) (DEF:5, MIN:1, MAX:100)
+	EditorMaxFileSize int
+	// 시스템 자원 사용량 수집 주기(초) (DEF:5, MIN:1, MAX:3600)
+	MetricsInterval int
+	// 시스템 자원 사용량 이력 보관 개수 (DEF:720, MIN:1, MAX:100000)
+	MetricsHistorySize int
+	// Prometheus 지표(/metrics) 제공 여부 (DEF:true, ENABLE:true, DISABLE:false)
+	EnablePrometheus bool
+	// Prometheus 지표 요청 인증 Bearer 토큰 (DEF:없음(인증 안 함))
+	PrometheusToken string
+
+	// 설정 파일 항목 이름별 값의 출처 (기본 값인 항목은 없음)
+	sources map[string]string
+}
+
+// 설정 값 출처 정의
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// Source 설정 값의 출처 반환
+//
+// Parameters:
+//   - key: 설정 파일 항목 이름
+//
+// Returns:
+//   - string: 출처 (예: "default", "file (conf/weblin.properties:3)", "env (WEBLIN_LISTEN_PORT)")
+func (c *Config) Source(key string) string {
+	if source, exists := c.sources[key]; exists {
+		return source
+	}
+	return SourceDefault
+}
+
+// setSource 설정 값의 출처 기록
+//
+// Parameters:
+//   - key: 설정 파일 항목 이름
+//   - source: 출처
+func (c *Config) setSource(key, source string) {
+	if c.sources == nil {
+		c.sources = make(map[string]string)
+	}
+	c.sources[key] = source
 }
 
 // RunConfig 런타임 전역 설정 정보 구조체
 type RunConfig struct {
 	DebugMode bool
-	Pid       int
+	// 데몬 프로세스를 생성하지 않고 가동 (systemd 등 서비스 관리자에서 실행)
+	Foreground bool
+	Pid        int
+	StartTime  time.Time
 }
 
-var Conf Config
+// current 현재 적용 중인 설정 정보 (설정 다시 읽기 시 통째로 교체)
+var current atomic.Pointer[Config]
+
 var RunConf RunConfig
 
+// subscribers 설정 변경 알림 함수 목록
+var (
+	subscribersMu sync.Mutex
+	subscribers   []func(old, new *Config)
+)
+
 // init config 패키지 임포트 시 자동 초기화
 func init() {
-	Conf.MaxLogFileSize = 100
-	Conf.MaxLogFileBackup = 10
-	Conf.MaxLogFileAge = 90
-	Conf.CompBakLogFile = true
+	current.Store(Default())
 }
 
-// LoadConfig 설정 파일 로드
+// Default 기본 설정 정보 생성 (설정 항목 정의의 기본 값)
 //
-// Parameters:
-//   - filePath: 설정 파일 경로
+// Returns:
+//   - *Config: 기본 설정 정보
+func Default() *Config {
+	c := &Config{}
+	for i := range Options {
+		if err := Options[i].Set(c, Options[i].Default); err != nil {
+			panic(fmt.Sprintf("config: invalid default of %s: %s", Options[i].Key, err))
+		}
+	}
+	return c
+}
+
+// Current 현재 적용 중인 설정 정보 반환
+//
+// 설정 다시 읽기 시 구조체가 교체되므로 여러 값을 함께 사용할 경우 한 번 조회한 구조체를 사용한다.
+// 반환된 구조체는 수정하지 않아야 한다.
+//
+// Returns:
+//   - *Config: 현재 설정 정보
+func Current() *Config {
+	return current.Load()
+}
+
+// LoadConfig 설정 파일을 로드하여 현재 설정으로 적용 (서버 가동 시)
 //
 // Returns:
 //   - error: 성공(nil), 실패(error)
-func LoadConfig(filePath string) error {
-	// 설정 파일 파싱
-	config, err := parseConfig(filePath)
+func LoadConfig() error {
+	conf, err := Load()
 	if err != nil {
 		return err
 	}
+	current.Store(conf)
+	return nil
+}
 
-	if valueStr, exists := config["MaxLogFileSize"]; exists {
-		value, err := strconv.Atoi(valueStr)
-		if err != nil && value >= 1 && value <= 1000 {
-			Conf.MaxLogFileSize = value
-		}
+// Reload 설정 파일을 다시 로드하여 검증 후 현재 설정과 교체하고 변경 알림 함수 호출
+//
+// 파일을 읽을 수 없거나 검증에 실패하면 현재 설정을 그대로 유지한다.
+// 환경 변수와 명령행 플래그 설정 값은 다시 읽은 설정 파일보다 우선 적용된다.
+//
+// Returns:
+//   - []string: 변경된 설정 항목 이름 목록
+//   - error: 성공(nil), 실패(error)
+func Reload() ([]string, error) {
+	conf, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	old := current.Swap(conf)
+	changed := Diff(old, conf)
+
+	subscribersMu.Lock()
+	fns := append([]func(old, new *Config){}, subscribers...)
+	subscribersMu.Unlock()
+	for _, fn := range fns {
+		fn(old, conf)
 	}
 
-	if valueStr, exists := config["MaxLogFileBackup"]; exists {
-		value, err := strconv.Atoi(valueStr)
-		if err != nil && value >= 1 && value <= 100 {
-			Conf.MaxLogFileBackup = value
+	return changed, nil
+}
+
+// Subscribe 설정 다시 읽기로 설정이 교체될 때 호출할 함수 등록
+//
+// Parameters:
+//   - fn: 이전 설정과 새 설정을 전달받는 함수
+func Subscribe(fn func(old, new *Config)) {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+
+	subscribers = append(subscribers, fn)
+}
+
+// Diff 두 설정 정보에서 값이 다른 항목 이름 목록 반환
+//
+// Parameters:
+//   - old: 이전 설정 정보
+//   - new: 새 설정 정보
+//
+// Returns:
+//   - []string: 값이 다른 항목 이름 목록 (설정 파일 항목 이름)
+func Diff(old, new *Config) []string {
+	var changed []string
+	for i := range Options {
+		if Options[i].Get(old) != Options[i].Get(new) {
+			changed = append(changed, Options[i].Key)
 		}
 	}
+	return changed
+}
+
+// LineError 설정 파일 특정 라인의 오류 정보 구조체
+type LineError struct {
+	File string
+	Line int
+	Err  error
+}
+
+// Error 오류 위치(파일:라인)를 포함한 메시지로 변환
+//
+// Returns:
+//   - string: error
+func (e *LineError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Err)
+}
+
+// Unwrap 원본 오류 반환
+//
+// Returns:
+//   - error: 원본 오류
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError 설정 파일 검증 오류 목록 구조체 (발견한 오류를 모두 포함)
+type ValidationError struct {
+	Errs []error
+}
+
+// Error 오류 목록을 하나의 메시지로 변환
+//
+// Returns:
+//   - string: error
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errs))
+	for _, err := range e.Errs {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
 
-	if valueStr, exists := config["MaxLogFileAge"]; exists {
-		value, err := strconv.Atoi(valueStr)
-		if err != nil && value >= 1 && value <= 365 {
-			Conf.MaxLogFileAge = value
+// Unwrap 오류 목록 반환
+//
+// Returns:
+//   - []error: 오류 목록
+func (e *ValidationError) Unwrap() []error {
+	return e.Errs
+}
+
+// Load 서버가 사용할 최종 설정 정보 반환 (현재 설정은 변경하지 않음)
+//
+// 우선 순위는 명령행 플래그 > 환경 변수(WEBLIN_*) > 설정 파일 > 기본 값이며,
+// 설정 파일 경로를 지정하지 않았고 기본 경로에 설정 파일이 없을 경우 설정 파일은 생략한다.
+// 발견한 오류는 모두 *ValidationError로 반환하며, 오류가 하나라도 있으면 설정 정보를 반환하지 않는다.
+//
+// Returns:
+//   - *Config: 설정 정보
+//   - error: 성공(nil), 실패(error)
+func Load() (*Config, error) {
+	filePath, explicit := FilePath()
+
+	conf := Default()
+	errs, err := conf.applyFile(filePath)
+	if err != nil && (explicit || !errors.Is(err, fs.ErrNotExist)) {
+		return nil, err
+	}
+	errs = append(errs, conf.applyEnv()...)
+	errs = append(errs, conf.applyFlags()...)
+
+	if err := validate(conf, filePath, errs); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+// LoadFile 설정 파일만 기본 설정에 적용하여 검증한 설정 정보 반환 (환경 변수, 명령행 플래그 제외)
+//
+// 알 수 없는 항목, 중복 항목, 형식 및 범위 오류를 모두 찾아 *ValidationError로 반환하며,
+// 오류가 하나라도 있으면 설정 정보를 반환하지 않는다.
+//
+// Parameters:
+//   - filePath: 설정 파일 경로
+//
+// Returns:
+//   - *Config: 설정 정보
+//   - error: 성공(nil), 실패(error)
+func LoadFile(filePath string) (*Config, error) {
+	conf := Default()
+	errs, err := conf.applyFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validate(conf, filePath, errs); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+// validate 항목별 오류가 없을 경우 항목 간 검증을 수행하고 오류 목록을 하나의 오류로 변환
+//
+// Parameters:
+//   - conf: 설정 정보
+//   - filePath: 설정 파일 경로
+//   - errs: 항목별 오류 목록
+//
+// Returns:
+//   - error: 성공(nil), 실패(*ValidationError)
+func validate(conf *Config, filePath string, errs []error) error {
+	// 항목별 오류가 없을 경우에만 항목 간 검증 (잘못된 값으로 인한 부수 오류 방지)
+	if len(errs) == 0 {
+		if err := conf.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s", filePath, err))
 		}
 	}
+	if len(errs) > 0 {
+		return &ValidationError{Errs: errs}
+	}
+	return nil
+}
 
-	if valueStr, exists := config["CompressBackupLogFile"]; exists {
-		if strings.ToLower(valueStr) == "no" {
-			Conf.CompBakLogFile = false
+// applyFile 설정 파일을 파싱하여 설정 정보에 적용
+//
+// Parameters:
+//   - filePath: 설정 파일 경로
+//
+// Returns:
+//   - []error: 라인별 오류 목록 (*LineError)
+//   - error: 설정 파일 읽기 실패(error)
+func (c *Config) applyFile(filePath string) ([]error, error) {
+	// 설정 파일 파싱
+	entries, err := parseConfig(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	lines := make(map[string]int, len(entries))
+	for _, entry := range entries {
+		lineErr := func(format string, args ...interface{}) {
+			errs = append(errs, &LineError{File: filePath, Line: entry.line, Err: fmt.Errorf(format, args...)})
+		}
+
+		if entry.key == "" {
+			lineErr("malformed line %q (expected \"<key> <value>\")", entry.value)
+			continue
+		}
+		opt, ok := LookupOption(entry.key)
+		if !ok {
+			lineErr("unknown key %q", entry.key)
+			continue
 		}
+		if line, exists := lines[entry.key]; exists {
+			lineErr("duplicate key %q (already set at line %d)", entry.key, line)
+			continue
+		}
+		lines[entry.key] = entry.line
+
+		if err := opt.Set(c, entry.value); err != nil {
+			lineErr("%s: %s", entry.key, err)
+			continue
+		}
+		c.setSource(entry.key, fmt.Sprintf("%s (%s:%d)", SourceFile, filePath, entry.line))
 	}
 
+	return errs, nil
+}
+
+// Validate 설정 값 검증
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func (c *Config) Validate() error {
+	// 계정 비밀번호가 평문으로 네트워크에 전송되지 않도록 루프백이 아닌 주소에서는 HTTPS 필요
+	if !c.EnableTLS && !c.AllowInsecureHTTP && !isLoopback(c.ListenAddress) {
+		return fmt.Errorf("ListenAddress %q is not a loopback address and EnableTLS is disabled "+
+			"(passwords would be sent in cleartext); enable TLS, listen on 127.0.0.1 or set AllowInsecureHTTP yes",
+			c.ListenAddress)
+	}
+
+	// HTTPS 사용 시 인증서와 개인키를 읽을 수 있는지 확인
+	if c.EnableTLS {
+		if _, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile); err != nil {
+			return fmt.Errorf("failed to load certificate (%s, %s): %s", c.TLSCertFile, c.TLSKeyFile, err)
+		}
+	}
 	return nil
 }
 
-// parseConfig 설정 파일을 파싱하여 맵에 저장
+// isLoopback 수신 주소가 루프백 주소인지 확인
+//
+// Parameters:
+//   - addr: 수신 주소 (빈 문자열은 모든 주소)
+//
+// Returns:
+//   - bool: 루프백(true), 그 외(false)
+func isLoopback(addr string) bool {
+	if addr == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(addr)
+	return ip != nil && ip.IsLoopback()
+}
+
+// configEntry 설정 파일 라인 정보 구조체
+type configEntry struct {
+	// 항목 이름 (형식이 잘못된 라인일 경우 빈 문자열)
+	key   string
+	value string
+	line  int
+}
+
+// parseConfig 설정 파일을 파싱하여 라인 순서대로 반환
+//
+// 각 라인은 "<항목 이름> <값>" 형식이며, 값에는 공백이 포함될 수 있다.
+// 비어있거나 '#'으로 시작하는 라인은 무시한다.
 //
 // Parameters:
 //   - filePath: 설정 파일 경로
 //
 // Returns:
-//   - map[string]string: 설정 정보 맵
+//   - []configEntry: 설정 파일 라인 정보 목록
 //   - error: 성공(nil), 실패(error)
-func parseConfig(filePath string) (map[string]string, error) {
-	config := make(map[string]string)
-
+func parseConfig(filePath string) ([]configEntry, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %s", err)
+		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
+	var entries []configEntry
 	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
+	for lineNo := 1; scanner.Scan(); lineNo++ {
 		line := strings.TrimSpace(scanner.Text())
 
 		// 비어있거나 주석 처리된 라인은 무시
@@ -170,21 +538,21 @@ func parseConfig(filePath string) (map[string]string, error) {
 			continue
 		}
 
-		// 각 라인을 key, value 형태로 분리
-		parts := strings.Fields(line)
-		if len(parts) != 2 {
-			continue
+		// 각 라인을 key, value 형태로 분리 (key=value 등 공백으로 구분되지 않은 라인은 형식 오류)
+		key, value := line, ""
+		if i := strings.IndexAny(line, " \t"); i >= 0 {
+			key, value = line[:i], strings.TrimSpace(line[i+1:])
+		}
+		if strings.ContainsAny(key, "=:") {
+			key, value = "", line
 		}
-		key := parts[0]
-		value := parts[1]
 
-		// 설정 정보 맵에 저장
-		config[key] = value
+		entries = append(entries, configEntry{key: key, value: value, line: lineNo})
 	}
 
 	if err := scanner.Err(); err != nil {
 		return nil, fmt.Errorf("error reading config file: %s", err)
 	}
 
-	return config, nil
+	return entries, nil
 }
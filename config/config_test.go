@@ -0,0 +1,408 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeConfig 임시 디렉터리에 설정 파일 작성
+//
+// Parameters:
+//   - t: 테스트 정보
+//   - content: 설정 파일 내용
+//
+// Returns:
+//   - string: 설정 파일 경로
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "weblin.properties")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	return path
+}
+
+// setOverrides 명령행 설정 파일 경로와 설정 값을 지정하고 테스트 종료 시 초기화
+//
+// Parameters:
+//   - t: 테스트 정보
+//   - filePath: 설정 파일 경로
+//   - values: 설정 파일 항목 이름별 설정 값
+func setOverrides(t *testing.T, filePath string, values map[string]string) {
+	t.Helper()
+	SetFilePath(filePath)
+	SetFlagValues(values)
+	t.Cleanup(func() {
+		SetFilePath("")
+		SetFlagValues(nil)
+	})
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	// 라인별 오류 (라인 번호, 오류 메시지에 포함되어야 하는 내용)
+	type lineErr struct {
+		line int
+		want string
+	}
+	tests := []struct {
+		name    string
+		content string
+		want    []lineErr
+	}{
+		{
+			name:    "unknown key",
+			content: "# comment\n\nListenPort 9000\nListenPrt 9001\n",
+			want:    []lineErr{{4, `unknown key "ListenPrt"`}},
+		},
+		{
+			name:    "duplicate key",
+			content: "ListenPort 9000\nMaxLogFileAge 30\nListenPort 9001\n",
+			want:    []lineErr{{3, `duplicate key "ListenPort" (already set at line 1)`}},
+		},
+		{
+			name:    "key=value",
+			content: "ListenPort=9000\n",
+			want:    []lineErr{{1, `malformed line "ListenPort=9000"`}},
+		},
+		{
+			name:    "key: value",
+			content: "\n  ListenPort: 9000\n",
+			want:    []lineErr{{2, `malformed line "ListenPort: 9000"`}},
+		},
+		{
+			name:    "missing value",
+			content: "ListenPort\nEnableTLS\t\n",
+			want:    []lineErr{{1, "ListenPort: missing value"}, {2, "EnableTLS: missing value"}},
+		},
+		{
+			name:    "below minimum",
+			content: "ListenPort 0\n",
+			want:    []lineErr{{1, "ListenPort: value 0 out of range (MIN:1, MAX:65535)"}},
+		},
+		{
+			name:    "above maximum",
+			content: "MaxLogFileAge 366\n",
+			want:    []lineErr{{1, "MaxLogFileAge: value 366 out of range (MIN:1, MAX:365)"}},
+		},
+		{
+			name:    "invalid integer",
+			content: "ListenPort 80a\n",
+			want:    []lineErr{{1, `ListenPort: invalid integer "80a"`}},
+		},
+		{
+			name:    "invalid bool",
+			content: "EnableTLS true\n",
+			want:    []lineErr{{1, `EnableTLS: invalid value "true" (expected yes or no)`}},
+		},
+		{
+			name:    "invalid log level",
+			content: "TextLogLevel trace\n",
+			want:    []lineErr{{1, `TextLogLevel: invalid value "trace" (expected debug, info, warn or error)`}},
+		},
+		{
+			name:    "all errors reported",
+			content: "ListenPort 0\nFoo bar\nEnableTLS maybe\nListenPort 8080\n",
+			want: []lineErr{
+				{1, "ListenPort: value 0 out of range"},
+				{2, `unknown key "Foo"`},
+				{3, `EnableTLS: invalid value "maybe"`},
+				{4, `duplicate key "ListenPort"`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.content)
+			conf, err := LoadFile(path)
+			if conf != nil {
+				t.Errorf("LoadFile() returned a config with errors")
+			}
+
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("LoadFile() error = %v, want *ValidationError", err)
+			}
+			if len(verr.Errs) != len(tt.want) {
+				t.Fatalf("LoadFile() errors = %v, want %d errors", verr.Errs, len(tt.want))
+			}
+			for i, want := range tt.want {
+				var lerr *LineError
+				if !errors.As(verr.Errs[i], &lerr) {
+					t.Fatalf("error %d = %v, want *LineError", i, verr.Errs[i])
+				}
+				if lerr.File != path || lerr.Line != want.line || !strings.Contains(lerr.Err.Error(), want.want) {
+					t.Errorf("error %d = %v, want %s:%d: %s", i, lerr, path, want.line, want.want)
+				}
+				if !strings.HasPrefix(lerr.Error(), path+":") {
+					t.Errorf("error %d = %q, want file:line prefix", i, lerr.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestLoadFileValues(t *testing.T) {
+	path := writeConfig(t, strings.Join([]string{
+		"# [HTTP Server Configuration]",
+		"ListenPort   9000  ",
+		"\tEnableTLS no",
+		"CompressBackupLogFile NO",
+		"EnablePrometheus Yes",
+		"TextLogLevel DEBUG",
+		"MaxLogFileAge 365",
+		"ReadTimeout 0",
+		"PrometheusToken secret token",
+		"",
+	}, "\n"))
+
+	conf, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if conf.ListenPort != 9000 || conf.EnableTLS || conf.CompBakLogFile || !conf.EnablePrometheus ||
+		conf.TextLogLevel != "debug" || conf.MaxLogFileAge != 365 || conf.ReadTimeout != 0 ||
+		conf.PrometheusToken != "secret token" {
+		t.Errorf("LoadFile() = %+v", conf)
+	}
+	if got, want := conf.Source("ListenPort"), "file ("+path+":2)"; got != want {
+		t.Errorf("Source(ListenPort) = %q, want %q", got, want)
+	}
+	if got := conf.Source("ListenAddress"); got != SourceDefault {
+		t.Errorf("Source(ListenAddress) = %q, want %q", got, SourceDefault)
+	}
+	if conf.ListenAddress != "127.0.0.1" || conf.MaxLogFileSize != 100 {
+		t.Errorf("defaults not kept: ListenAddress=%q, MaxLogFileSize=%d", conf.ListenAddress, conf.MaxLogFileSize)
+	}
+}
+
+func TestLoadFileNotFound(t *testing.T) {
+	_, err := LoadFile(filepath.Join(t.TempDir(), "missing.properties"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("LoadFile() error = %v, want not exist", err)
+	}
+}
+
+func TestValidateListenAddress(t *testing.T) {
+	tests := []struct {
+		content string
+		wantErr bool
+	}{
+		{"ListenAddress 127.0.0.1\n", false},
+		{"ListenAddress ::1\n", false},
+		{"ListenAddress localhost\n", false},
+		{"ListenAddress 0.0.0.0\n", true},
+		{"ListenAddress\n", true},
+		{"ListenAddress 192.0.2.1\n", true},
+		{"ListenAddress 0.0.0.0\nAllowInsecureHTTP yes\n", false},
+		{"ListenAddress 0.0.0.0\nAllowInsecureHTTP no\n", true},
+	}
+	for _, tt := range tests {
+		path := writeConfig(t, tt.content)
+		_, err := LoadFile(path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("LoadFile(%q) error = %v, wantErr %t", tt.content, err, tt.wantErr)
+		}
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeConfig(t, "ListenPort 9000\nMaxLogFileSize 50\nTextLogLevel debug\n")
+	setOverrides(t, path, map[string]string{"ListenPort": "9200"})
+	t.Setenv("WEBLIN_LISTEN_PORT", "9100")
+	t.Setenv("WEBLIN_MAX_LOG_FILE_SIZE", "60")
+
+	conf, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tests := []struct {
+		key    string
+		value  string
+		source string
+	}{
+		{"ListenPort", "9200", "flag (--listen-port)"},
+		{"MaxLogFileSize", "60", "env (WEBLIN_MAX_LOG_FILE_SIZE)"},
+		{"TextLogLevel", "debug", "file (" + path + ":3)"},
+		{"JsonLogLevel", "info", SourceDefault},
+	}
+	for _, tt := range tests {
+		opt, _ := LookupOption(tt.key)
+		if got := opt.Get(conf); got != tt.value {
+			t.Errorf("%s = %q, want %q", tt.key, got, tt.value)
+		}
+		if got := conf.Source(tt.key); got != tt.source {
+			t.Errorf("Source(%s) = %q, want %q", tt.key, got, tt.source)
+		}
+	}
+}
+
+func TestLoadConfigFileEnv(t *testing.T) {
+	path := writeConfig(t, "ListenPort 9000\n")
+	t.Setenv(EnvConfigFile, path)
+
+	conf, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if conf.ListenPort != 9000 {
+		t.Errorf("ListenPort = %d, want 9000", conf.ListenPort)
+	}
+
+	// 명령행 플래그로 지정한 경로가 환경 변수보다 우선
+	setOverrides(t, filepath.Join(t.TempDir(), "missing.properties"), nil)
+	if _, err := Load(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Load() error = %v, want not exist", err)
+	}
+}
+
+func TestLoadOverrideErrors(t *testing.T) {
+	path := writeConfig(t, "ListenPort 0\n")
+	setOverrides(t, path, map[string]string{"EnableTLS": "on"})
+	t.Setenv("WEBLIN_MAX_LOG_FILE_AGE", "0")
+
+	conf, err := Load()
+	var verr *ValidationError
+	if conf != nil || !errors.As(err, &verr) {
+		t.Fatalf("Load() = %v, %v, want *ValidationError", conf, err)
+	}
+
+	// 설정 파일, 환경 변수, 명령행 플래그의 오류를 모두 보고
+	wants := []string{
+		path + ":1: ListenPort: value 0 out of range",
+		"env WEBLIN_MAX_LOG_FILE_AGE: value 0 out of range",
+		`flag --enable-tls: invalid value "on" (expected yes or no)`,
+	}
+	if len(verr.Errs) != len(wants) {
+		t.Fatalf("Load() errors = %v, want %d errors", verr.Errs, len(wants))
+	}
+	for i, want := range wants {
+		if !strings.Contains(verr.Errs[i].Error(), want) {
+			t.Errorf("error %d = %v, want %q", i, verr.Errs[i], want)
+		}
+	}
+}
+
+func TestOptionSet(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"ListenPort", "1", "1", false},
+		{"ListenPort", "65535", "65535", false},
+		{"ListenPort", "65536", "", true},
+		{"ListenPort", "-1", "", true},
+		{"ListenPort", " 80", "", true},
+		{"ReadTimeout", "0", "0", false},
+		{"ReadTimeout", "86401", "", true},
+		{"EnableTLS", "yes", "yes", false},
+		{"EnableTLS", "YES", "yes", false},
+		{"EnableTLS", "no", "no", false},
+		{"EnableTLS", "No", "no", false},
+		{"EnableTLS", "1", "", true},
+		{"EnableTLS", "", "", true},
+		{"ConsoleLogLevel", "Warn", "warn", false},
+		{"ConsoleLogLevel", "", "", true},
+		{"TLSCertFile", "", "", false},
+		{"TLSCertFile", "/etc/weblin/cert.pem", "/etc/weblin/cert.pem", false},
+	}
+	for _, tt := range tests {
+		opt, ok := LookupOption(tt.key)
+		if !ok {
+			t.Fatalf("LookupOption(%q) not found", tt.key)
+		}
+		conf := Default()
+		err := opt.Set(conf, tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Set(%s, %q) error = %v, wantErr %t", tt.key, tt.value, err, tt.wantErr)
+			continue
+		}
+		if err == nil && opt.Get(conf) != tt.want {
+			t.Errorf("Set(%s, %q) = %q, want %q", tt.key, tt.value, opt.Get(conf), tt.want)
+		}
+	}
+}
+
+func TestOptionNames(t *testing.T) {
+	tests := []struct {
+		key  string
+		env  string
+		flag string
+	}{
+		{"ListenPort", "WEBLIN_LISTEN_PORT", "listen-port"},
+		{"EnableTLS", "WEBLIN_ENABLE_TLS", "enable-tls"},
+		{"TLSCertFile", "WEBLIN_TLS_CERT_FILE", "tls-cert-file"},
+		{"AllowInsecureHTTP", "WEBLIN_ALLOW_INSECURE_HTTP", "allow-insecure-http"},
+		{"MaxLogFileSize", "WEBLIN_MAX_LOG_FILE_SIZE", "max-log-file-size"},
+	}
+	for _, tt := range tests {
+		opt, ok := LookupOption(tt.key)
+		if !ok {
+			t.Fatalf("LookupOption(%q) not found", tt.key)
+		}
+		if got := opt.EnvName(); got != tt.env {
+			t.Errorf("EnvName(%s) = %q, want %q", tt.key, got, tt.env)
+		}
+		if got := opt.FlagName(); got != tt.flag {
+			t.Errorf("FlagName(%s) = %q, want %q", tt.key, got, tt.flag)
+		}
+	}
+}
+
+func TestDefaultsFile(t *testing.T) {
+	// 기본 설정 파일은 모든 항목이 주석 처리되어 기본 설정과 같음
+	var b strings.Builder
+	if err := WriteDefaults(&b); err != nil {
+		t.Fatalf("WriteDefaults() error = %v", err)
+	}
+	conf, err := LoadFile(writeConfig(t, b.String()))
+	if err != nil {
+		t.Fatalf("LoadFile(defaults) error = %v", err)
+	}
+	if changed := Diff(Default(), conf); len(changed) != 0 {
+		t.Errorf("Diff(defaults) = %v, want none", changed)
+	}
+
+	// 설정 항목 라인의 주석을 해제해도 기본 설정과 같음
+	var lines []string
+	for _, line := range strings.Split(b.String(), "\n") {
+		fields := strings.Fields(strings.TrimPrefix(line, "#"))
+		if len(fields) == 0 {
+			continue
+		}
+		if _, ok := LookupOption(fields[0]); ok {
+			lines = append(lines, strings.TrimPrefix(line, "#"))
+		}
+	}
+	if len(lines) != len(Options) {
+		t.Fatalf("WriteDefaults() wrote %d options, want %d", len(lines), len(Options))
+	}
+	conf, err = LoadFile(writeConfig(t, strings.Join(lines, "\n")))
+	if err != nil {
+		t.Fatalf("LoadFile(uncommented defaults) error = %v", err)
+	}
+	if changed := Diff(Default(), conf); len(changed) != 0 {
+		t.Errorf("Diff(uncommented defaults) = %v, want none", changed)
+	}
+}
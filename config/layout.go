@@ -0,0 +1,169 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hoon-kr/weblin/pkg/utils/file"
+)
+
+// 파일 배치 방식 정의
+const (
+	// LayoutLocal 실행 파일(또는 기준 경로) 아래에 conf, var, log 디렉터리 배치 (기본)
+	LayoutLocal = "local"
+	// LayoutFHS /etc/weblin, /run, /var/log/weblin 배치 (배포판 패키지)
+	LayoutFHS = "fhs"
+)
+
+const (
+	// EnvLayout 파일 배치 방식 환경 변수 이름
+	EnvLayout = EnvPrefix + "LAYOUT"
+	// EnvBaseDir 기준 경로 환경 변수 이름
+	EnvBaseDir = EnvPrefix + "BASE_DIR"
+)
+
+// Layout 파일 배치 정보 구조체
+type Layout struct {
+	// 배치 방식 (local, fhs)
+	Name string
+	// 기준 경로 (local: 실행 파일 경로 대체, fhs: 루트 경로 대체, 빈 문자열일 경우 기본)
+	BaseDir string
+	// 작업 경로 (설정 파일 경로 및 설정 값의 상대 경로 기준, 빈 문자열일 경우 실행 파일 경로)
+	WorkDir string
+	// 설정 파일 경로
+	ConfFile string
+	// PID 파일 경로
+	PidFile string
+	// 로그 파일 경로
+	ConsoleLogFile string
+	JsonLogFile    string
+	// 감사 로그 파일 경로
+	AuditLogFile string
+	// 감사 로그 해시 체인 키 파일 경로 (감사 로그와 다른 디렉터리)
+	AuditKeyFile string
+	// 감사 로그 마지막 항목 기록 파일 경로 (감사 로그와 다른 디렉터리)
+	AuditAnchorFile string
+	// 제어 API Unix 소켓 경로
+	ControlSocket string
+}
+
+// layout 현재 파일 배치 정보 (명령어 실행 초기에 한 번 설정)
+var layout = &Layout{
+	Name:            LayoutLocal,
+	ConfFile:        ConfFilePath,
+	PidFile:         PidFilePath,
+	ConsoleLogFile:  ConsoleLogFilePath,
+	JsonLogFile:     JsonLogFilePath,
+	AuditLogFile:    AuditLogFilePath,
+	AuditKeyFile:    AuditKeyFilePath,
+	AuditAnchorFile: AuditAnchorPath,
+	ControlSocket:   ControlSocketPath,
+}
+
+// SetLayout 파일 배치 방식 설정
+//
+// 빈 문자열로 지정한 항목은 환경 변수(WEBLIN_LAYOUT, WEBLIN_BASE_DIR) 값을 사용한다.
+// 데몬 프로세스는 작업 경로를 변경한 뒤 같은 인자로 다시 실행되므로 기준 경로는 절대 경로만 허용한다.
+//
+// Parameters:
+//   - name: 배치 방식 (local, fhs)
+//   - baseDir: 기준 경로 (절대 경로)
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func SetLayout(name, baseDir string) error {
+	if name == "" {
+		name = os.Getenv(EnvLayout)
+	}
+	if baseDir == "" {
+		baseDir = os.Getenv(EnvBaseDir)
+	}
+	if baseDir != "" {
+		if !filepath.IsAbs(baseDir) {
+			return fmt.Errorf("base directory must be an absolute path: %s", baseDir)
+		}
+		baseDir = filepath.Clean(baseDir)
+	}
+
+	switch name {
+	case "", LayoutLocal:
+		layout = &Layout{
+			Name:            LayoutLocal,
+			BaseDir:         baseDir,
+			WorkDir:         baseDir,
+			ConfFile:        ConfFilePath,
+			PidFile:         PidFilePath,
+			ConsoleLogFile:  ConsoleLogFilePath,
+			JsonLogFile:     JsonLogFilePath,
+			AuditLogFile:    AuditLogFilePath,
+			AuditKeyFile:    AuditKeyFilePath,
+			AuditAnchorFile: AuditAnchorPath,
+			ControlSocket:   ControlSocketPath,
+		}
+	case LayoutFHS:
+		root := baseDir
+		if root == "" {
+			root = "/"
+		}
+		confDir := filepath.Join(root, "etc", ModuleName)
+		logDir := filepath.Join(root, "var", "log", ModuleName)
+		stateDir := filepath.Join(root, "var", "lib", ModuleName)
+		layout = &Layout{
+			Name:            LayoutFHS,
+			BaseDir:         baseDir,
+			WorkDir:         confDir,
+			ConfFile:        filepath.Join(confDir, filepath.Base(ConfFilePath)),
+			PidFile:         filepath.Join(root, "run", filepath.Base(PidFilePath)),
+			ConsoleLogFile:  filepath.Join(logDir, filepath.Base(ConsoleLogFilePath)),
+			JsonLogFile:     filepath.Join(logDir, filepath.Base(JsonLogFilePath)),
+			AuditLogFile:    filepath.Join(logDir, filepath.Base(AuditLogFilePath)),
+			AuditKeyFile:    filepath.Join(stateDir, filepath.Base(AuditKeyFilePath)),
+			AuditAnchorFile: filepath.Join(stateDir, filepath.Base(AuditAnchorPath)),
+			ControlSocket:   filepath.Join(root, "run", filepath.Base(ControlSocketPath)),
+		}
+	default:
+		return fmt.Errorf("invalid layout: %s (expected %s or %s)", name, LayoutLocal, LayoutFHS)
+	}
+
+	return nil
+}
+
+// Paths 현재 파일 배치 정보 반환
+//
+// Returns:
+//   - *Layout: 파일 배치 정보 (수정하지 않아야 함)
+func Paths() *Layout {
+	return layout
+}
+
+// ChangeWorkDir 파일 배치 방식의 작업 경로로 작업 경로 변경
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func ChangeWorkDir() error {
+	if layout.WorkDir == "" {
+		return file.ChangeWorkPathToModulePath()
+	}
+
+	if err := os.Chdir(layout.WorkDir); err != nil {
+		return fmt.Errorf("failed to change dir: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const (
+	// EnvPrefix 설정 항목 환경 변수 이름 접두사 (예: ListenPort -> WEBLIN_LISTEN_PORT)
+	EnvPrefix = "WEBLIN_"
+	// EnvConfigFile 설정 파일 경로 환경 변수 이름
+	EnvConfigFile = EnvPrefix + "CONFIG"
+)
+
+// 명령행에서 지정한 설정 파일 경로 및 설정 값 (설정 다시 읽기 시에도 유지)
+var (
+	overrideMu   sync.Mutex
+	flagFilePath string
+	flagValues   map[string]string
+)
+
+// SetFilePath 명령행 플래그(--config)로 지정한 설정 파일 경로 저장
+//
+// Parameters:
+//   - filePath: 설정 파일 경로 (명령어를 실행한 경로 기준의 절대 경로)
+func SetFilePath(filePath string) {
+	overrideMu.Lock()
+	defer overrideMu.Unlock()
+
+	flagFilePath = filePath
+}
+
+// FilePath 사용할 설정 파일 경로 반환 (명령행 플래그 > 환경 변수(WEBLIN_CONFIG) > 파일 배치 방식의 기본 경로)
+//
+// Returns:
+//   - string: 설정 파일 경로
+//   - bool: 설정 파일 경로를 지정함(true), 기본 경로(false)
+func FilePath() (string, bool) {
+	overrideMu.Lock()
+	defer overrideMu.Unlock()
+
+	if flagFilePath != "" {
+		return flagFilePath, true
+	}
+	if filePath := os.Getenv(EnvConfigFile); filePath != "" {
+		return filePath, true
+	}
+	return layout.ConfFile, false
+}
+
+// SetFlagValues 명령행 플래그로 지정한 설정 값 저장
+//
+// Parameters:
+//   - values: 설정 파일 항목 이름별 설정 값 (설정 파일 표기)
+func SetFlagValues(values map[string]string) {
+	overrideMu.Lock()
+	defer overrideMu.Unlock()
+
+	flagValues = values
+}
+
+// EnvName 설정 항목의 환경 변수 이름 반환 (예: EnableTLS -> WEBLIN_ENABLE_TLS)
+//
+// Returns:
+//   - string: 환경 변수 이름
+func (o *Option) EnvName() string {
+	return EnvPrefix + strings.ToUpper(strings.Join(splitWords(o.Key), "_"))
+}
+
+// FlagName 설정 항목의 명령행 플래그 이름 반환 (예: EnableTLS -> enable-tls)
+//
+// Returns:
+//   - string: 명령행 플래그 이름 (-- 제외)
+func (o *Option) FlagName() string {
+	return strings.ToLower(strings.Join(splitWords(o.Key), "-"))
+}
+
+// applyEnv 환경 변수(WEBLIN_*)로 지정한 설정 값을 설정 정보에 적용
+//
+// Returns:
+//   - []error: 항목별 오류 목록
+func (c *Config) applyEnv() []error {
+	var errs []error
+	for i := range Options {
+		opt := &Options[i]
+		value, exists := os.LookupEnv(opt.EnvName())
+		if !exists {
+			continue
+		}
+		if err := opt.Set(c, strings.TrimSpace(value)); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %s", SourceEnv, opt.EnvName(), err))
+			continue
+		}
+		c.setSource(opt.Key, fmt.Sprintf("%s (%s)", SourceEnv, opt.EnvName()))
+	}
+	return errs
+}
+
+// applyFlags 명령행 플래그로 지정한 설정 값을 설정 정보에 적용
+//
+// Returns:
+//   - []error: 항목별 오류 목록
+func (c *Config) applyFlags() []error {
+	overrideMu.Lock()
+	defer overrideMu.Unlock()
+
+	var errs []error
+	for i := range Options {
+		opt := &Options[i]
+		value, exists := flagValues[opt.Key]
+		if !exists {
+			continue
+		}
+		if err := opt.Set(c, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s --%s: %s", SourceFlag, opt.FlagName(), err))
+			continue
+		}
+		c.setSource(opt.Key, fmt.Sprintf("%s (--%s)", SourceFlag, opt.FlagName()))
+	}
+	return errs
+}
+
+// splitWords 설정 항목 이름을 단어 단위로 분리 (예: TLSCertFile -> TLS, Cert, File)
+//
+// Parameters:
+//   - key: 설정 파일 항목 이름 (CamelCase)
+//
+// Returns:
+//   - []string: 단어 목록
+func splitWords(key string) []string {
+	var words []string
+	runes := []rune(key)
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		// 소문자 다음 대문자, 또는 연속된 대문자(약어) 중 소문자 앞의 대문자에서 분리
+		if unicode.IsUpper(runes[i]) &&
+			(!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(runes[start:]))
+}
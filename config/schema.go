@@ -0,0 +1,265 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Kind 설정 값 유형
+type Kind int
+
+const (
+	// KindInt 정수 (MIN, MAX 범위 검사)
+	KindInt Kind = iota
+	// KindBool 사용 여부 (yes, no)
+	KindBool
+	// KindString 문자열 (빈 값 허용)
+	KindString
+)
+
+// Option 설정 항목 정의 구조체
+type Option struct {
+	// 설정 파일 항목 이름
+	Key string
+	// Config 구조체 필드 이름
+	Field string
+	// 설정 파일 구역 이름 (# [<Section> Configuration])
+	Section string
+	Kind    Kind
+	// 기본 값 (설정 파일 표기)
+	Default string
+	// 정수 값 범위 (KindInt)
+	Min int
+	Max int
+	// 허용 값 목록 (KindString, 비어 있을 경우 제한 없음, 소문자로 저장)
+	Values []string
+	// 설정 파일 주석 (설명, 기본 값 및 범위)
+	Desc string
+	// 비밀 값 여부 (설정 조회 시 값 숨김)
+	Secret bool
+}
+
+// Options 전체 설정 항목 정의 (설정 파일 작성 순서)
+var Options = []Option{
+	{Key: "MaxLogFileSize", Field: "MaxLogFileSize", Section: "Logs", Kind: KindInt, Default: "100", Min: 1, Max: 1000,
+		Desc: "Maximum size per log file (DEF:100MB, MIN:1MB, MAX:1000MB)"},
+	{Key: "MaxLogFileBackup", Field: "MaxLogFileBackup", Section: "Logs", Kind: KindInt, Default: "10", Min: 1, Max: 100,
+		Desc: "Maximum number of log file backups (DEF:10, MIN:1, MAX:100)"},
+	{Key: "MaxLogFileAge", Field: "MaxLogFileAge", Section: "Logs", Kind: KindInt, Default: "90", Min: 1, Max: 365,
+		Desc: "Number of days to keep backup log files (DEF:90, MIN:1, MAX:365)"},
+	{Key: "CompressBackupLogFile", Field: "CompBakLogFile", Section: "Logs", Kind: KindBool, Default: "yes",
+		Desc: "Whether backup log files are compressed (DEF:yes, ENABLE:yes, DISABLE:no)"},
+	{Key: "TextLogLevel", Field: "TextLogLevel", Section: "Logs", Kind: KindString, Default: "info", Values: LogLevels,
+		Desc: "Minimum level written to the text log file (DEF:info, VALUES:debug, info, warn, error)"},
+	{Key: "JsonLogLevel", Field: "JsonLogLevel", Section: "Logs", Kind: KindString, Default: "info", Values: LogLevels,
+		Desc: "Minimum level written to the JSON log file (DEF:info, VALUES:debug, info, warn, error)"},
+	{Key: "ConsoleLogLevel", Field: "ConsoleLogLevel", Section: "Logs", Kind: KindString, Default: "info", Values: LogLevels,
+		Desc: "Minimum level written to stderr in foreground mode (DEF:info, VALUES:debug, info, warn, error)"},
+
+	{Key: "ListenAddress", Field: "ListenAddress", Section: "HTTP Server", Kind: KindString, Default: "127.0.0.1",
+		Desc: "Listen address of the web server, a non-loopback address requires EnableTLS or AllowInsecureHTTP (DEF:127.0.0.1)"},
+	{Key: "ListenPort", Field: "ListenPort", Section: "HTTP Server", Kind: KindInt, Default: "8080", Min: 1, Max: 65535,
+		Desc: "Listen port of the web server (DEF:8080, MIN:1, MAX:65535)"},
+	{Key: "EnableTLS", Field: "EnableTLS", Section: "HTTP Server", Kind: KindBool, Default: "no",
+		Desc: "Whether HTTPS is used (DEF:no, ENABLE:yes, DISABLE:no)"},
+	{Key: "AllowInsecureHTTP", Field: "AllowInsecureHTTP", Section: "HTTP Server", Kind: KindBool, Default: "no",
+		Desc: "Whether plain HTTP is allowed on a non-loopback ListenAddress, passwords are sent in cleartext (DEF:no, ENABLE:yes, DISABLE:no)"},
+	{Key: "TLSCertFile", Field: "TLSCertFile", Section: "HTTP Server", Kind: KindString, Default: "conf/weblin.crt",
+		Desc: "HTTPS certificate file path (DEF:conf/weblin.crt)"},
+	{Key: "TLSKeyFile", Field: "TLSKeyFile", Section: "HTTP Server", Kind: KindString, Default: "conf/weblin.key",
+		Desc: "HTTPS private key file path (DEF:conf/weblin.key)"},
+	{Key: "ReadHeaderTimeout", Field: "ReadHeaderTimeout", Section: "HTTP Server", Kind: KindInt, Default: "10", Min: 1, Max: 600,
+		Desc: "Timeout for reading request headers in seconds (DEF:10, MIN:1, MAX:600)"},
+	{Key: "ReadTimeout", Field: "ReadTimeout", Section: "HTTP Server", Kind: KindInt, Default: "0", Min: 0, Max: 86400,
+		Desc: "Timeout for reading the entire request in seconds (DEF:0(unlimited), MIN:0, MAX:86400)"},
+	{Key: "WriteTimeout", Field: "WriteTimeout", Section: "HTTP Server", Kind: KindInt, Default: "0", Min: 0, Max: 86400,
+		Desc: "Timeout for writing the response in seconds (DEF:0(unlimited), MIN:0, MAX:86400)"},
+	{Key: "IdleTimeout", Field: "IdleTimeout", Section: "HTTP Server", Kind: KindInt, Default: "120", Min: 1, Max: 3600,
+		Desc: "Timeout for idle keep-alive connections in seconds (DEF:120, MIN:1, MAX:3600)"},
+	{Key: "ShutdownTimeout", Field: "ShutdownTimeout", Section: "HTTP Server", Kind: KindInt, Default: "30", Min: 1, Max: 600,
+		Desc: "Timeout for draining in-flight requests on shutdown in seconds (DEF:30, MIN:1, MAX:600)"},
+
+	{Key: "SessionTimeout", Field: "SessionTimeout", Section: "Session", Kind: KindInt, Default: "720", Min: 1, Max: 10080,
+		Desc: "Maximum lifetime of a login session in minutes (DEF:720, MIN:1, MAX:10080)"},
+	{Key: "SessionIdleTimeout", Field: "SessionIdleTimeout", Section: "Session", Kind: KindInt, Default: "30", Min: 1, Max: 1440,
+		Desc: "Idle timeout of a login session in minutes (DEF:30, MIN:1, MAX:1440)"},
+
+	{Key: "UploadTimeout", Field: "UploadTimeout", Section: "File Manager", Kind: KindInt, Default: "60", Min: 1, Max: 10080,
+		Desc: "Idle time after which an unfinished upload is discarded in minutes (DEF:60, MIN:1, MAX:10080)"},
+	{Key: "ExtractMaxSize", Field: "ExtractMaxSize", Section: "File Manager", Kind: KindInt, Default: "10240", Min: 1, Max: 1048576,
+		Desc: "Maximum total size of files extracted from one archive in MB (DEF:10240, MIN:1, MAX:1048576)"},
+	{Key: "ExtractMaxEntries", Field: "ExtractMaxEntries", Section: "File Manager", Kind: KindInt, Default: "100000", Min: 1, Max: 10000000,
+		Desc: "Maximum number of entries extracted from one archive (DEF:100000, MIN:1, MAX:10000000)"},
+	{Key: "EditorMaxFileSize", Field: "EditorMaxFileSize", Section: "File Manager", Kind: KindInt, Default: "5", Min: 1, Max: 100,
+		Desc: "Maximum size of a file opened in the text editor in MB (DEF:5, MIN:1, MAX:100)"},
+
+	{Key: "MetricsInterval", Field: "MetricsInterval", Section: "System Monitor", Kind: KindInt, Default: "5", Min: 1, Max: 3600,
+		Desc: "Interval for sampling system resource usage in seconds (DEF:5, MIN:1, MAX:3600)"},
+	{Key: "MetricsHistorySize", Field: "MetricsHistorySize", Section: "System Monitor", Kind: KindInt, Default: "720", Min: 1, Max: 100000,
+		Desc: "Number of samples kept in the resource usage history (DEF:720, MIN:1, MAX:100000)"},
+
+	{Key: "EnablePrometheus", Field: "EnablePrometheus", Section: "Prometheus", Kind: KindBool, Default: "yes",
+		Desc: "Whether weblin's own metrics are exposed on /metrics (DEF:yes, ENABLE:yes, DISABLE:no)"},
+	{Key: "PrometheusToken", Field: "PrometheusToken", Section: "Prometheus", Kind: KindString, Default: "",
+		Desc: "Bearer token required to scrape /metrics (DEF:none(no authentication))", Secret: true},
+}
+
+// LogLevels 로그 레벨 설정 항목의 허용 값 목록
+var LogLevels = []string{"debug", "info", "warn", "error"}
+
+// optionByKey 설정 파일 항목 이름별 설정 항목 정의
+var optionByKey = make(map[string]*Option, len(Options))
+
+// init 설정 항목 정의 검증 (정의 오류는 개발 단계에서 발견되도록 panic)
+func init() {
+	configType := reflect.TypeOf(Config{})
+	for i := range Options {
+		opt := &Options[i]
+		if _, exists := optionByKey[opt.Key]; exists {
+			panic(fmt.Sprintf("config: duplicate option %s", opt.Key))
+		}
+		if _, ok := configType.FieldByName(opt.Field); !ok {
+			panic(fmt.Sprintf("config: option %s refers to unknown field %s", opt.Key, opt.Field))
+		}
+		optionByKey[opt.Key] = opt
+	}
+}
+
+// LookupOption 설정 파일 항목 이름으로 설정 항목 정의 조회
+//
+// Parameters:
+//   - key: 설정 파일 항목 이름
+//
+// Returns:
+//   - *Option: 설정 항목 정의
+//   - bool: 존재(true), 미존재(false)
+func LookupOption(key string) (*Option, bool) {
+	opt, ok := optionByKey[key]
+	return opt, ok
+}
+
+// Set 설정 파일 표기의 값을 검증하여 설정 정보에 적용
+//
+// Parameters:
+//   - c: 설정 정보
+//   - value: 설정 값 (설정 파일 표기)
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func (o *Option) Set(c *Config, value string) error {
+	field := reflect.ValueOf(c).Elem().FieldByName(o.Field)
+
+	switch o.Kind {
+	case KindInt:
+		if value == "" {
+			return fmt.Errorf("missing value")
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		if n < o.Min || n > o.Max {
+			return fmt.Errorf("value %d out of range (MIN:%d, MAX:%d)", n, o.Min, o.Max)
+		}
+		field.SetInt(int64(n))
+	case KindBool:
+		switch strings.ToLower(value) {
+		case "yes":
+			field.SetBool(true)
+		case "no":
+			field.SetBool(false)
+		case "":
+			return fmt.Errorf("missing value")
+		default:
+			return fmt.Errorf("invalid value %q (expected yes or no)", value)
+		}
+	case KindString:
+		if len(o.Values) > 0 {
+			value = strings.ToLower(value)
+			if value == "" {
+				return fmt.Errorf("missing value")
+			}
+			if !slices.Contains(o.Values, value) {
+				return fmt.Errorf("invalid value %q (expected %s or %s)", value,
+					strings.Join(o.Values[:len(o.Values)-1], ", "), o.Values[len(o.Values)-1])
+			}
+		}
+		field.SetString(value)
+	}
+
+	return nil
+}
+
+// Get 설정 정보의 값을 설정 파일 표기로 반환
+//
+// Parameters:
+//   - c: 설정 정보
+//
+// Returns:
+//   - string: 설정 값 (설정 파일 표기)
+func (o *Option) Get(c *Config) string {
+	field := reflect.ValueOf(c).Elem().FieldByName(o.Field)
+
+	switch o.Kind {
+	case KindInt:
+		return strconv.FormatInt(field.Int(), 10)
+	case KindBool:
+		if field.Bool() {
+			return "yes"
+		}
+		return "no"
+	default:
+		return field.String()
+	}
+}
+
+// WriteDefaults 전체 설정 항목을 기본 값으로 주석 처리한 설정 파일 작성
+//
+// Parameters:
+//   - w: 설정 파일 writer
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func WriteDefaults(w io.Writer) error {
+	section := ""
+	for i := range Options {
+		opt := &Options[i]
+		if opt.Section != section {
+			if section != "" {
+				if _, err := fmt.Fprintln(w); err != nil {
+					return err
+				}
+			}
+			section = opt.Section
+			if _, err := fmt.Fprintf(w, "# [%s Configuration]\n", section); err != nil {
+				return err
+			}
+		}
+
+		line := strings.TrimSpace(opt.Key + " " + opt.Default)
+		if _, err := fmt.Fprintf(w, "# %s\n#%s\n", opt.Desc, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
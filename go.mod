@@ -3,6 +3,8 @@ module github.com/hoon-kr/weblin
 go 1.21.13
 
 require (
+	github.com/creack/pty v1.1.24
+	github.com/gorilla/websocket v1.5.3
 	github.com/spf13/cobra v1.8.1
 	go.uber.org/automaxprocs v1.6.0
 	go.uber.org/zap v1.27.0
@@ -0,0 +1,200 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package audit
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// 해시 체인 키 크기 (바이트)
+const keySize = 32
+
+// Anchor 감사 로그 마지막 항목 정보 구조체
+//
+// 감사 로그와 다른 디렉터리에 항목을 기록할 때마다 갱신하여, 감사 로그 끝부분의 항목이
+// 삭제된 경우 검출할 수 있도록 한다.
+type Anchor struct {
+	Seq  uint64
+	Hash string
+}
+
+// LoadKey 해시 체인 키 파일 읽기
+//
+// Parameters:
+//   - path: 키 파일 경로
+//   - create: 키 파일이 없을 경우 새로운 키 생성
+//
+// Returns:
+//   - []byte: 해시 체인 키
+//   - error: 성공(nil), 실패(error)
+func LoadKey(path string, create bool) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && create {
+		return createKey(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit key: %s", err)
+	}
+
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != keySize {
+		return nil, fmt.Errorf("invalid audit key (%s)", path)
+	}
+	return key, nil
+}
+
+// createKey 새로운 해시 체인 키를 생성하여 키 파일에 기록
+//
+// Parameters:
+//   - path: 키 파일 경로
+//
+// Returns:
+//   - []byte: 해시 체인 키
+//   - error: 성공(nil), 실패(error)
+func createKey(path string) ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate audit key: %s", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to make directory: %s", err)
+	}
+	// 서버 실행 계정만 읽을 수 있으며, 이미 존재하는 키는 덮어쓰지 않음
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit key: %s", err)
+	}
+	_, err = file.WriteString(hex.EncodeToString(key) + "\n")
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("failed to write audit key: %s", err)
+	}
+	return key, nil
+}
+
+// ReadAnchor 마지막 항목 기록 파일 읽기
+//
+// Parameters:
+//   - path: 마지막 항목 기록 파일 경로
+//
+// Returns:
+//   - *Anchor: 마지막 항목 정보 (파일이 없거나 기록된 항목이 없을 경우 일련 번호 0)
+//   - error: 성공(nil), 실패(error)
+func ReadAnchor(path string) (*Anchor, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Anchor{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit anchor: %s", err)
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return &Anchor{}, nil
+	}
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("invalid audit anchor (%s)", path)
+	}
+	seq, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audit anchor (%s)", path)
+	}
+	return &Anchor{Seq: seq, Hash: fields[1]}, nil
+}
+
+// openAnchor 마지막 항목 기록 파일 열기
+//
+// Parameters:
+//   - path: 마지막 항목 기록 파일 경로
+//
+// Returns:
+//   - *os.File
+//   - error: 성공(nil), 실패(error)
+func openAnchor(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to make directory: %s", err)
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit anchor: %s", err)
+	}
+	return file, nil
+}
+
+// writeAnchor 마지막 항목 정보를 기록 파일에 덮어쓰기
+//
+// 항상 같은 길이로 기록하므로 이전 내용이 남지 않는다.
+//
+// Parameters:
+//   - file: 마지막 항목 기록 파일
+//   - anchor: 마지막 항목 정보
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func writeAnchor(file *os.File, anchor *Anchor) error {
+	line := fmt.Sprintf("%020d %s\n", anchor.Seq, anchor.Hash)
+	if _, err := file.WriteAt([]byte(line), 0); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// checkAnchor 감사 로그의 마지막 항목이 기록 파일의 항목과 일치하는지 확인
+//
+// 기록 파일은 감사 로그 기록 후 갱신하므로, 감사 로그에 기록 파일보다 이후의 항목이 있는 것은 허용한다.
+//
+// Parameters:
+//   - anchor: 기록 파일의 마지막 항목 정보
+//   - last: 감사 로그의 마지막 항목 (nil일 경우 항목 없음)
+//   - anchorHash: 감사 로그에서 기록 파일의 일련 번호에 해당하는 항목의 해시 (없을 경우 빈 문자열)
+//
+// Returns:
+//   - error: 일치(nil), 불일치(error)
+func checkAnchor(anchor *Anchor, last *Entry, anchorHash string) error {
+	lastSeq := uint64(0)
+	if last != nil {
+		lastSeq = last.Seq
+	}
+
+	switch {
+	case anchor.Seq == 0 && lastSeq == 0:
+		return nil
+	case anchor.Seq == 0:
+		return fmt.Errorf("audit anchor is missing but the audit log has entries up to seq %d", lastSeq)
+	case lastSeq < anchor.Seq:
+		return fmt.Errorf("audit log ends at seq %d but the anchor records seq %d (trailing entries removed)",
+			lastSeq, anchor.Seq)
+	case anchorHash != anchor.Hash:
+		return fmt.Errorf("entry seq %d does not match the audit anchor", anchor.Seq)
+	}
+	return nil
+}
@@ -0,0 +1,482 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+/*
+Package audit 감사 로그 패키지
+
+웹에서 수행한 사용자 작업(로그인, 파일 작업, 권한 변경, 프로세스 종료, 웹 터미널 등)을
+일반 로그와 별도의 파일에 JSON 한 줄씩 기록한다. 각 항목은 이전 항목의 해시를 포함하는
+해시 체인을 이루므로 항목의 삭제나 수정은 Verify로 검출할 수 있다.
+
+해시는 감사 로그와 다른 디렉터리에 보관하는 키로 계산한 HMAC-SHA256이므로 키 없이 항목을
+수정하고 해시를 다시 계산할 수 없으며, 마지막 항목의 일련 번호와 해시는 항목을 기록할 때마다
+별도의 파일(Anchor)에 갱신하여 끝부분 항목의 삭제도 검출한다.
+*/
+package audit
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hoon-kr/weblin/internal/logger"
+	"github.com/hoon-kr/weblin/internal/web"
+)
+
+// 작업 결과 정의
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// 감사 작업 정의 (파일, 프로세스 작업은 헬퍼 프로세스 요청 이름(fs.*, proc.*)을 사용)
+const (
+	// ActionLogin 로그인 (실패 포함)
+	ActionLogin = "auth.login"
+	// ActionLogout 로그아웃
+	ActionLogout = "auth.logout"
+	// ActionSessionRevoke 다른 로그인 세션 폐기
+	ActionSessionRevoke = "auth.revoke"
+	// ActionTerminalStart 웹 터미널 세션 시작
+	ActionTerminalStart = "terminal.start"
+	// ActionTerminalStop 웹 터미널 세션 종료
+	ActionTerminalStop = "terminal.stop"
+	// ActionAuditOpen 서버 가동으로 감사 로그 기록 시작
+	ActionAuditOpen = "audit.open"
+	// ActionAuditClose 서버 정지로 감사 로그 기록 종료
+	ActionAuditClose = "audit.close"
+)
+
+// genesisHash 첫 번째 항목의 이전 해시
+var genesisHash = strings.Repeat("0", sha256.Size*2)
+
+// Entry 감사 로그 항목 구조체
+type Entry struct {
+	// 일련 번호 (1부터 연속)
+	Seq uint64 `json:"seq"`
+	// 기록 시각 (RFC3339Nano, UTC, 해시 재계산 시 같은 표기를 유지하도록 문자열로 저장)
+	Time      string `json:"time"`
+	Action    string `json:"action"`
+	Result    string `json:"result"`
+	User      string `json:"user,omitempty"`
+	Remote    string `json:"remote,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+	// 작업 대상 (경로, PID, 세션 ID 등)
+	Target string `json:"target,omitempty"`
+	// 작업 상세 내용
+	Detail string `json:"detail,omitempty"`
+	// 실패 사유
+	Error string `json:"error,omitempty"`
+	// 이전 항목의 해시 (첫 번째 항목은 0으로 채운 값)
+	Prev string `json:"prev"`
+	// 현재 항목의 해시 (Hash를 제외한 JSON의 HMAC-SHA256)
+	Hash string `json:"hash,omitempty"`
+}
+
+// Logger 감사 로그 기록 관리 정보 구조체
+type Logger struct {
+	mu     sync.Mutex
+	file   *os.File
+	anchor *os.File
+	key    []byte
+	seq    uint64
+	prev   string
+}
+
+// Log 서버 전체 감사 로그 (Open 전에는 기록하지 않음)
+var Log = &Logger{}
+
+// NewEntry 웹 요청 정보로 감사 로그 항목 생성
+//
+// Parameters:
+//   - r: 요청 정보
+//   - user: 작업을 요청한 로그인 계정 이름
+//   - action: 작업 이름
+//   - target: 작업 대상
+//
+// Returns:
+//   - *Entry
+func NewEntry(r *http.Request, user, action, target string) *Entry {
+	return &Entry{
+		Action:    action,
+		User:      user,
+		Remote:    r.RemoteAddr,
+		RequestID: web.RequestID(r),
+		Target:    target,
+	}
+}
+
+// Record 웹 요청으로 수행한 작업을 서버 전체 감사 로그에 기록
+//
+// Parameters:
+//   - r: 요청 정보
+//   - user: 작업을 요청한 로그인 계정 이름
+//   - action: 작업 이름 (헬퍼 프로세스 요청 이름)
+//   - target: 작업 대상 (경로, PID 등)
+//   - detail: 작업 상세 내용
+//   - err: 작업 실패 사유 (성공일 경우 nil)
+func Record(r *http.Request, user, action, target, detail string, err error) {
+	entry := NewEntry(r, user, action, target)
+	entry.Detail = detail
+	Log.Record(entry, err)
+}
+
+// Open 감사 로그 파일을 열고 마지막 항목에 이어서 기록할 준비
+//
+// 감사 로그의 마지막 항목이 기록 파일(Anchor)과 일치하지 않으면 삭제된 항목이 있는 것이므로
+// 이어서 기록하지 않는다 (이어서 기록하면 기록 파일이 갱신되어 삭제 흔적이 사라짐).
+//
+// Parameters:
+//   - path: 감사 로그 파일 경로
+//   - keyPath: 해시 체인 키 파일 경로 (없을 경우 생성)
+//   - anchorPath: 마지막 항목 기록 파일 경로
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func (l *Logger) Open(path, keyPath, anchorPath string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file != nil {
+		return fmt.Errorf("audit log is already open")
+	}
+
+	anchor, err := ReadAnchor(anchorPath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to make directory: %s", err)
+	}
+	// 감사 로그는 서버 실행 계정만 읽고 쓸 수 있음
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %s", err)
+	}
+
+	last, err := lastEntry(file)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to read audit log (%s): %s (check with 'audit verify')", path, err)
+	}
+
+	// 기록 파일은 감사 로그 기록 후 갱신하므로 마지막 항목보다 하나 앞설 수 있음 (기록 중 비정상 종료)
+	anchorHash := ""
+	if last != nil && last.Seq == anchor.Seq {
+		anchorHash = last.Hash
+	} else if last != nil && last.Seq == anchor.Seq+1 {
+		anchorHash = last.Prev
+	}
+	if err := checkAnchor(anchor, last, anchorHash); err != nil {
+		file.Close()
+		return fmt.Errorf("%s: %s (check with 'audit verify', and move both the audit log and %s away to start a new log)",
+			path, err, anchorPath)
+	}
+
+	// 키는 기록된 항목이 없을 때만 생성 (키 파일이 삭제된 경우 새로운 키로 이어서 기록하지 않음)
+	key, err := LoadKey(keyPath, last == nil && anchor.Seq == 0)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if last != nil {
+		if err := checkHash(last, key); err != nil {
+			file.Close()
+			return fmt.Errorf("%s: %s (check with 'audit verify')", path, err)
+		}
+	}
+
+	anchorFile, err := openAnchor(anchorPath)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	l.file = file
+	l.anchor = anchorFile
+	l.key = key
+	l.seq = 0
+	l.prev = genesisHash
+	if last != nil {
+		l.seq = last.Seq
+		l.prev = last.Hash
+	}
+
+	l.write(&Entry{Action: ActionAuditOpen, Detail: fmt.Sprintf("pid:%d", os.Getpid())}, nil)
+	return nil
+}
+
+// Close 감사 로그 기록 종료
+//
+// 마지막 항목의 일련 번호와 해시는 기록 파일(Anchor)과 함께 일반 로그에도 기록한다.
+func (l *Logger) Close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return
+	}
+
+	l.write(&Entry{Action: ActionAuditClose, Detail: fmt.Sprintf("pid:%d", os.Getpid())}, nil)
+	l.file.Close()
+	l.file = nil
+	l.anchor.Close()
+	l.anchor = nil
+	logger.Log.LogInfo("Audit log closed (seq:%d, hash:%s)", l.seq, l.prev)
+}
+
+// Record 감사 로그 항목 기록 (일련 번호, 시각, 해시는 자동으로 설정)
+//
+// Parameters:
+//   - e: 감사 로그 항목
+//   - err: 작업 실패 사유 (성공일 경우 nil)
+func (l *Logger) Record(e *Entry, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return
+	}
+	l.write(e, err)
+}
+
+// write 감사 로그 항목을 해시 체인에 연결하여 파일에 기록 (l.mu 잠금 상태에서 호출)
+//
+// Parameters:
+//   - e: 감사 로그 항목
+//   - err: 작업 실패 사유 (성공일 경우 nil)
+func (l *Logger) write(e *Entry, err error) {
+	e.Seq = l.seq + 1
+	e.Time = time.Now().UTC().Format(time.RFC3339Nano)
+	e.Result = ResultSuccess
+	if err != nil {
+		e.Result = ResultFailure
+		e.Error = err.Error()
+	}
+	e.Prev = l.prev
+	e.Hash = ""
+
+	hash, line, werr := marshalEntry(e, l.key)
+	if werr == nil {
+		// 한 번의 write로 기록하여 항목이 다른 항목과 섞이지 않도록 함
+		if _, werr = l.file.Write(line); werr == nil {
+			werr = l.file.Sync()
+		}
+	}
+	if werr != nil {
+		logger.Log.LogError("Failed to write audit log (action:%s, user:%s, target:%s): %s",
+			e.Action, e.User, e.Target, werr)
+		return
+	}
+
+	l.seq = e.Seq
+	l.prev = hash
+
+	if werr := writeAnchor(l.anchor, &Anchor{Seq: l.seq, Hash: l.prev}); werr != nil {
+		logger.Log.LogError("Failed to write audit anchor (seq:%d): %s", l.seq, werr)
+	}
+}
+
+// VerifyResult 감사 로그 검증 결과 구조체
+type VerifyResult struct {
+	// 검증한 항목 수
+	Entries int
+	// 마지막 항목
+	Last *Entry
+	// 줄 번호별 검증 오류 목록
+	Errs []error
+}
+
+// Verify 감사 로그의 해시 체인 검증
+//
+// 항목의 수정(해시 불일치), 중간 항목의 삭제 및 순서 변경(일련 번호, 이전 해시 불일치)을 검출하며,
+// 마지막 항목 기록 파일(Anchor)과 대조하여 끝부분 항목의 삭제를 검출한다.
+//
+// Parameters:
+//   - r: 감사 로그 reader
+//   - key: 해시 체인 키
+//   - anchor: 마지막 항목 정보 (nil일 경우 대조하지 않음)
+//
+// Returns:
+//   - *VerifyResult: 검증 결과
+//   - error: 성공(nil), 읽기 실패(error)
+func Verify(r io.Reader, key []byte, anchor *Anchor) (*VerifyResult, error) {
+	result := &VerifyResult{}
+	expectSeq := uint64(1)
+	expectPrev := genesisHash
+	anchorHash := ""
+
+	reader := bufio.NewReader(r)
+	for lineNum := 1; ; lineNum++ {
+		line, err := reader.ReadBytes('\n')
+		if len(line) == 0 && errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to read audit log: %s", err)
+		}
+
+		var e Entry
+		if jerr := json.Unmarshal(line, &e); jerr != nil {
+			result.Errs = append(result.Errs, fmt.Errorf("line %d: invalid entry: %s", lineNum, jerr))
+			// 다음 항목은 이전 항목을 알 수 없으므로 일련 번호, 이전 해시를 확인하지 않음
+			expectSeq, expectPrev = 0, ""
+			continue
+		}
+		result.Entries++
+
+		var problems []string
+		if expectSeq != 0 && e.Seq != expectSeq {
+			problems = append(problems, fmt.Sprintf("sequence %d, expected %d", e.Seq, expectSeq))
+		}
+		if expectPrev != "" && e.Prev != expectPrev {
+			problems = append(problems, "previous hash mismatch")
+		}
+		if checkHash(&e, key) != nil {
+			problems = append(problems, "hash mismatch")
+		}
+
+		if len(problems) > 0 {
+			result.Errs = append(result.Errs, fmt.Errorf("line %d (seq:%d): %s (entry modified, or entries deleted or reordered before it)",
+				lineNum, e.Seq, strings.Join(problems, ", ")))
+		}
+
+		// 다음 항목은 현재 항목에 이어서 검증 (하나의 변경이 이후 모든 항목의 오류로 보고되지 않도록)
+		expectSeq = e.Seq + 1
+		expectPrev = e.Hash
+		entry := e
+		result.Last = &entry
+		if anchor != nil && e.Seq == anchor.Seq {
+			anchorHash = e.Hash
+		}
+	}
+
+	if anchor != nil {
+		if err := checkAnchor(anchor, result.Last, anchorHash); err != nil {
+			result.Errs = append(result.Errs, err)
+		}
+	}
+	return result, nil
+}
+
+// checkHash 감사 로그 항목의 해시 확인
+//
+// Parameters:
+//   - e: 감사 로그 항목
+//   - key: 해시 체인 키
+//
+// Returns:
+//   - error: 일치(nil), 불일치(error)
+func checkHash(e *Entry, key []byte) error {
+	unsigned := *e
+	unsigned.Hash = ""
+	hash, _, err := marshalEntry(&unsigned, key)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(hash), []byte(e.Hash)) {
+		return fmt.Errorf("hash mismatch of entry seq %d", e.Seq)
+	}
+	return nil
+}
+
+// marshalEntry 감사 로그 항목의 해시를 계산하고 기록할 한 줄 생성
+//
+// Parameters:
+//   - e: 감사 로그 항목 (Hash는 빈 값이어야 함)
+//   - key: 해시 체인 키
+//
+// Returns:
+//   - string: 항목 해시
+//   - []byte: 해시를 포함한 JSON 한 줄 (줄바꿈 포함)
+//   - error: 성공(nil), 실패(error)
+func marshalEntry(e *Entry, key []byte) (string, []byte, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode audit entry: %s", err)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	hash := hex.EncodeToString(mac.Sum(nil))
+
+	signed := *e
+	signed.Hash = hash
+	line, err := json.Marshal(&signed)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode audit entry: %s", err)
+	}
+	return hash, append(line, '\n'), nil
+}
+
+// lastEntry 감사 로그 파일의 마지막 항목 반환
+//
+// Parameters:
+//   - file: 감사 로그 파일
+//
+// Returns:
+//   - *Entry: 마지막 항목 (빈 파일일 경우 nil)
+//   - error: 성공(nil), 실패(error)
+func lastEntry(file *os.File) (*Entry, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	// 파일 끝에서부터 블록 단위로 읽어 마지막 줄 탐색
+	const blockSize = 4096
+	end := info.Size()
+	var tail []byte
+	for offset := end; offset > 0; {
+		size := int64(blockSize)
+		if offset < size {
+			size = offset
+		}
+		offset -= size
+
+		block := make([]byte, size)
+		if _, err := file.ReadAt(block, offset); err != nil {
+			return nil, err
+		}
+		tail = append(block, tail...)
+
+		trimmed := strings.TrimRight(string(tail), "\n")
+		if idx := strings.LastIndexByte(trimmed, '\n'); idx >= 0 || offset == 0 {
+			line := trimmed[idx+1:]
+			if line == "" {
+				return nil, nil
+			}
+			var e Entry
+			if err := json.Unmarshal([]byte(line), &e); err != nil {
+				return nil, fmt.Errorf("invalid last entry: %s", err)
+			}
+			return &e, nil
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,483 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package audit
+
+import (
+	"bytes"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testLog 테스트용 감사 로그 파일 경로 구조체
+type testLog struct {
+	path       string
+	keyPath    string
+	anchorPath string
+}
+
+// newTestLog 임시 디렉터리에 감사 로그를 열고 항목을 기록한 뒤 닫기
+//
+// 감사 로그, 키, 기록 파일은 서로 다른 디렉터리에 두며, 가동 시작 항목을 포함하여 count+1개의 항목이 기록된다.
+//
+// Parameters:
+//   - t: 테스트 정보
+//   - count: 기록할 항목 수
+//
+// Returns:
+//   - *testLog
+func newTestLog(t *testing.T, count int) *testLog {
+	t.Helper()
+	dir := t.TempDir()
+	tl := &testLog{
+		path:       filepath.Join(dir, "log", "audit.log"),
+		keyPath:    filepath.Join(dir, "key", "audit.key"),
+		anchorPath: filepath.Join(dir, "anchor", "audit.anchor"),
+	}
+	tl.append(t, count)
+	return tl
+}
+
+// append 감사 로그를 열어 항목을 이어서 기록한 뒤 닫기 (가동 시작 항목 포함 count+1개)
+//
+// Parameters:
+//   - t: 테스트 정보
+//   - count: 기록할 항목 수
+func (tl *testLog) append(t *testing.T, count int) {
+	t.Helper()
+	l := &Logger{}
+	if err := l.Open(tl.path, tl.keyPath, tl.anchorPath); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	r := httptest.NewRequest("POST", "/api/fs/delete", nil)
+	for i := 1; i <= count; i++ {
+		l.Record(NewEntry(r, "alice", "fs.delete", fmt.Sprintf("/data/file%d", i)), nil)
+	}
+	closeTestLogger(l)
+}
+
+// closeTestLogger 종료 항목 없이 감사 로그 파일 닫기 (Close는 초기화되지 않은 일반 로그에 기록하므로 사용하지 않음)
+//
+// Parameters:
+//   - l: 감사 로그
+func closeTestLogger(l *Logger) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.file.Close()
+	l.file = nil
+	l.anchor.Close()
+	l.anchor = nil
+}
+
+// lines 감사 로그 파일의 항목 줄 목록
+//
+// Parameters:
+//   - t: 테스트 정보
+//
+// Returns:
+//   - []string
+func (tl *testLog) lines(t *testing.T) []string {
+	t.Helper()
+	data, err := os.ReadFile(tl.path)
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	return strings.SplitAfter(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+// writeLines 감사 로그 파일을 주어진 줄 목록으로 덮어쓰기
+//
+// Parameters:
+//   - t: 테스트 정보
+//   - lines: 항목 줄 목록
+func (tl *testLog) writeLines(t *testing.T, lines []string) {
+	t.Helper()
+	data := strings.Join(lines, "")
+	if !strings.HasSuffix(data, "\n") {
+		data += "\n"
+	}
+	if err := os.WriteFile(tl.path, []byte(data), 0600); err != nil {
+		t.Fatalf("failed to write audit log: %v", err)
+	}
+}
+
+// verify 키와 기록 파일로 감사 로그 검증
+//
+// Parameters:
+//   - t: 테스트 정보
+//
+// Returns:
+//   - *VerifyResult
+func (tl *testLog) verify(t *testing.T) *VerifyResult {
+	t.Helper()
+	key, err := LoadKey(tl.keyPath, false)
+	if err != nil {
+		t.Fatalf("LoadKey() error = %v", err)
+	}
+	anchor, err := ReadAnchor(tl.anchorPath)
+	if err != nil {
+		t.Fatalf("ReadAnchor() error = %v", err)
+	}
+	data, err := os.ReadFile(tl.path)
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	result, err := Verify(bytes.NewReader(data), key, anchor)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	return result
+}
+
+func TestVerifyIntact(t *testing.T) {
+	tl := newTestLog(t, 4)
+	result := tl.verify(t)
+	if len(result.Errs) != 0 {
+		t.Fatalf("Verify() errors = %v, want none", result.Errs)
+	}
+	if result.Entries != 5 || result.Last == nil || result.Last.Seq != 5 {
+		t.Fatalf("Verify() = %d entries (last %+v), want 5", result.Entries, result.Last)
+	}
+	if result.Last.Target != "/data/file4" || result.Last.User != "alice" || result.Last.Result != ResultSuccess {
+		t.Errorf("last entry = %+v", result.Last)
+	}
+}
+
+func TestVerifyTampered(t *testing.T) {
+	tests := []struct {
+		name string
+		// 감사 로그 줄 목록 변경 (줄 1은 가동 시작 항목, 줄 2~5는 /data/file1~4)
+		tamper func(lines []string) []string
+		// 검증 오류에 포함되어야 하는 내용
+		want string
+	}{
+		{
+			name: "modified entry",
+			tamper: func(lines []string) []string {
+				lines[2] = strings.Replace(lines[2], "/data/file2", "/data/other", 1)
+				return lines
+			},
+			want: "line 3 (seq:3): hash mismatch",
+		},
+		{
+			name: "modified result",
+			tamper: func(lines []string) []string {
+				lines[1] = strings.Replace(lines[1], `"result":"success"`, `"result":"failure"`, 1)
+				return lines
+			},
+			want: "line 2 (seq:2): hash mismatch",
+		},
+		{
+			name: "deleted middle entry",
+			tamper: func(lines []string) []string {
+				return append(lines[:2], lines[3:]...)
+			},
+			want: "line 3 (seq:4): sequence 4, expected 3, previous hash mismatch",
+		},
+		{
+			name: "reordered entries",
+			tamper: func(lines []string) []string {
+				lines[2], lines[3] = lines[3], lines[2]
+				return lines
+			},
+			want: "line 3 (seq:4): sequence 4, expected 3, previous hash mismatch",
+		},
+		{
+			name: "truncated trailing entries",
+			tamper: func(lines []string) []string {
+				return lines[:3]
+			},
+			want: "audit log ends at seq 3 but the anchor records seq 5",
+		},
+		{
+			name: "replaced last entry",
+			tamper: func(lines []string) []string {
+				// 중간 항목을 삭제하고 끝부분 항목을 복사해 일련 번호를 맞춰도 해시로 검출됨
+				lines[4] = strings.Replace(lines[3], `"seq":4`, `"seq":5`, 1)
+				return lines
+			},
+			want: "line 5 (seq:5): previous hash mismatch, hash mismatch",
+		},
+		{
+			name: "invalid line",
+			tamper: func(lines []string) []string {
+				lines[1] = "not json\n"
+				return lines
+			},
+			want: "line 2: invalid entry",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestLog(t, 4)
+			tl.writeLines(t, tt.tamper(tl.lines(t)))
+
+			result := tl.verify(t)
+			if !containsError(result.Errs, tt.want) {
+				t.Fatalf("Verify() errors = %v, want %q", result.Errs, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifySwappedKey(t *testing.T) {
+	tl := newTestLog(t, 2)
+	if err := os.Remove(tl.keyPath); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadKey(tl.keyPath, true); err != nil {
+		t.Fatalf("LoadKey() error = %v", err)
+	}
+
+	result := tl.verify(t)
+	if len(result.Errs) != 3 {
+		t.Fatalf("Verify() errors = %v, want a hash mismatch for every entry", result.Errs)
+	}
+	for _, err := range result.Errs {
+		if !strings.Contains(err.Error(), "hash mismatch") {
+			t.Errorf("Verify() error = %v, want hash mismatch", err)
+		}
+	}
+}
+
+func TestVerifyAnchor(t *testing.T) {
+	tests := []struct {
+		name   string
+		anchor func(last *Entry) *Anchor
+		want   string
+	}{
+		{"last entry", func(last *Entry) *Anchor { return &Anchor{Seq: last.Seq, Hash: last.Hash} }, ""},
+		{"one entry behind", func(last *Entry) *Anchor { return &Anchor{Seq: last.Seq - 1, Hash: last.Prev} }, ""},
+		{"hash mismatch", func(last *Entry) *Anchor { return &Anchor{Seq: last.Seq, Hash: last.Prev} },
+			"entry seq 3 does not match the audit anchor"},
+		{"missing", func(last *Entry) *Anchor { return &Anchor{} },
+			"audit anchor is missing but the audit log has entries up to seq 3"},
+		{"ahead of log", func(last *Entry) *Anchor { return &Anchor{Seq: last.Seq + 1, Hash: last.Hash} },
+			"audit log ends at seq 3 but the anchor records seq 4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestLog(t, 2)
+			key, err := LoadKey(tl.keyPath, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(tl.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := Verify(bytes.NewReader(data), key, nil)
+			if err != nil || len(result.Errs) != 0 {
+				t.Fatalf("Verify() without anchor = %v, %v", result, err)
+			}
+
+			result, err = Verify(bytes.NewReader(data), key, tt.anchor(result.Last))
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if tt.want == "" && len(result.Errs) != 0 {
+				t.Fatalf("Verify() errors = %v, want none", result.Errs)
+			}
+			if tt.want != "" && !containsError(result.Errs, tt.want) {
+				t.Fatalf("Verify() errors = %v, want %q", result.Errs, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenRefusesTamperedLog(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(t *testing.T, tl *testLog)
+		want   string
+	}{
+		{
+			name: "truncated trailing entries",
+			tamper: func(t *testing.T, tl *testLog) {
+				tl.writeLines(t, tl.lines(t)[:3])
+			},
+			want: "trailing entries removed",
+		},
+		{
+			name: "modified last entry",
+			tamper: func(t *testing.T, tl *testLog) {
+				lines := tl.lines(t)
+				lines[4] = strings.Replace(lines[4], "/data/file4", "/data/other", 1)
+				tl.writeLines(t, lines)
+			},
+			want: "hash mismatch of entry seq 5",
+		},
+		{
+			name: "anchor removed",
+			tamper: func(t *testing.T, tl *testLog) {
+				if err := os.Remove(tl.anchorPath); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: "audit anchor is missing",
+		},
+		{
+			name: "anchor hash mismatch",
+			tamper: func(t *testing.T, tl *testLog) {
+				anchor, err := ReadAnchor(tl.anchorPath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				line := fmt.Sprintf("%020d %s\n", anchor.Seq, genesisHash)
+				if err := os.WriteFile(tl.anchorPath, []byte(line), 0600); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: "entry seq 5 does not match the audit anchor",
+		},
+		{
+			name: "key removed",
+			tamper: func(t *testing.T, tl *testLog) {
+				if err := os.Remove(tl.keyPath); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: "failed to read audit key",
+		},
+		{
+			name: "key swapped",
+			tamper: func(t *testing.T, tl *testLog) {
+				if err := os.Remove(tl.keyPath); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := LoadKey(tl.keyPath, true); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: "hash mismatch of entry seq 5",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestLog(t, 4)
+			tt.tamper(t, tl)
+			before, err := os.ReadFile(tl.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			l := &Logger{}
+			err = l.Open(tl.path, tl.keyPath, tl.anchorPath)
+			if err == nil {
+				closeTestLogger(l)
+				t.Fatalf("Open() error = nil, want %q", tt.want)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Open() error = %v, want %q", err, tt.want)
+			}
+
+			// 거부한 경우 감사 로그에 기록하지 않고, 키를 새로 만들지 않음
+			after, err := os.ReadFile(tl.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(before, after) {
+				t.Errorf("Open() modified the audit log")
+			}
+			if tt.name == "key removed" {
+				if _, err := os.Stat(tl.keyPath); !os.IsNotExist(err) {
+					t.Errorf("Open() created a new key: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenContinuesChain(t *testing.T) {
+	tl := newTestLog(t, 2)
+	tl.append(t, 2)
+
+	result := tl.verify(t)
+	if len(result.Errs) != 0 || result.Entries != 6 {
+		t.Fatalf("Verify() = %d entries, errors %v, want 6 entries without errors", result.Entries, result.Errs)
+	}
+}
+
+func TestOpenAnchorOneBehind(t *testing.T) {
+	tl := newTestLog(t, 2)
+
+	// 항목을 기록한 뒤 기록 파일을 갱신하기 전에 비정상 종료한 경우
+	lines := tl.lines(t)
+	key, err := LoadKey(tl.keyPath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := Verify(strings.NewReader(strings.Join(lines, "")), key, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := fmt.Sprintf("%020d %s\n", result.Last.Seq-1, result.Last.Prev)
+	if err := os.WriteFile(tl.anchorPath, []byte(line), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tl.append(t, 1)
+	result = tl.verify(t)
+	if len(result.Errs) != 0 || result.Entries != 5 {
+		t.Fatalf("Verify() = %d entries, errors %v, want 5 entries without errors", result.Entries, result.Errs)
+	}
+}
+
+func TestOpenNewLog(t *testing.T) {
+	dir := t.TempDir()
+	tl := &testLog{
+		path:       filepath.Join(dir, "audit.log"),
+		keyPath:    filepath.Join(dir, "keys", "audit.key"),
+		anchorPath: filepath.Join(dir, "audit.anchor"),
+	}
+	tl.append(t, 0)
+
+	// 키와 감사 로그는 서버 실행 계정만 접근 가능
+	for _, path := range []string{tl.path, tl.keyPath, tl.anchorPath} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("%s mode = %v, want 0600", filepath.Base(path), info.Mode().Perm())
+		}
+	}
+	result := tl.verify(t)
+	if len(result.Errs) != 0 || result.Entries != 1 || result.Last.Action != ActionAuditOpen {
+		t.Fatalf("Verify() = %+v", result)
+	}
+}
+
+// containsError 오류 목록에 주어진 내용을 포함하는 오류가 있는지 확인
+//
+// Parameters:
+//   - errs: 오류 목록
+//   - want: 포함되어야 하는 내용
+//
+// Returns:
+//   - bool
+func containsError(errs []error, want string) bool {
+	for _, err := range errs {
+		if strings.Contains(err.Error(), want) {
+			return true
+		}
+	}
+	return false
+}
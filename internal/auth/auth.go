@@ -0,0 +1,61 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+/*
+Package auth 리눅스 로컬 계정 인증 패키지
+*/
+package auth
+
+import "errors"
+
+var (
+	// ErrInvalidCredentials 계정이 존재하지 않거나 비밀번호 불일치
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	// ErrAccountLocked 잠겨 있거나 로그인이 허용되지 않은 계정
+	ErrAccountLocked = errors.New("account is locked")
+	// ErrAccountExpired 만료된 계정
+	ErrAccountExpired = errors.New("account has expired")
+	// ErrPasswordExpired 비밀번호 변경이 필요한 계정
+	ErrPasswordExpired = errors.New("password has expired")
+)
+
+// User 인증된 리눅스 계정 정보 구조체
+type User struct {
+	Username string
+	Uid      uint32
+	Gid      uint32
+	Gecos    string
+	Home     string
+	Shell    string
+}
+
+// Authenticator 계정 인증 인터페이스
+type Authenticator interface {
+	// Authenticate 사용자명과 비밀번호로 계정을 인증하여 계정 정보 반환
+	Authenticate(username, password string) (*User, error)
+}
+
+// IsAuthError 인증 실패(계정 정보 불일치, 잠김, 만료) 에러인지 확인
+//
+// Parameters:
+//   - err: error
+//
+// Returns:
+//   - bool: 인증 실패(true), 그 외 서버 내부 에러(false)
+func IsAuthError(err error) bool {
+	return errors.Is(err, ErrInvalidCredentials) || errors.Is(err, ErrAccountLocked) ||
+		errors.Is(err, ErrAccountExpired) || errors.Is(err, ErrPasswordExpired)
+}
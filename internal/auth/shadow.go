@@ -0,0 +1,303 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package auth
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hoon-kr/weblin/pkg/utils/crypt"
+)
+
+const (
+	DefaultPasswdPath = "/etc/passwd"
+	DefaultShadowPath = "/etc/shadow"
+)
+
+// dummyHash 존재하지 않는 계정 인증 시 응답 시간을 맞추기 위한 해시 (SHA-512, "weblin")
+const dummyHash = "$6$weblin.dummy$" +
+	"JWTlx4ve9SUxC6hwYasoVRUal24XWWQQ.JXRYU0S9/hK/XJ7MyssLsTFIc7kaoBaBsakD7f/R5EPlrIKajO1J/"
+
+// 로그인을 허용하지 않는 셸
+var noLoginShells = map[string]bool{
+	"nologin": true,
+	"false":   true,
+}
+
+// ShadowAuthenticator passwd/shadow 파일 기반 계정 인증 정보 구조체
+type ShadowAuthenticator struct {
+	// passwd 파일 경로
+	PasswdPath string
+	// shadow 파일 경로
+	ShadowPath string
+	// 현재 시각 (계정 만료 검사용, nil일 경우 time.Now 사용)
+	Now func() time.Time
+}
+
+// shadowEntry shadow 파일 항목 구조체 (날짜는 1970-01-01 기준 일 수, 미설정 시 -1)
+type shadowEntry struct {
+	password   string
+	lastChange int64
+	maxDays    int64
+	inactive   int64
+	expire     int64
+}
+
+// NewShadowAuthenticator 시스템 passwd/shadow 파일 기반 인증 구조체 생성
+//
+// Returns:
+//   - *ShadowAuthenticator
+func NewShadowAuthenticator() *ShadowAuthenticator {
+	return &ShadowAuthenticator{
+		PasswdPath: DefaultPasswdPath,
+		ShadowPath: DefaultShadowPath,
+	}
+}
+
+// Authenticate 사용자명과 비밀번호로 계정을 인증하여 계정 정보 반환
+//
+// Parameters:
+//   - username: 사용자명
+//   - password: 평문 비밀번호
+//
+// Returns:
+//   - *User: 계정 정보
+//   - error: 성공(nil), 실패(ErrInvalidCredentials, ErrAccountLocked, ErrAccountExpired,
+//     ErrPasswordExpired 또는 파일 처리 error)
+func (a *ShadowAuthenticator) Authenticate(username, password string) (*User, error) {
+	if username == "" || strings.ContainsAny(username, ":\n") {
+		return nil, ErrInvalidCredentials
+	}
+
+	u, err := a.lookupUser(username)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := a.lookupShadow(username)
+	if err != nil {
+		return nil, err
+	}
+
+	// 계정이 존재하지 않더라도 해시 연산을 수행하여 응답 시간으로 계정 유무를 추측할 수 없도록 함
+	if u == nil || entry == nil {
+		crypt.Verify(password, dummyHash)
+		return nil, ErrInvalidCredentials
+	}
+
+	// 비밀번호가 없거나 잠긴 계정 (!, *, !! 등)은 존재하지 않는 계정과 구분할 수 없도록
+	// 같은 해시 연산을 수행하고 같은 에러 반환
+	if entry.password == "" || strings.HasPrefix(entry.password, "!") ||
+		strings.HasPrefix(entry.password, "*") {
+		crypt.Verify(password, dummyHash)
+		return nil, ErrInvalidCredentials
+	}
+
+	ok, err := crypt.Verify(password, entry.password)
+	if errors.Is(err, crypt.ErrUnsupportedHash) || errors.Is(err, crypt.ErrInvalidHash) {
+		// 지원하지 않는 해시 형식(DES, bcrypt 등)도 존재하지 않는 계정과 구분할 수 없도록 같은 해시 연산 후
+		// 같은 에러 반환 (해시 형식은 서버 로그에만 기록)
+		crypt.Verify(password, dummyHash)
+		return nil, fmt.Errorf("%w (user:%s, hash:%s): %s", ErrInvalidCredentials, username, hashFormat(entry.password), err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify password (user:%s): %s", username, err)
+	}
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+
+	// 비밀번호가 일치한 경우에만 계정 상태를 알려줌
+	if err := a.checkExpiry(entry); err != nil {
+		return nil, err
+	}
+	if noLoginShells[filepath.Base(u.Shell)] {
+		return nil, ErrAccountLocked
+	}
+
+	return u, nil
+}
+
+// checkExpiry 계정 및 비밀번호 만료 여부 확인
+//
+// Parameters:
+//   - entry: shadow 항목
+//
+// Returns:
+//   - error: 정상(nil), 만료(ErrAccountExpired, ErrPasswordExpired)
+func (a *ShadowAuthenticator) checkExpiry(entry *shadowEntry) error {
+	now := time.Now
+	if a.Now != nil {
+		now = a.Now
+	}
+	today := now().Unix() / 86400
+
+	// 계정 만료일
+	if entry.expire >= 0 && today >= entry.expire {
+		return ErrAccountExpired
+	}
+
+	// 최종 변경일이 0이면 다음 로그인 시 비밀번호 변경 필요
+	if entry.lastChange == 0 {
+		return ErrPasswordExpired
+	}
+
+	// 비밀번호 최대 사용 기간 초과
+	if entry.lastChange > 0 && entry.maxDays >= 0 && today > entry.lastChange+entry.maxDays {
+		// 비활성화 기간까지 초과한 경우 계정 만료
+		if entry.inactive >= 0 && today > entry.lastChange+entry.maxDays+entry.inactive {
+			return ErrAccountExpired
+		}
+		return ErrPasswordExpired
+	}
+
+	return nil
+}
+
+// lookupUser passwd 파일에서 계정 정보 조회
+//
+// Parameters:
+//   - username: 사용자명
+//
+// Returns:
+//   - *User: 계정 정보 (존재하지 않을 경우 nil)
+//   - error: 성공(nil), 실패(error)
+func (a *ShadowAuthenticator) lookupUser(username string) (*User, error) {
+	var user *User
+	err := scanColonFile(a.PasswdPath, username, func(fields []string) error {
+		if len(fields) < 7 {
+			return fmt.Errorf("malformed passwd entry (user:%s)", username)
+		}
+		uid, err := strconv.ParseUint(fields[2], 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid uid in passwd entry (user:%s)", username)
+		}
+		gid, err := strconv.ParseUint(fields[3], 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid gid in passwd entry (user:%s)", username)
+		}
+		user = &User{
+			Username: fields[0],
+			Uid:      uint32(uid),
+			Gid:      uint32(gid),
+			Gecos:    fields[4],
+			Home:     fields[5],
+			Shell:    fields[6],
+		}
+		return nil
+	})
+	return user, err
+}
+
+// lookupShadow shadow 파일에서 비밀번호 정보 조회
+//
+// Parameters:
+//   - username: 사용자명
+//
+// Returns:
+//   - *shadowEntry: shadow 항목 (존재하지 않을 경우 nil)
+//   - error: 성공(nil), 실패(error)
+func (a *ShadowAuthenticator) lookupShadow(username string) (*shadowEntry, error) {
+	var entry *shadowEntry
+	err := scanColonFile(a.ShadowPath, username, func(fields []string) error {
+		if len(fields) < 8 {
+			return fmt.Errorf("malformed shadow entry (user:%s)", username)
+		}
+		entry = &shadowEntry{
+			password:   fields[1],
+			lastChange: parseDays(fields[2]),
+			maxDays:    parseDays(fields[4]),
+			inactive:   parseDays(fields[6]),
+			expire:     parseDays(fields[7]),
+		}
+		return nil
+	})
+	return entry, err
+}
+
+// scanColonFile 콜론(:)으로 구분된 계정 파일에서 사용자명이 일치하는 항목을 찾아 콜백 호출
+//
+// Parameters:
+//   - filePath: 파일 경로
+//   - username: 사용자명
+//   - fn: 항목을 찾았을 때 호출할 콜백
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func scanColonFile(filePath, username string, fn func(fields []string) error) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %s", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, ":")
+		if fields[0] == username {
+			return fn(fields)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading file (%s): %s", filePath, err)
+	}
+
+	return nil
+}
+
+// hashFormat 로그 기록용 해시 형식 반환 (해시 값은 포함하지 않음)
+//
+// Parameters:
+//   - hash: crypt(3) 형식 해시
+//
+// Returns:
+//   - string: 해시 형식 (예: $2b$, des)
+func hashFormat(hash string) string {
+	if !strings.HasPrefix(hash, "$") {
+		return "des"
+	}
+	if idx := strings.IndexByte(hash[1:], '$'); idx >= 0 {
+		return hash[:idx+2]
+	}
+	return "unknown"
+}
+
+// parseDays shadow 파일의 날짜 필드를 정수로 변환
+//
+// Parameters:
+//   - value: 날짜 필드 값
+//
+// Returns:
+//   - int64: 일 수 (비어있거나 잘못된 값일 경우 -1)
+func parseDays(value string) int64 {
+	days, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return days
+}
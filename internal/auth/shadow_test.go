@@ -0,0 +1,115 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package auth
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testPassword 테스트용 shadow 파일의 비밀번호
+const testPassword = "Hello world!"
+
+// newTestAuthenticator testdata의 passwd/shadow 파일 기반 인증 구조체 생성 (기준일: 20000)
+//
+// Returns:
+//   - *ShadowAuthenticator
+func newTestAuthenticator() *ShadowAuthenticator {
+	return &ShadowAuthenticator{
+		PasswdPath: filepath.Join("testdata", "passwd"),
+		ShadowPath: filepath.Join("testdata", "shadow"),
+		Now:        func() time.Time { return time.Unix(20000*86400+3600, 0) },
+	}
+}
+
+func TestShadowAuthenticator(t *testing.T) {
+	a := newTestAuthenticator()
+
+	tests := []struct {
+		name     string
+		username string
+		password string
+		wantErr  error
+	}{
+		{"valid", "alice", testPassword, nil},
+		{"wrong password", "alice", "hello world!", ErrInvalidCredentials},
+		{"empty password", "alice", "", ErrInvalidCredentials},
+		{"unknown user", "mallory", testPassword, ErrInvalidCredentials},
+		{"user without shadow entry", "noshadow", testPassword, ErrInvalidCredentials},
+		{"empty username", "", testPassword, ErrInvalidCredentials},
+		{"username with colon", "alice:x", testPassword, ErrInvalidCredentials},
+		{"locked with !", "locked", testPassword, ErrInvalidCredentials},
+		{"locked with *", "star", testPassword, ErrInvalidCredentials},
+		{"locked with !!", "doublebang", testPassword, ErrInvalidCredentials},
+		{"no password", "nopass", "", ErrInvalidCredentials},
+		{"system account", "root", testPassword, ErrInvalidCredentials},
+		{"unsupported des hash", "deshash", testPassword, ErrInvalidCredentials},
+		{"unsupported bcrypt hash", "bcrypt", testPassword, ErrInvalidCredentials},
+		{"account expired", "expired", testPassword, ErrAccountExpired},
+		{"account expired wrong password", "expired", "wrong", ErrInvalidCredentials},
+		{"password change required", "mustchange", testPassword, ErrPasswordExpired},
+		{"password max days exceeded", "oldpass", testPassword, ErrPasswordExpired},
+		{"password inactive period exceeded", "inactive", testPassword, ErrAccountExpired},
+		{"nologin shell", "nologin", testPassword, ErrAccountLocked},
+		{"nologin shell wrong password", "nologin", "wrong", ErrInvalidCredentials},
+		{"false shell", "falseshell", testPassword, ErrAccountLocked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := a.Authenticate(tt.username, tt.password)
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("Authenticate(%q) error = %v, want %v", tt.username, err, tt.wantErr)
+			}
+			if err != nil {
+				if u != nil {
+					t.Errorf("Authenticate(%q) returned user with error", tt.username)
+				}
+				if !IsAuthError(err) {
+					t.Errorf("IsAuthError(%v) = false, want true", err)
+				}
+				return
+			}
+			if u == nil || u.Username != tt.username {
+				t.Fatalf("Authenticate(%q) user = %+v", tt.username, u)
+			}
+		})
+	}
+}
+
+func TestShadowAuthenticatorUser(t *testing.T) {
+	u, err := newTestAuthenticator().Authenticate("alice", testPassword)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %s", err)
+	}
+
+	want := User{Username: "alice", Uid: 1000, Gid: 1000, Gecos: "Alice", Home: "/home/alice", Shell: "/bin/bash"}
+	if *u != want {
+		t.Errorf("Authenticate() user = %+v, want %+v", *u, want)
+	}
+}
+
+func TestShadowAuthenticatorFileError(t *testing.T) {
+	a := newTestAuthenticator()
+	a.ShadowPath = filepath.Join(t.TempDir(), "missing")
+
+	_, err := a.Authenticate("alice", testPassword)
+	if err == nil || IsAuthError(err) {
+		t.Errorf("Authenticate() error = %v, want file error", err)
+	}
+}
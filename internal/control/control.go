@@ -0,0 +1,268 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+/*
+Package control 로컬 제어 API 패키지
+
+동작 중인 서버는 Unix 소켓으로 HTTP 제어 API를 제공하며, weblin 명령어(status 등)는
+이 소켓으로 서버 상태를 조회한다. 소켓은 서버 실행 계정만 접근할 수 있다(0600).
+*/
+package control
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hoon-kr/weblin/internal/web"
+	"github.com/hoon-kr/weblin/pkg/utils/goroutine"
+)
+
+// 제어 API 경로
+const (
+	// StatusPath 서버 상태 조회
+	StatusPath = "/status"
+	// LogLevelPath 로그 레벨 조회(GET) 및 변경(POST)
+	LogLevelPath = "/log-level"
+	// ReloadPath 설정 다시 읽기(POST)
+	ReloadPath = "/reload"
+)
+
+// Status 서버 상태 정보 구조체
+type Status struct {
+	Running   bool   `json:"running"`
+	Healthy   bool   `json:"healthy"`
+	Pid       int    `json:"pid,omitempty"`
+	Version   string `json:"version,omitempty"`
+	BuildTime string `json:"buildTime,omitempty"`
+	// 가동 모드 (normal, debug)
+	Mode      string     `json:"mode,omitempty"`
+	StartTime *time.Time `json:"startTime,omitempty"`
+	// 가동 시간(초)
+	Uptime int64 `json:"uptime,omitempty"`
+	// 웹 서버 수신 주소 (예: http://[::]:8080)
+	Listen    []string `json:"listen,omitempty"`
+	Sessions  int      `json:"sessions"`
+	Terminals int      `json:"terminals"`
+	// 고루틴 작업별 상태 (Required: 항상 가동 중이어야 하는 작업)
+	Tasks []TaskStatus `json:"tasks,omitempty"`
+	// 상태 조회 실패 사유
+	Error string `json:"error,omitempty"`
+}
+
+// TaskStatus 고루틴 작업 상태 정보 구조체
+type TaskStatus struct {
+	goroutine.TaskStatus
+	Required bool `json:"required"`
+}
+
+// LogLevels 로그 출력 대상별 로그 레벨 정보 구조체
+type LogLevels struct {
+	// 로그 출력 대상(text, json, console)별 로그 레벨
+	Levels map[string]string `json:"levels"`
+}
+
+// LogLevelRequest 로그 레벨 변경 요청 구조체
+type LogLevelRequest struct {
+	// 로그 출력 대상 (빈 문자열일 경우 전체)
+	Sink  string `json:"sink,omitempty"`
+	Level string `json:"level"`
+}
+
+// ReloadResult 설정 다시 읽기 결과 구조체
+type ReloadResult struct {
+	// 변경된 설정 항목 이름 목록
+	Changed []string `json:"changed"`
+}
+
+// Server 제어 API 서버 관리 정보 구조체
+type Server struct {
+	mu         sync.Mutex
+	mux        *http.ServeMux
+	httpServer *http.Server
+	path       string
+}
+
+// NewServer 제어 API 서버 구조체 생성
+//
+// Returns:
+//   - *Server
+func NewServer() *Server {
+	return &Server{mux: http.NewServeMux()}
+}
+
+// HandleFunc 요청 경로에 핸들러 함수 등록
+//
+// Parameters:
+//   - pattern: 요청 경로 패턴
+//   - handler: 요청 핸들러 함수
+func (s *Server) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	s.mux.HandleFunc(pattern, handler)
+}
+
+// Start Unix 소켓을 생성하고 제어 API 서버 가동
+//
+// 이전 가동 시 남은 소켓 파일은 삭제 후 다시 생성한다.
+//
+// Parameters:
+//   - path: Unix 소켓 경로
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func (s *Server) Start(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.httpServer != nil {
+		return fmt.Errorf("control server is already running")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to make directory: %s", err)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove stale control socket: %s", err)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("failed to listen (%s): %s", path, err)
+	}
+	// 서버 실행 계정만 접근 가능
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to change mode of control socket: %s", err)
+	}
+
+	s.httpServer = &http.Server{
+		Handler:           s.mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	s.path = path
+
+	go s.httpServer.Serve(listener)
+
+	return nil
+}
+
+// Shutdown 제어 API 서버 정지 및 소켓 파일 삭제
+func (s *Server) Shutdown() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.httpServer == nil {
+		return
+	}
+
+	s.httpServer.Close()
+	// Close 시 net 패키지가 소켓 파일을 삭제하지만, 삭제되지 않은 경우를 대비
+	os.Remove(s.path)
+	s.httpServer = nil
+}
+
+// Get 동작 중인 서버의 제어 API 조회
+//
+// Parameters:
+//   - ctx: 요청 컨텍스트 (타임아웃)
+//   - socketPath: Unix 소켓 경로
+//   - path: 제어 API 경로
+//   - v: 응답 데이터를 저장할 포인터
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func Get(ctx context.Context, socketPath, path string, v interface{}) error {
+	return call(ctx, socketPath, http.MethodGet, path, nil, v)
+}
+
+// Post 동작 중인 서버의 제어 API 요청
+//
+// Parameters:
+//   - ctx: 요청 컨텍스트 (타임아웃)
+//   - socketPath: Unix 소켓 경로
+//   - path: 제어 API 경로
+//   - body: JSON으로 변환하여 전송할 요청 데이터
+//   - v: 응답 데이터를 저장할 포인터 (nil일 경우 무시)
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func Post(ctx context.Context, socketPath, path string, body, v interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %s", err)
+	}
+	return call(ctx, socketPath, http.MethodPost, path, bytes.NewReader(data), v)
+}
+
+// call 제어 API 요청 전송 및 JSON 응답 해석
+//
+// Parameters:
+//   - ctx: 요청 컨텍스트 (타임아웃)
+//   - socketPath: Unix 소켓 경로
+//   - method: 요청 메서드
+//   - path: 제어 API 경로
+//   - body: 요청 본문 (nil일 경우 없음)
+//   - v: 응답 데이터를 저장할 포인터 (nil일 경우 무시)
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func call(ctx context.Context, socketPath, method, path string, body io.Reader, v interface{}) error {
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+
+	// Unix 소켓으로 연결하므로 호스트 이름은 사용하지 않음
+	req, err := http.NewRequestWithContext(ctx, method, "http://weblin"+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %s", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to connect to control socket (%s): %s", socketPath, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp web.ErrorResponse
+		if json.NewDecoder(resp.Body).Decode(&errResp) == nil && errResp.Error != "" {
+			return fmt.Errorf("control request failed: %s", errResp.Error)
+		}
+		return fmt.Errorf("control request failed: %s", resp.Status)
+	}
+
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			return fmt.Errorf("failed to decode response: %s", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,452 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package filemanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hoon-kr/weblin/config"
+	"github.com/hoon-kr/weblin/internal/audit"
+	"github.com/hoon-kr/weblin/internal/auth"
+	"github.com/hoon-kr/weblin/internal/logger"
+	"github.com/hoon-kr/weblin/internal/privsep"
+	"github.com/hoon-kr/weblin/internal/session"
+	"github.com/hoon-kr/weblin/internal/web"
+	"github.com/hoon-kr/weblin/pkg/utils/archive"
+)
+
+const (
+	// 종료된 압축 해제 작업 결과 유지 시간
+	extractJobRetention = 10 * time.Minute
+	// 압축 해제 작업 고루틴 종료 대기 타임아웃
+	extractStopTimeout = 5 * time.Second
+	// 압축 스트림 전송 버퍼 크기
+	archiveBufferSize = 32 * 1024
+)
+
+// 압축 해제 작업 상태
+const (
+	jobRunning   = "running"
+	jobCompleted = "completed"
+	jobFailed    = "failed"
+	jobCanceled  = "canceled"
+)
+
+// extractRequest 압축 해제 요청 구조체
+type extractRequest struct {
+	Archive string `json:"archive"`
+	Dest    string `json:"dest"`
+	// 압축 파일 형식 (생략 시 확장자로 판별)
+	Format    string `json:"format,omitempty"`
+	Overwrite bool   `json:"overwrite,omitempty"`
+}
+
+// extractStatus 압축 해제 작업 상태 응답 구조체
+type extractStatus struct {
+	ID      string `json:"id"`
+	Archive string `json:"archive"`
+	Dest    string `json:"dest"`
+	State   string `json:"state"`
+	archive.Progress
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// extractJob 압축 해제 작업 정보 구조체
+type extractJob struct {
+	mu         sync.Mutex
+	id         string
+	user       *auth.User
+	archive    string
+	dest       string
+	state      string
+	progress   archive.Progress
+	err        error
+	startedAt  time.Time
+	finishedAt time.Time
+	cancel     context.CancelFunc
+	// 작업 종료 시 결과와 함께 기록할 감사 로그 항목 (요청 정보 포함)
+	auditEntry *audit.Entry
+}
+
+// status 압축 해제 작업 상태 생성
+//
+// Returns:
+//   - *extractStatus
+func (j *extractJob) status() *extractStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	s := &extractStatus{
+		ID:        j.id,
+		Archive:   j.archive,
+		Dest:      j.dest,
+		State:     j.state,
+		Progress:  j.progress,
+		StartedAt: j.startedAt,
+	}
+	if j.err != nil {
+		s.Error = j.err.Error()
+	}
+	if !j.finishedAt.IsZero() {
+		finishedAt := j.finishedAt
+		s.FinishedAt = &finishedAt
+	}
+	return s
+}
+
+// handleArchive 선택한 경로들을 압축하여 다운로드 (GET /api/fs/archive?path=&path=&format=&name=)
+//
+// 헬퍼 프로세스가 계정 권한으로 압축 데이터를 파이프에 기록하면 이를 그대로 응답으로 전송하므로
+// 임시 파일을 만들지 않는다. 전송 도중 실패하면 불완전한 압축 파일이 정상 파일로 저장되지 않도록
+// 연결을 강제로 종료한다.
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (m *Manager) handleArchive(w http.ResponseWriter, r *http.Request) {
+	if !web.AllowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	query := r.URL.Query()
+	if len(query["path"]) == 0 {
+		web.WriteError(w, http.StatusBadRequest, "path is required")
+		return
+	}
+	paths := make([]string, 0, len(query["path"]))
+	for _, p := range query["path"] {
+		path, err := CleanPath(p)
+		if err != nil {
+			web.WriteError(w, http.StatusBadRequest, "%s", err)
+			return
+		}
+		paths = append(paths, path)
+	}
+
+	format := archive.FormatZip
+	if value := query.Get("format"); value != "" {
+		var err error
+		if format, err = archive.ParseFormat(value); err != nil {
+			web.WriteError(w, http.StatusBadRequest, "%s", err)
+			return
+		}
+	}
+
+	name := query.Get("name")
+	if name == "" {
+		name = "archive"
+		if len(paths) == 1 && paths[0] != "/" {
+			name = filepath.Base(paths[0])
+		}
+		name += format.Ext()
+	}
+
+	client, err := m.Client(r)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	defer pr.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Do(r.Context(), &privsep.Request{
+			Op:    opArchive,
+			Args:  &archiveArgs{Format: format, Paths: paths},
+			Files: []*os.File{pw},
+		}, nil)
+		// 헬퍼 프로세스 작업이 끝나면 쓰기 끝을 닫아 읽기 측에 EOF 전달
+		pw.Close()
+		done <- err
+	}()
+
+	// 첫 데이터가 도착하기 전에 실패한 경우(경로 없음, 권한 없음 등)는 에러 응답 전송
+	buf := make([]byte, archiveBufferSize)
+	n, err := io.ReadAtLeast(pr, buf, 1)
+	if err != nil {
+		if herr := <-done; herr != nil {
+			err = herr
+		}
+		WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	logOperation(r, "%s (paths:%v, format:%s)", opArchive, paths, format)
+
+	_, err = w.Write(buf[:n])
+	if err == nil {
+		_, err = io.CopyBuffer(w, pr, buf)
+	}
+	if err != nil {
+		// 클라이언트 연결 종료 시 헬퍼 프로세스가 파이프 쓰기 에러로 중단되도록 읽기 끝을 닫음
+		pr.Close()
+	}
+	if herr := <-done; herr != nil {
+		logger.FromContext(r.Context()).LogWarn("Archive stream aborted (paths:%v): %s", paths, herr)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// handleExtractCreate 압축 해제 작업 시작 (POST /api/fs/extract)
+//
+// 압축 해제는 고루틴 작업으로 수행되며 응답으로 전달한 작업 ID로 진행 상황을 조회한다.
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (m *Manager) handleExtractCreate(w http.ResponseWriter, r *http.Request) {
+	if !web.AllowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	var req extractRequest
+	if err := web.ReadJSON(r, &req); err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	src, err := CleanPath(req.Archive)
+	if err != nil {
+		web.WriteError(w, http.StatusBadRequest, "archive: %s", err)
+		return
+	}
+	dest, err := CleanPath(req.Dest)
+	if err != nil {
+		web.WriteError(w, http.StatusBadRequest, "dest: %s", err)
+		return
+	}
+
+	var format archive.Format
+	if req.Format != "" {
+		format, err = archive.ParseFormat(req.Format)
+	} else {
+		format, err = archive.DetectFormat(src)
+	}
+	if err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	client, err := m.Client(r)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	id, err := newID()
+	if err != nil {
+		web.WriteError(w, http.StatusInternalServerError, "%s", err)
+		return
+	}
+
+	s, _ := session.FromContext(r.Context())
+	job := &extractJob{
+		id:         id,
+		user:       s.User,
+		archive:    src,
+		dest:       dest,
+		state:      jobRunning,
+		startedAt:  time.Now(),
+		auditEntry: audit.NewEntry(r, s.User.Username, opExtract, src),
+	}
+	args := &extractArgs{
+		Archive:    src,
+		Dest:       dest,
+		Format:     format,
+		Overwrite:  req.Overwrite,
+		MaxSize:    int64(config.Current().ExtractMaxSize) << 20,
+		MaxEntries: config.Current().ExtractMaxEntries,
+	}
+
+	m.mu.Lock()
+	m.pruneJobs(time.Now())
+	m.jobs[id] = job
+	m.mu.Unlock()
+
+	// 작업을 고루틴 관리 작업으로 등록하여 서버 종료 시 함께 취소되도록 함
+	taskName := "extract-" + id
+	m.gm.AddTask(taskName, func(ctx context.Context) {
+		defer func() {
+			// 작업 종료 후 관리 목록에서 제거 (자기 자신을 대기하지 않도록 별도 고루틴에서 수행)
+			go m.gm.RemoveTask(taskName, extractStopTimeout)
+		}()
+		m.runExtract(ctx, client, job, args)
+	})
+	if err := m.gm.Start(taskName); err != nil {
+		m.mu.Lock()
+		delete(m.jobs, id)
+		m.mu.Unlock()
+		job.auditEntry.Detail = fmt.Sprintf("id:%s, dest:%s", id, dest)
+		audit.Log.Record(job.auditEntry, err)
+		web.WriteError(w, http.StatusInternalServerError, "failed to start extract job: %s", err)
+		return
+	}
+
+	logOperation(r, "%s (id:%s, archive:%s, dest:%s)", opExtract, id, src, dest)
+	w.Header().Set("Location", PathPrefix+"extract/"+id)
+	web.WriteJSON(w, http.StatusAccepted, job.status())
+}
+
+// runExtract 헬퍼 프로세스에 압축 해제를 요청하고 진행 상황 및 결과를 작업에 반영
+//
+// Parameters:
+//   - ctx: 작업 종료 컨텍스트
+//   - client: 로그인 계정의 헬퍼 프로세스 연결
+//   - job: 압축 해제 작업 정보
+//   - args: 압축 해제 요청 인자
+func (m *Manager) runExtract(ctx context.Context, client *privsep.Client, job *extractJob, args *extractArgs) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	job.mu.Lock()
+	job.cancel = cancel
+	job.mu.Unlock()
+
+	var result archive.Progress
+	_, err := client.Do(ctx, &privsep.Request{
+		Op:   opExtract,
+		Args: args,
+		OnProgress: func(data json.RawMessage) {
+			var p archive.Progress
+			if json.Unmarshal(data, &p) == nil {
+				job.mu.Lock()
+				job.progress = p
+				job.mu.Unlock()
+			}
+		},
+	}, &result)
+
+	job.mu.Lock()
+	job.finishedAt = time.Now()
+	job.cancel = nil
+	switch {
+	case err == nil:
+		job.state = jobCompleted
+		job.progress = result
+	case errors.Is(err, context.Canceled):
+		job.state = jobCanceled
+	default:
+		job.state = jobFailed
+		job.err = err
+	}
+	state := job.state
+	progress := job.progress
+	job.mu.Unlock()
+
+	// 압축 해제 결과(완료, 실패, 취소)를 감사 로그에 기록
+	job.auditEntry.Detail = fmt.Sprintf("id:%s, dest:%s, state:%s, entries:%d, bytes:%d",
+		job.id, job.dest, state, progress.Entries, progress.Bytes)
+	audit.Log.Record(job.auditEntry, err)
+
+	if err != nil && state == jobFailed {
+		logger.Log.LogWarn("Extract failed (id:%s, user:%s, archive:%s, dest:%s): %s",
+			job.id, job.user.Username, job.archive, job.dest, err)
+		return
+	}
+	logger.Log.LogInfo("Extract %s (id:%s, user:%s, archive:%s, dest:%s)",
+		state, job.id, job.user.Username, job.archive, job.dest)
+}
+
+// handleExtractJob 압축 해제 작업 상태 조회 및 취소 (GET, DELETE /api/fs/extract/{id})
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+//   - id: 작업 ID
+func (m *Manager) handleExtractJob(w http.ResponseWriter, r *http.Request, id string) {
+	if !web.AllowMethods(w, r, http.MethodGet, http.MethodDelete) {
+		return
+	}
+
+	job, ok := m.lookupJob(r, id)
+	if !ok {
+		web.WriteError(w, http.StatusNotFound, "extract job not found: %s", id)
+		return
+	}
+
+	if r.Method == http.MethodDelete {
+		job.mu.Lock()
+		if job.cancel != nil {
+			job.cancel()
+		}
+		job.mu.Unlock()
+		logOperation(r, "extract cancel (id:%s)", id)
+	}
+	web.WriteJSON(w, http.StatusOK, job.status())
+}
+
+// lookupJob 요청한 계정의 압축 해제 작업 조회
+//
+// Parameters:
+//   - r: 요청 정보 (로그인 세션 포함)
+//   - id: 작업 ID
+//
+// Returns:
+//   - *extractJob
+//   - bool: 존재(true), 미존재(false)
+func (m *Manager) lookupJob(r *http.Request, id string) (*extractJob, bool) {
+	s, ok := session.FromContext(r.Context())
+	if !ok {
+		return nil, false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.pruneJobs(time.Now())
+	job, exists := m.jobs[id]
+	if !exists || job.user.Uid != s.User.Uid {
+		return nil, false
+	}
+	return job, true
+}
+
+// pruneJobs 결과 유지 시간이 지난 압축 해제 작업 제거 (m.mu 잠금 상태에서 호출)
+//
+// Parameters:
+//   - now: 현재 시각
+func (m *Manager) pruneJobs(now time.Time) {
+	for id, job := range m.jobs {
+		job.mu.Lock()
+		expired := !job.finishedAt.IsZero() && now.Sub(job.finishedAt) > extractJobRetention
+		job.mu.Unlock()
+		if expired {
+			delete(m.jobs, id)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package filemanager
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/hoon-kr/weblin/internal/web"
+)
+
+// handleDownload 파일 다운로드 (GET, HEAD /api/fs/download?path=)
+//
+// Range, If-Range, If-None-Match, If-Modified-Since 요청을 지원하므로 중단된 다운로드를
+// 이어받을 수 있다.
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (m *Manager) handleDownload(w http.ResponseWriter, r *http.Request) {
+	if !web.AllowMethods(w, r, http.MethodGet, http.MethodHead) {
+		return
+	}
+
+	path, err := CleanPath(r.URL.Query().Get("path"))
+	if err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	client, err := m.Client(r)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	// 계정 권한으로 파일을 열어 전달받은 디스크립터로 전송 (FIFO 등은 열기가 대기하지 않도록 O_NONBLOCK)
+	f, err := client.Open(r.Context(), path, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	if !fi.Mode().IsRegular() {
+		WriteError(w, &os.PathError{Op: "download", Path: path, Err: syscall.EISDIR})
+		return
+	}
+
+	name := filepath.Base(path)
+	w.Header().Set("ETag", downloadETag(fi))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	w.Header().Set("Cache-Control", "private, no-cache")
+
+	logOperation(r, "download (path:%s, range:%q)", path, r.Header.Get("Range"))
+	http.ServeContent(w, r, name, fi.ModTime(), f)
+}
+
+// downloadETag 파일 크기와 수정 시각으로 ETag 생성 (If-Range 검증용)
+//
+// Parameters:
+//   - fi: 파일 정보
+//
+// Returns:
+//   - string: ETag
+func downloadETag(fi os.FileInfo) string {
+	return fmt.Sprintf(`"%x-%x"`, fi.Size(), fi.ModTime().UnixNano())
+}
@@ -0,0 +1,247 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package filemanager
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"syscall"
+
+	"github.com/hoon-kr/weblin/config"
+	"github.com/hoon-kr/weblin/internal/audit"
+	"github.com/hoon-kr/weblin/internal/privsep"
+	"github.com/hoon-kr/weblin/internal/session"
+	"github.com/hoon-kr/weblin/internal/web"
+	"github.com/hoon-kr/weblin/pkg/utils/file"
+)
+
+// textFile 텍스트 편집기 파일 응답 구조체
+type textFile struct {
+	file.Info
+	// 인코딩 (utf-8, utf-8-bom만 편집 가능)
+	Encoding string `json:"encoding"`
+	Editable bool   `json:"editable"`
+	ETag     string `json:"etag"`
+	// 파일 내용 (편집 가능한 경우에만 포함, BOM 제외)
+	Content *string `json:"content,omitempty"`
+}
+
+// conflictResponse 편집 충돌 응답 구조체
+type conflictResponse struct {
+	Error string `json:"error"`
+	// 현재 파일의 ETag (파일이 없으면 빈 문자열)
+	ETag string `json:"etag"`
+}
+
+// handleText 텍스트 편집기 파일 조회 및 저장 (GET, PUT /api/fs/text?path=)
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (m *Manager) handleText(w http.ResponseWriter, r *http.Request) {
+	if !web.AllowMethods(w, r, http.MethodGet, http.MethodPut) {
+		return
+	}
+
+	path, err := CleanPath(r.URL.Query().Get("path"))
+	if err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		m.handleTextRead(w, r, path)
+	} else {
+		m.handleTextSave(w, r, path)
+	}
+}
+
+// handleTextRead 텍스트 파일 내용 조회
+//
+// 바이너리 또는 편집할 수 없는 인코딩의 파일은 내용 없이 415 상태 코드로 파일 정보와 판별한
+// 인코딩을 전달하여 편집기가 열지 않도록 한다.
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+//   - path: 파일 경로
+func (m *Manager) handleTextRead(w http.ResponseWriter, r *http.Request, path string) {
+	client, err := m.Client(r)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	// FIFO 등 일반 파일이 아닌 경우 열기가 대기하지 않도록 O_NONBLOCK으로 연 뒤 확인
+	f, err := client.Open(r.Context(), path, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	if !fi.Mode().IsRegular() {
+		WriteError(w, &os.PathError{Op: "read", Path: path, Err: syscall.EISDIR})
+		return
+	}
+	if fi.Size() > editorMaxFileSize() {
+		WriteError(w, &os.PathError{Op: "read", Path: path, Err: syscall.EFBIG})
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(f, editorMaxFileSize()+1))
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	if int64(len(data)) > editorMaxFileSize() {
+		WriteError(w, &os.PathError{Op: "read", Path: path, Err: syscall.EFBIG})
+		return
+	}
+
+	result := &textFile{
+		Info:     *file.NewInfo(path, fi),
+		Encoding: file.DetectEncoding(data),
+		ETag:     file.ContentETag(fi, data),
+	}
+	result.Editable = file.IsEditableEncoding(result.Encoding)
+	w.Header().Set("ETag", result.ETag)
+
+	if !result.Editable {
+		web.WriteJSON(w, http.StatusUnsupportedMediaType, result)
+		return
+	}
+	content := file.DecodeText(data, result.Encoding)
+	result.Content = &content
+	web.WriteJSON(w, http.StatusOK, result)
+}
+
+// handleTextSave 텍스트 파일 저장
+//
+// 요청 본문은 UTF-8 텍스트이며 쿼리 파라미터 encoding(utf-8, utf-8-bom)으로 저장 인코딩을,
+// perm으로 새 파일의 권한을 지정한다. 기존 파일은 조회 시 받은 ETag를 If-Match로, 새 파일은
+// If-None-Match: *를 전달해야 하며 편집 도중 파일이 변경되었으면 412 상태 코드로 거부한다.
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+//   - path: 파일 경로
+func (m *Manager) handleTextSave(w http.ResponseWriter, r *http.Request, path string) {
+	args := &saveTextArgs{
+		Path:    path,
+		IfMatch: r.Header.Get("If-Match"),
+		Create:  r.Header.Get("If-None-Match") == "*",
+		MaxSize: editorMaxFileSize(),
+	}
+	if args.IfMatch == "" && !args.Create {
+		web.WriteError(w, http.StatusPreconditionRequired, "If-Match or If-None-Match: * header is required")
+		return
+	}
+
+	query := r.URL.Query()
+	perm, err := parsePerm(query.Get("perm"), defaultFilePerm)
+	if err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	args.Perm = perm
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, args.MaxSize))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			WriteError(w, &os.PathError{Op: "save", Path: path, Err: syscall.EFBIG})
+			return
+		}
+		web.WriteError(w, http.StatusBadRequest, "failed to read request body: %s", err)
+		return
+	}
+	data, err := file.EncodeText(body, query.Get("encoding"))
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	client, err := m.Client(r)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	// 파일 내용은 요청 프레임 크기 제한을 받지 않도록 파이프로 전달
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	go func() {
+		pw.Write(data)
+		pw.Close()
+	}()
+
+	var result saveTextResult
+	_, err = client.Do(r.Context(), &privsep.Request{
+		Op:    opSaveText,
+		Args:  args,
+		Files: []*os.File{pr},
+	}, &result)
+	// 헬퍼 프로세스가 내용을 읽지 않고 실패한 경우 기록 중인 고루틴이 종료되도록 읽기 끝을 닫음
+	pr.Close()
+	if err != nil {
+		audit.Record(r, session.Username(r.Context()), opSaveText, path, "", err)
+		WriteError(w, err)
+		return
+	}
+
+	if result.Conflict {
+		web.WriteJSON(w, http.StatusPreconditionFailed, &conflictResponse{
+			Error: "file was modified or removed since it was opened",
+			ETag:  result.ETag,
+		})
+		return
+	}
+
+	status := http.StatusOK
+	if args.Create {
+		status = http.StatusCreated
+	}
+	logOperation(r, "%s (path:%s, size:%d)", opSaveText, path, len(data))
+	audit.Record(r, session.Username(r.Context()), opSaveText, path, fmt.Sprintf("size:%d", len(data)), nil)
+	w.Header().Set("ETag", result.ETag)
+	web.WriteJSON(w, status, &textFile{
+		Info:     *result.Info,
+		Encoding: file.DetectEncoding(data),
+		Editable: true,
+		ETag:     result.ETag,
+	})
+}
+
+// editorMaxFileSize 편집 가능한 최대 파일 크기 반환
+//
+// Returns:
+//   - int64: 최대 크기 (바이트)
+func editorMaxFileSize() int64 {
+	return int64(config.Current().EditorMaxFileSize) << 20
+}
@@ -0,0 +1,148 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package filemanager
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// textRoute 텍스트 편집기 요청 경로
+//
+// Parameters:
+//   - path: 파일 경로
+//
+// Returns:
+//   - string
+func textRoute(path string) string {
+	return "text?path=" + url.QueryEscape(path)
+}
+
+// mkfifo 임시 디렉터리에 FIFO 생성
+//
+// Parameters:
+//   - t: 테스트 정보
+//
+// Returns:
+//   - string: FIFO 경로
+func mkfifo(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fifo")
+	if err := syscall.Mkfifo(path, 0o644); err != nil {
+		t.Fatalf("mkfifo: %v", err)
+	}
+	return path
+}
+
+// releaseFifo FIFO를 열고 대기 중인 읽기가 있으면 쓰기 쪽을 열었다 닫아 대기를 해제
+//
+// Parameters:
+//   - path: FIFO 경로
+func releaseFifo(path string) {
+	// 대기 중인 읽기가 없으면 ENXIO로 실패하며 아무것도 하지 않음
+	fd, err := syscall.Open(path, syscall.O_WRONLY|syscall.O_NONBLOCK|syscall.O_CLOEXEC, 0)
+	if err == nil {
+		syscall.Close(fd)
+	}
+}
+
+func TestTextReadSave(t *testing.T) {
+	ts := newTestServer(t)
+	path := filepath.Join(t.TempDir(), "note.txt")
+
+	// 새 파일은 If-None-Match: *로만 생성
+	resp, body := ts.do(t, http.MethodPut, textRoute(path), nil, strings.NewReader("first\n"))
+	if resp.StatusCode != http.StatusPreconditionRequired {
+		t.Fatalf("PUT without precondition = %d: %s", resp.StatusCode, body)
+	}
+	resp, body = ts.do(t, http.MethodPut, textRoute(path), []string{"If-None-Match", "*"}, strings.NewReader("first\n"))
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("PUT new file = %d: %s", resp.StatusCode, body)
+	}
+	created := resp.Header.Get("ETag")
+
+	resp, body = ts.do(t, http.MethodGet, textRoute(path), nil, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET = %d: %s", resp.StatusCode, body)
+	}
+	var text textFile
+	if err := json.Unmarshal([]byte(body), &text); err != nil {
+		t.Fatal(err)
+	}
+	if text.Content == nil || *text.Content != "first\n" || !text.Editable || text.ETag != created {
+		t.Fatalf("GET = %+v, want content %q and ETag %s", text, "first\n", created)
+	}
+
+	resp, body = ts.do(t, http.MethodPut, textRoute(path), []string{"If-Match", text.ETag}, strings.NewReader("second\n"))
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("PUT = %d: %s", resp.StatusCode, body)
+	}
+
+	// 조회 이후 변경된 파일은 저장하지 않음
+	resp, body = ts.do(t, http.MethodPut, textRoute(path), []string{"If-Match", text.ETag}, strings.NewReader("third\n"))
+	if resp.StatusCode != http.StatusPreconditionFailed {
+		t.Fatalf("PUT with stale ETag = %d: %s", resp.StatusCode, body)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "second\n" {
+		t.Fatalf("file = %q, %v, want %q", data, err, "second\n")
+	}
+}
+
+func TestTextNotRegular(t *testing.T) {
+	ts := newTestServer(t)
+	fifo := mkfifo(t)
+
+	tests := []struct {
+		name   string
+		method string
+		route  string
+		header []string
+	}{
+		{"read fifo", http.MethodGet, textRoute(fifo), nil},
+		{"save fifo", http.MethodPut, textRoute(fifo), []string{"If-Match", `"x"`}},
+		{"create over fifo", http.MethodPut, textRoute(fifo), []string{"If-None-Match", "*"}},
+		{"download fifo", http.MethodGet, "download?path=" + url.QueryEscape(fifo), nil},
+		{"read directory", http.MethodGet, textRoute(filepath.Dir(fifo)), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// FIFO는 쓰는 쪽이 없으면 열기가 대기하므로 응답이 제한 시간 안에 와야 함
+			start := time.Now()
+			resp, body := ts.do(t, tt.method, tt.route, tt.header, strings.NewReader("text\n"))
+			releaseFifo(fifo)
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("%s took %s", tt.name, elapsed)
+			}
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Fatalf("%s = %d: %s, want %d", tt.name, resp.StatusCode, body, http.StatusBadRequest)
+			}
+
+			fi, err := os.Lstat(fifo)
+			if err != nil || fi.Mode()&os.ModeNamedPipe == 0 {
+				t.Fatalf("fifo replaced: %v, %v", fi, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,465 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+/*
+Package filemanager 웹 파일 관리 API 패키지
+
+모든 파일 작업은 로그인 계정 권한으로 동작하는 헬퍼 프로세스(privsep)를 통해 수행한다.
+*/
+package filemanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/hoon-kr/weblin/internal/audit"
+	"github.com/hoon-kr/weblin/internal/logger"
+	"github.com/hoon-kr/weblin/internal/privsep"
+	"github.com/hoon-kr/weblin/internal/session"
+	"github.com/hoon-kr/weblin/internal/web"
+	"github.com/hoon-kr/weblin/pkg/utils/file"
+	"github.com/hoon-kr/weblin/pkg/utils/goroutine"
+)
+
+const (
+	// PathPrefix 파일 관리 API 경로
+	PathPrefix = "/api/fs/"
+	// 디렉터리 생성 기본 권한
+	defaultDirPerm = 0o755
+	// 파일 생성 기본 권한
+	defaultFilePerm = 0o644
+)
+
+// pathRequest 단일 경로 요청 구조체
+type pathRequest struct {
+	Path      string `json:"path"`
+	Perm      string `json:"perm,omitempty"`
+	Parents   bool   `json:"parents,omitempty"`
+	Recursive bool   `json:"recursive,omitempty"`
+}
+
+// transferRequest 원본/대상 경로 요청 구조체
+type transferRequest struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Overwrite bool   `json:"overwrite,omitempty"`
+}
+
+// Manager 파일 관리 API 정보 구조체
+type Manager struct {
+	pool    *privsep.Pool
+	gm      *goroutine.GoroutineManager
+	mu      sync.Mutex
+	uploads map[string]*upload
+	jobs    map[string]*extractJob
+}
+
+// NewManager 파일 관리 API 구조체 생성
+//
+// Parameters:
+//   - pool: 계정별 헬퍼 프로세스 관리 구조체
+//   - gm: 압축 해제 작업을 등록할 고루틴 관리 구조체
+//
+// Returns:
+//   - *Manager
+func NewManager(pool *privsep.Pool, gm *goroutine.GoroutineManager) *Manager {
+	return &Manager{
+		pool:    pool,
+		gm:      gm,
+		uploads: make(map[string]*upload),
+		jobs:    make(map[string]*extractJob),
+	}
+}
+
+// ServeHTTP 파일 관리 API 요청 처리 (로그인 세션 필요)
+//
+//   - GET  /api/fs/list?path=   디렉터리 목록
+//   - GET  /api/fs/stat?path=   파일 정보
+//   - POST /api/fs/mkdir        디렉터리 생성 {path, perm, parents}
+//   - POST /api/fs/create       빈 파일 생성 {path, perm}
+//   - POST /api/fs/rename       이름 변경 및 이동 {from, to, overwrite}
+//   - POST /api/fs/copy         복사 {from, to, overwrite}
+//   - POST /api/fs/delete       삭제 {path, recursive}
+//   - GET  /api/fs/download?path= 다운로드 (Range 지원)
+//   - POST /api/fs/upload       업로드 생성 {path, size, perm, overwrite, sha256}
+//   - GET, PUT, DELETE /api/fs/upload/{id}  업로드 상태 조회, 청크 전송, 취소
+//   - POST /api/fs/upload/{id}/complete    업로드 완료 {sha256}
+//   - GET  /api/fs/archive?path=&format=   압축 다운로드 (zip, tar.gz)
+//   - POST /api/fs/extract      압축 해제 작업 시작 {archive, dest, format, overwrite}
+//   - GET, DELETE /api/fs/extract/{id}     압축 해제 진행 상황 조회, 취소
+//   - GET, PUT /api/fs/text?path=          텍스트 편집기 파일 조회, 저장 (If-Match)
+//   - POST /api/fs/chmod        권한 변경 {paths, mode, fileMode, dirMode, recursive}
+//   - POST /api/fs/chown        소유자 변경 {paths, owner, group, recursive}
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (m *Manager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	route := strings.TrimPrefix(r.URL.Path, PathPrefix)
+	switch route {
+	case "list":
+		m.handleList(w, r)
+	case "stat":
+		m.handleStat(w, r)
+	case "mkdir":
+		m.handleMkdir(w, r)
+	case "create":
+		m.handleCreate(w, r)
+	case "rename":
+		m.handleTransfer(w, r, opRename)
+	case "copy":
+		m.handleTransfer(w, r, opCopy)
+	case "delete":
+		m.handleDelete(w, r)
+	case "download":
+		m.handleDownload(w, r)
+	case "upload":
+		m.handleUploadCreate(w, r)
+	case "archive":
+		m.handleArchive(w, r)
+	case "extract":
+		m.handleExtractCreate(w, r)
+	case "text":
+		m.handleText(w, r)
+	case "chmod":
+		m.handleChmod(w, r)
+	case "chown":
+		m.handleChown(w, r)
+	default:
+		if id, found := strings.CutPrefix(route, "upload/"); found {
+			m.handleUpload(w, r, id)
+			return
+		}
+		if id, found := strings.CutPrefix(route, "extract/"); found {
+			m.handleExtractJob(w, r, id)
+			return
+		}
+		web.WriteError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// handleList 디렉터리 목록 조회
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (m *Manager) handleList(w http.ResponseWriter, r *http.Request) {
+	if !web.AllowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	dirPath, err := CleanPath(r.URL.Query().Get("path"))
+	if err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	var result listResult
+	if err := m.call(r, opList, &pathArgs{Path: dirPath}, &result); err != nil {
+		WriteError(w, err)
+		return
+	}
+	web.WriteJSON(w, http.StatusOK, &result)
+}
+
+// handleStat 파일 정보 조회
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (m *Manager) handleStat(w http.ResponseWriter, r *http.Request) {
+	if !web.AllowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	path, err := CleanPath(r.URL.Query().Get("path"))
+	if err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	var info file.Info
+	if err := m.call(r, opStat, &pathArgs{Path: path}, &info); err != nil {
+		WriteError(w, err)
+		return
+	}
+	web.WriteJSON(w, http.StatusOK, &info)
+}
+
+// handleMkdir 디렉터리 생성
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (m *Manager) handleMkdir(w http.ResponseWriter, r *http.Request) {
+	m.handleCreateEntry(w, r, opMkdir, defaultDirPerm)
+}
+
+// handleCreate 빈 파일 생성
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (m *Manager) handleCreate(w http.ResponseWriter, r *http.Request) {
+	m.handleCreateEntry(w, r, opCreate, defaultFilePerm)
+}
+
+// handleCreateEntry 디렉터리 또는 빈 파일 생성 공통 처리
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+//   - op: 헬퍼 프로세스 요청 이름
+//   - defaultPerm: 권한 미지정 시 기본 권한
+func (m *Manager) handleCreateEntry(w http.ResponseWriter, r *http.Request, op string, defaultPerm fs.FileMode) {
+	if !web.AllowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	var req pathRequest
+	if err := web.ReadJSON(r, &req); err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	path, err := CleanPath(req.Path)
+	if err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	perm, err := parsePerm(req.Perm, defaultPerm)
+	if err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	var info file.Info
+	err = m.call(r, op, &pathArgs{Path: path, Perm: perm, Parents: req.Parents}, &info)
+	audit.Record(r, session.Username(r.Context()), op, path, fmt.Sprintf("perm:%o", perm), err)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	logOperation(r, "%s (path:%s)", op, path)
+	web.WriteJSON(w, http.StatusCreated, &info)
+}
+
+// handleTransfer 이름 변경, 이동, 복사 처리
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+//   - op: 헬퍼 프로세스 요청 이름 (opRename, opCopy)
+func (m *Manager) handleTransfer(w http.ResponseWriter, r *http.Request, op string) {
+	if !web.AllowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	var req transferRequest
+	if err := web.ReadJSON(r, &req); err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	from, err := CleanPath(req.From)
+	if err != nil {
+		web.WriteError(w, http.StatusBadRequest, "from: %s", err)
+		return
+	}
+	to, err := CleanPath(req.To)
+	if err != nil {
+		web.WriteError(w, http.StatusBadRequest, "to: %s", err)
+		return
+	}
+
+	var info file.Info
+	err = m.call(r, op, &transferArgs{From: from, To: to, Overwrite: req.Overwrite}, &info)
+	audit.Record(r, session.Username(r.Context()), op, from, "to:"+to, err)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	logOperation(r, "%s (from:%s, to:%s)", op, from, to)
+	web.WriteJSON(w, http.StatusOK, &info)
+}
+
+// handleDelete 파일 또는 디렉터리 삭제
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (m *Manager) handleDelete(w http.ResponseWriter, r *http.Request) {
+	if !web.AllowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	var req pathRequest
+	if err := web.ReadJSON(r, &req); err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	path, err := CleanPath(req.Path)
+	if err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	err = m.call(r, opDelete, &pathArgs{Path: path, Recursive: req.Recursive}, nil)
+	audit.Record(r, session.Username(r.Context()), opDelete, path, fmt.Sprintf("recursive:%t", req.Recursive), err)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	logOperation(r, "%s (path:%s, recursive:%t)", opDelete, path, req.Recursive)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Client 요청한 로그인 계정의 헬퍼 프로세스 연결 반환
+//
+// Parameters:
+//   - r: 요청 정보 (로그인 세션 포함)
+//
+// Returns:
+//   - *privsep.Client
+//   - error: 성공(nil), 실패(error)
+func (m *Manager) Client(r *http.Request) (*privsep.Client, error) {
+	s, ok := session.FromContext(r.Context())
+	if !ok {
+		return nil, errors.New("login required")
+	}
+	return m.pool.Get(s.User)
+}
+
+// call 로그인 계정의 헬퍼 프로세스에 요청 전달
+//
+// Parameters:
+//   - r: 요청 정보 (로그인 세션 포함)
+//   - op: 헬퍼 프로세스 요청 이름
+//   - args: 요청 인자
+//   - result: 결과를 저장할 구조체 포인터
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func (m *Manager) call(r *http.Request, op string, args, result interface{}) error {
+	client, err := m.Client(r)
+	if err != nil {
+		return err
+	}
+	return client.Call(r.Context(), op, args, result)
+}
+
+// CleanPath 요청 경로 검증 및 정규화 (절대 경로만 허용)
+//
+// Parameters:
+//   - path: 요청 경로
+//
+// Returns:
+//   - string: 정규화된 경로
+//   - error: 성공(nil), 실패(error)
+func CleanPath(path string) (string, error) {
+	if path == "" {
+		return "", errors.New("path is required")
+	}
+	if strings.ContainsRune(path, 0) {
+		return "", errors.New("path contains invalid character")
+	}
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("path must be absolute: %s", path)
+	}
+	return filepath.Clean(path), nil
+}
+
+// WriteError 파일 작업 에러를 HTTP 상태 코드로 변환하여 응답 전송
+//
+// Parameters:
+//   - w: 응답 writer
+//   - err: 파일 작업 에러
+func WriteError(w http.ResponseWriter, err error) {
+	web.WriteError(w, errorStatus(err), "%s", err)
+}
+
+// errorStatus 파일 작업 에러에 해당하는 HTTP 상태 코드 반환
+//
+// Parameters:
+//   - err: 파일 작업 에러
+//
+// Returns:
+//   - int: HTTP 상태 코드
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, file.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, file.ErrPermission):
+		return http.StatusForbidden
+	case errors.Is(err, file.ErrExists), errors.Is(err, syscall.ENOTEMPTY):
+		return http.StatusConflict
+	case errors.Is(err, file.ErrInvalid), errors.Is(err, syscall.ENOTDIR),
+		errors.Is(err, syscall.EISDIR), errors.Is(err, syscall.ENAMETOOLONG),
+		errors.Is(err, syscall.ELOOP):
+		return http.StatusBadRequest
+	case errors.Is(err, syscall.EFBIG):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, syscall.ENOSPC), errors.Is(err, syscall.EDQUOT):
+		return http.StatusInsufficientStorage
+	case errors.Is(err, syscall.EROFS):
+		return http.StatusForbidden
+	case errors.Is(err, context.Canceled):
+		// 클라이언트 연결 종료 (nginx 관례)
+		return 499
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parsePerm 8진수 권한 문자열 변환 (예: "755", "0644")
+//
+// Parameters:
+//   - perm: 권한 문자열 (빈 문자열일 경우 기본 권한)
+//   - defaultPerm: 기본 권한
+//
+// Returns:
+//   - fs.FileMode: 권한
+//   - error: 성공(nil), 실패(error)
+func parsePerm(perm string, defaultPerm fs.FileMode) (fs.FileMode, error) {
+	if perm == "" {
+		return defaultPerm, nil
+	}
+	value, err := strconv.ParseUint(perm, 8, 32)
+	if err != nil || value > 0o777 {
+		return 0, fmt.Errorf("invalid permission: %s", perm)
+	}
+	return fs.FileMode(value), nil
+}
+
+// logOperation 파일 변경 작업 로그 기록
+//
+// Parameters:
+//   - r: 요청 정보 (로그인 세션 포함)
+//   - format: 로그 메시지
+//   - args: 가변 인자
+func logOperation(r *http.Request, format string, args ...interface{}) {
+	// 요청 ID, 로그인 계정, 원격 주소는 요청 컨텍스트의 로거가 필드로 기록
+	logger.FromContext(r.Context()).LogInfo("File operation: %s", fmt.Sprintf(format, args...))
+}
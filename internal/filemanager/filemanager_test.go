@@ -0,0 +1,173 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package filemanager
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/user"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hoon-kr/weblin/config"
+	"github.com/hoon-kr/weblin/internal/auth"
+	"github.com/hoon-kr/weblin/internal/logger"
+	"github.com/hoon-kr/weblin/internal/privsep"
+	"github.com/hoon-kr/weblin/internal/session"
+	"github.com/hoon-kr/weblin/pkg/utils/goroutine"
+)
+
+// testRequestTimeout 테스트 요청 제한 시간
+const testRequestTimeout = 10 * time.Second
+
+// testPool 테스트 전체에서 사용하는 헬퍼 프로세스 관리 구조체
+var testPool *privsep.Pool
+
+// TestMain 로그와 헬퍼 프로세스를 준비하고 테스트 실행
+//
+// 헬퍼 프로세스는 실행 파일(/proc/self/exe)을 다시 실행하므로, 헬퍼 명령어로 실행된 경우
+// 테스트 대신 헬퍼 프로세스로 동작한다.
+func TestMain(m *testing.M) {
+	if len(os.Args) > 1 && os.Args[1] == privsep.HelperCommand {
+		code, _ := privsep.RunHelper(nil)
+		os.Exit(code)
+	}
+	os.Exit(runTests(m))
+}
+
+// runTests 임시 디렉터리에 로그를 기록하도록 설정하고 테스트 실행
+//
+// Parameters:
+//   - m: 테스트 정보
+//
+// Returns:
+//   - int: 테스트 종료 코드
+func runTests(m *testing.M) int {
+	dir, err := os.MkdirTemp("", "weblin-filemanager-")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to make temp directory: %s\n", err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+
+	if err := config.SetLayout(config.LayoutFHS, dir); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to set layout: %s\n", err)
+		return 1
+	}
+	logger.Log.InitializeLogger()
+	defer logger.Log.FinalizeLogger()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	testPool = privsep.NewPool()
+	reaperDone := make(chan struct{})
+	go func() {
+		testPool.Reaper(ctx)
+		close(reaperDone)
+	}()
+	defer func() {
+		cancel()
+		<-reaperDone
+	}()
+
+	return m.Run()
+}
+
+// testServer 로그인 세션이 있는 파일 관리 API 테스트 서버 구조체
+type testServer struct {
+	*httptest.Server
+	fm     *Manager
+	cookie *http.Cookie
+}
+
+// newTestServer 현재 계정으로 로그인한 세션과 파일 관리 API 테스트 서버 생성
+//
+// Parameters:
+//   - t: 테스트 정보
+//
+// Returns:
+//   - *testServer
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	cu, err := user.Current()
+	if err != nil {
+		t.Fatalf("failed to lookup current user: %v", err)
+	}
+	uid, _ := strconv.ParseUint(cu.Uid, 10, 32)
+	gid, _ := strconv.ParseUint(cu.Gid, 10, 32)
+	account := &auth.User{Username: cu.Username, Uid: uint32(uid), Gid: uint32(gid), Home: cu.HomeDir, Shell: "/bin/sh"}
+
+	sessions := session.NewManager(nil)
+	s, err := sessions.Create(account, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	fm := NewManager(testPool, goroutine.NewGoroutineManager())
+	ts := &testServer{
+		Server: httptest.NewServer(sessions.Require(fm)),
+		fm:     fm,
+		cookie: &http.Cookie{Name: session.CookieName, Value: s.ID},
+	}
+	t.Cleanup(func() {
+		fm.AbortUploads()
+		ts.Close()
+	})
+	return ts
+}
+
+// do 로그인 세션으로 파일 관리 API 요청
+//
+// Parameters:
+//   - t: 테스트 정보
+//   - method: 요청 메서드
+//   - route: PathPrefix 이후 경로
+//   - header: 추가 요청 헤더 (이름, 값 순서)
+//   - body: 요청 본문 (없을 경우 nil)
+//
+// Returns:
+//   - *http.Response: 응답 (본문은 읽은 뒤 닫힘)
+//   - string: 응답 본문
+func (ts *testServer) do(t *testing.T, method, route string, header []string, body io.Reader) (*http.Response, string) {
+	t.Helper()
+	// 요청 처리가 대기하는 경우에도 테스트가 끝나도록 제한 시간 지정
+	ctx, cancel := context.WithTimeout(context.Background(), testRequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, ts.URL+PathPrefix+route, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.AddCookie(ts.cookie)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, route, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("%s %s: failed to read response: %v", method, route, err)
+	}
+	return resp, string(data)
+}
@@ -0,0 +1,400 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package filemanager
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/hoon-kr/weblin/internal/privsep"
+	"github.com/hoon-kr/weblin/pkg/utils/archive"
+	"github.com/hoon-kr/weblin/pkg/utils/file"
+)
+
+// 압축 해제 진행 상황 전송 주기
+const extractProgressInterval = 250 * time.Millisecond
+
+// 헬퍼 프로세스 요청 이름
+const (
+	opList   = "fs.list"
+	opStat   = "fs.stat"
+	opMkdir  = "fs.mkdir"
+	opCreate = "fs.create"
+	opRename = "fs.rename"
+	opCopy   = "fs.copy"
+	opDelete = "fs.delete"
+	opCommit = "fs.commit"
+	// 압축 관련 요청
+	opArchive = "fs.archive"
+	opExtract = "fs.extract"
+	// 텍스트 편집기 저장 요청
+	opSaveText = "fs.savetext"
+	// 권한 및 소유자 변경 요청
+	opChangePerm = "fs.chperm"
+)
+
+// pathArgs 단일 경로 요청 인자 구조체
+type pathArgs struct {
+	Path      string      `json:"path"`
+	Perm      fs.FileMode `json:"perm,omitempty"`
+	Parents   bool        `json:"parents,omitempty"`
+	Recursive bool        `json:"recursive,omitempty"`
+}
+
+// transferArgs 원본/대상 경로 요청 인자 구조체 (이름 변경, 이동, 복사)
+type transferArgs struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Overwrite bool   `json:"overwrite,omitempty"`
+}
+
+// commitArgs 임시 파일 반영 요청 인자 구조체
+type commitArgs struct {
+	TempPath  string      `json:"tempPath"`
+	Path      string      `json:"path"`
+	Perm      fs.FileMode `json:"perm"`
+	Overwrite bool        `json:"overwrite,omitempty"`
+}
+
+// archiveArgs 압축 생성 요청 인자 구조체 (압축 데이터는 전달한 파이프로 기록)
+type archiveArgs struct {
+	Format archive.Format `json:"format"`
+	Paths  []string       `json:"paths"`
+}
+
+// extractArgs 압축 해제 요청 인자 구조체
+type extractArgs struct {
+	Archive    string         `json:"archive"`
+	Dest       string         `json:"dest"`
+	Format     archive.Format `json:"format"`
+	Overwrite  bool           `json:"overwrite,omitempty"`
+	MaxSize    int64          `json:"maxSize"`
+	MaxEntries int            `json:"maxEntries"`
+}
+
+// saveTextArgs 텍스트 파일 저장 요청 인자 구조체 (파일 내용은 전달한 파이프로 수신)
+type saveTextArgs struct {
+	Path string `json:"path"`
+	// 편집을 시작할 때의 ETag (Create가 false일 때 일치해야 저장)
+	IfMatch string `json:"ifMatch,omitempty"`
+	// 새 파일 생성 (이미 존재하면 충돌)
+	Create  bool        `json:"create,omitempty"`
+	Perm    fs.FileMode `json:"perm"`
+	MaxSize int64       `json:"maxSize"`
+}
+
+// saveTextResult 텍스트 파일 저장 결과 구조체
+type saveTextResult struct {
+	// 편집 도중 파일이 변경되어 저장하지 않음
+	Conflict bool       `json:"conflict,omitempty"`
+	ETag     string     `json:"etag"`
+	Info     *file.Info `json:"info,omitempty"`
+}
+
+// permArgs 권한 및 소유자 변경 요청 인자 구조체 (권한은 chmod 형식 문자열)
+type permArgs struct {
+	Paths     []string `json:"paths"`
+	Mode      string   `json:"mode,omitempty"`
+	FileMode  string   `json:"fileMode,omitempty"`
+	DirMode   string   `json:"dirMode,omitempty"`
+	Uid       int      `json:"uid"`
+	Gid       int      `json:"gid"`
+	Recursive bool     `json:"recursive,omitempty"`
+}
+
+// listResult 디렉터리 목록 조회 결과 구조체
+type listResult struct {
+	Path    string      `json:"path"`
+	Entries []file.Info `json:"entries"`
+}
+
+// init 헬퍼 프로세스 요청 처리 함수 등록
+func init() {
+	privsep.RegisterHandler(opList, helperList)
+	privsep.RegisterHandler(opStat, helperStat)
+	privsep.RegisterHandler(opMkdir, helperMkdir)
+	privsep.RegisterHandler(opCreate, helperCreate)
+	privsep.RegisterHandler(opRename, helperRename)
+	privsep.RegisterHandler(opCopy, helperCopy)
+	privsep.RegisterHandler(opDelete, helperDelete)
+	privsep.RegisterHandler(opCommit, helperCommit)
+	privsep.RegisterHandler(opArchive, helperArchive)
+	privsep.RegisterHandler(opExtract, helperExtract)
+	privsep.RegisterHandler(opSaveText, helperSaveText)
+	privsep.RegisterHandler(opChangePerm, helperChangePerm)
+}
+
+// helperList 디렉터리 목록 조회
+func helperList(_ context.Context, call *privsep.Call) (interface{}, error) {
+	var args pathArgs
+	if err := call.Decode(&args); err != nil {
+		return nil, err
+	}
+
+	entries, err := file.ReadDir(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	return &listResult{Path: args.Path, Entries: entries}, nil
+}
+
+// helperStat 파일 정보 조회
+func helperStat(_ context.Context, call *privsep.Call) (interface{}, error) {
+	var args pathArgs
+	if err := call.Decode(&args); err != nil {
+		return nil, err
+	}
+	return file.Stat(args.Path)
+}
+
+// helperMkdir 디렉터리 생성
+func helperMkdir(_ context.Context, call *privsep.Call) (interface{}, error) {
+	var args pathArgs
+	if err := call.Decode(&args); err != nil {
+		return nil, err
+	}
+
+	if err := file.MakeDir(args.Path, args.Perm, args.Parents); err != nil {
+		return nil, err
+	}
+	return file.Stat(args.Path)
+}
+
+// helperCreate 빈 파일 생성
+func helperCreate(_ context.Context, call *privsep.Call) (interface{}, error) {
+	var args pathArgs
+	if err := call.Decode(&args); err != nil {
+		return nil, err
+	}
+
+	if err := file.CreateFile(args.Path, args.Perm); err != nil {
+		return nil, err
+	}
+	return file.Stat(args.Path)
+}
+
+// helperRename 이름 변경 및 이동
+func helperRename(ctx context.Context, call *privsep.Call) (interface{}, error) {
+	var args transferArgs
+	if err := call.Decode(&args); err != nil {
+		return nil, err
+	}
+
+	if err := file.Rename(ctx, args.From, args.To, args.Overwrite); err != nil {
+		return nil, err
+	}
+	return file.Stat(args.To)
+}
+
+// helperCopy 복사
+func helperCopy(ctx context.Context, call *privsep.Call) (interface{}, error) {
+	var args transferArgs
+	if err := call.Decode(&args); err != nil {
+		return nil, err
+	}
+
+	if err := file.Copy(ctx, args.From, args.To, args.Overwrite); err != nil {
+		return nil, err
+	}
+	return file.Stat(args.To)
+}
+
+// helperDelete 삭제
+func helperDelete(_ context.Context, call *privsep.Call) (interface{}, error) {
+	var args pathArgs
+	if err := call.Decode(&args); err != nil {
+		return nil, err
+	}
+	return nil, file.Remove(args.Path, args.Recursive)
+}
+
+// helperCommit 작성이 완료된 임시 파일을 권한 적용 후 대상 경로로 원자적 이동
+func helperCommit(_ context.Context, call *privsep.Call) (interface{}, error) {
+	var args commitArgs
+	if err := call.Decode(&args); err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(args.TempPath, args.Perm); err != nil {
+		return nil, err
+	}
+	if err := file.ReplaceFile(args.TempPath, args.Path, args.Overwrite); err != nil {
+		return nil, err
+	}
+	return file.Stat(args.Path)
+}
+
+// helperArchive 압축 파일을 생성하여 요청과 함께 전달된 파이프로 스트리밍
+func helperArchive(ctx context.Context, call *privsep.Call) (interface{}, error) {
+	var args archiveArgs
+	if err := call.Decode(&args); err != nil {
+		return nil, err
+	}
+	if len(call.Files) != 1 {
+		return nil, errors.New("archive output pipe is required")
+	}
+	return nil, archive.Create(ctx, call.Files[0], args.Format, args.Paths)
+}
+
+// helperExtract 압축 해제 (진행 상황을 주기적으로 전송)
+func helperExtract(ctx context.Context, call *privsep.Call) (interface{}, error) {
+	var args extractArgs
+	if err := call.Decode(&args); err != nil {
+		return nil, err
+	}
+
+	var last time.Time
+	result, err := archive.Extract(ctx, args.Archive, args.Format, args.Dest, archive.ExtractOptions{
+		MaxSize:    args.MaxSize,
+		MaxEntries: args.MaxEntries,
+		Overwrite:  args.Overwrite,
+		Progress: func(p archive.Progress) {
+			if now := time.Now(); now.Sub(last) >= extractProgressInterval {
+				last = now
+				call.Progress(&p)
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// helperSaveText 편집 중 변경 여부를 확인한 후 텍스트 파일을 원자적으로 저장
+func helperSaveText(_ context.Context, call *privsep.Call) (interface{}, error) {
+	var args saveTextArgs
+	if err := call.Decode(&args); err != nil {
+		return nil, err
+	}
+	if len(call.Files) != 1 {
+		return nil, errors.New("text content pipe is required")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(call.Files[0], args.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > args.MaxSize {
+		return nil, &os.PathError{Op: "save", Path: args.Path, Err: syscall.EFBIG}
+	}
+
+	etag, exists, err := currentETag(args.Path, args.MaxSize)
+	if err != nil {
+		return nil, err
+	}
+	// 새 파일인데 이미 존재하거나, 편집 중 파일이 삭제 또는 변경된 경우 저장하지 않음
+	// (저장 직전 검사이므로 검사와 교체 사이의 짧은 구간은 보호하지 않음)
+	if (args.Create && exists) || (!args.Create && (!exists || etag != args.IfMatch)) {
+		return &saveTextResult{Conflict: true, ETag: etag}, nil
+	}
+
+	if err := file.WriteFileAtomic(args.Path, data, args.Perm); err != nil {
+		return nil, err
+	}
+
+	fi, err := os.Stat(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	return &saveTextResult{ETag: file.ContentETag(fi, data), Info: file.NewInfo(args.Path, fi)}, nil
+}
+
+// currentETag 현재 파일 내용의 ETag 조회
+//
+// Parameters:
+//   - path: 파일 경로
+//   - maxSize: 편집 가능한 최대 크기 (초과 시 ETag를 계산하지 않음)
+//
+// Returns:
+//   - string: ETag (파일이 없거나 편집 가능 크기를 초과하면 빈 문자열)
+//   - bool: 파일 존재 여부
+//   - error: 성공(nil), 실패(error)
+func currentETag(path string, maxSize int64) (string, bool, error) {
+	// FIFO 등 일반 파일이 아닌 경우 열기가 대기하지 않도록 O_NONBLOCK으로 연 뒤 확인
+	f, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return "", true, err
+	}
+	if !fi.Mode().IsRegular() {
+		return "", true, &os.PathError{Op: "save", Path: path, Err: syscall.EISDIR}
+	}
+	if fi.Size() > maxSize {
+		return "", true, nil
+	}
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return "", true, err
+	}
+	return file.ContentETag(fi, data), true, nil
+}
+
+// helperChangePerm 권한 및 소유자 변경 (경로별 결과 반환)
+func helperChangePerm(ctx context.Context, call *privsep.Call) (interface{}, error) {
+	var args permArgs
+	if err := call.Decode(&args); err != nil {
+		return nil, err
+	}
+
+	change, err := args.change()
+	if err != nil {
+		return nil, err
+	}
+	return file.ChangePerm(ctx, args.Paths, change)
+}
+
+// change 요청 인자를 권한 변경 내용으로 변환
+//
+// Returns:
+//   - *file.PermChange: 권한 변경 내용
+//   - error: 성공(nil), 실패(file.ErrInvalid)
+func (a *permArgs) change() (*file.PermChange, error) {
+	change := &file.PermChange{Uid: a.Uid, Gid: a.Gid, Recursive: a.Recursive}
+	for _, m := range []struct {
+		text string
+		spec **file.ModeSpec
+	}{
+		{a.Mode, &change.Mode},
+		{a.FileMode, &change.FileMode},
+		{a.DirMode, &change.DirMode},
+	} {
+		if m.text == "" {
+			continue
+		}
+		spec, err := file.ParseMode(m.text)
+		if err != nil {
+			return nil, err
+		}
+		*m.spec = spec
+	}
+	return change, nil
+}
@@ -0,0 +1,221 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package filemanager
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os/user"
+	"strconv"
+	"strings"
+
+	"github.com/hoon-kr/weblin/internal/audit"
+	"github.com/hoon-kr/weblin/internal/session"
+	"github.com/hoon-kr/weblin/internal/web"
+	"github.com/hoon-kr/weblin/pkg/utils/file"
+)
+
+// chmodRequest 권한 변경 요청 구조체 (chmod 형식: 755, u+x,go-w 등)
+type chmodRequest struct {
+	Paths []string `json:"paths"`
+	// 파일과 디렉터리 공통 권한
+	Mode string `json:"mode,omitempty"`
+	// 파일, 디렉터리별 권한 (지정 시 mode 대신 적용)
+	FileMode  string `json:"fileMode,omitempty"`
+	DirMode   string `json:"dirMode,omitempty"`
+	Recursive bool   `json:"recursive,omitempty"`
+}
+
+// chownRequest 소유자 및 그룹 변경 요청 구조체 (이름 또는 숫자 ID)
+type chownRequest struct {
+	Paths     []string `json:"paths"`
+	Owner     string   `json:"owner,omitempty"`
+	Group     string   `json:"group,omitempty"`
+	Recursive bool     `json:"recursive,omitempty"`
+}
+
+// handleChmod 권한 변경 (POST /api/fs/chmod)
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (m *Manager) handleChmod(w http.ResponseWriter, r *http.Request) {
+	if !web.AllowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	var req chmodRequest
+	if err := web.ReadJSON(r, &req); err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	if req.Mode == "" && req.FileMode == "" && req.DirMode == "" {
+		web.WriteError(w, http.StatusBadRequest, "mode, fileMode or dirMode is required")
+		return
+	}
+
+	args := &permArgs{
+		Mode:      req.Mode,
+		FileMode:  req.FileMode,
+		DirMode:   req.DirMode,
+		Uid:       -1,
+		Gid:       -1,
+		Recursive: req.Recursive,
+	}
+	// 헬퍼 프로세스에 전달하기 전에 권한 형식 검증
+	if _, err := args.change(); err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	m.changePerm(w, r, req.Paths, args)
+}
+
+// handleChown 소유자 및 그룹 변경 (POST /api/fs/chown)
+//
+// 소유자 변경은 root 계정만, 그룹 변경은 소유한 파일을 자신이 속한 그룹으로만 변경할 수 있다.
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (m *Manager) handleChown(w http.ResponseWriter, r *http.Request) {
+	if !web.AllowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	var req chownRequest
+	if err := web.ReadJSON(r, &req); err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	if req.Owner == "" && req.Group == "" {
+		web.WriteError(w, http.StatusBadRequest, "owner or group is required")
+		return
+	}
+
+	uid, err := lookupID(req.Owner, lookupUid)
+	if err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	gid, err := lookupID(req.Group, lookupGid)
+	if err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	m.changePerm(w, r, req.Paths, &permArgs{Uid: uid, Gid: gid, Recursive: req.Recursive})
+}
+
+// changePerm 권한 및 소유자 변경 공통 처리
+//
+// 일부 경로가 실패하면 207(Multi-Status) 상태 코드와 함께 경로별 결과를 전달한다.
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+//   - paths: 요청 경로 목록
+//   - args: 헬퍼 프로세스 요청 인자
+func (m *Manager) changePerm(w http.ResponseWriter, r *http.Request, paths []string, args *permArgs) {
+	if len(paths) == 0 {
+		web.WriteError(w, http.StatusBadRequest, "paths is required")
+		return
+	}
+	for _, p := range paths {
+		path, err := CleanPath(p)
+		if err != nil {
+			web.WriteError(w, http.StatusBadRequest, "%s", err)
+			return
+		}
+		args.Paths = append(args.Paths, path)
+	}
+
+	var summary file.PermSummary
+	err := m.call(r, opChangePerm, args, &summary)
+	audit.Record(r, session.Username(r.Context()), opChangePerm, strings.Join(args.Paths, ","),
+		fmt.Sprintf("mode:%q, fileMode:%q, dirMode:%q, uid:%d, gid:%d, recursive:%t, changed:%d, failed:%d",
+			args.Mode, args.FileMode, args.DirMode, args.Uid, args.Gid, args.Recursive, summary.Changed, summary.Failed), err)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	logOperation(r, "%s (paths:%v, mode:%q, fileMode:%q, dirMode:%q, uid:%d, gid:%d, recursive:%t, changed:%d, failed:%d)",
+		opChangePerm, args.Paths, args.Mode, args.FileMode, args.DirMode, args.Uid, args.Gid,
+		args.Recursive, summary.Changed, summary.Failed)
+
+	status := http.StatusOK
+	if summary.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	web.WriteJSON(w, status, &summary)
+}
+
+// lookupID 계정 또는 그룹 이름을 ID로 변환 (숫자는 그대로 사용)
+//
+// Parameters:
+//   - name: 이름 또는 숫자 ID (빈 문자열이면 변경하지 않음)
+//   - lookup: 이름 조회 함수
+//
+// Returns:
+//   - int: ID (변경하지 않을 경우 -1)
+//   - error: 성공(nil), 실패(error)
+func lookupID(name string, lookup func(string) (string, error)) (int, error) {
+	if name == "" {
+		return -1, nil
+	}
+	if id, err := strconv.ParseUint(name, 10, 31); err == nil {
+		return int(id), nil
+	}
+
+	idStr, err := lookup(name)
+	if err != nil {
+		return -1, err
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return -1, fmt.Errorf("invalid id for %s: %s", name, idStr)
+	}
+	return id, nil
+}
+
+// lookupUid 계정 이름으로 uid 조회
+func lookupUid(name string) (string, error) {
+	u, err := user.Lookup(name)
+	if err != nil {
+		var unknown user.UnknownUserError
+		if errors.As(err, &unknown) {
+			return "", fmt.Errorf("unknown user: %s", name)
+		}
+		return "", err
+	}
+	return u.Uid, nil
+}
+
+// lookupGid 그룹 이름으로 gid 조회
+func lookupGid(name string) (string, error) {
+	g, err := user.LookupGroup(name)
+	if err != nil {
+		var unknown user.UnknownGroupError
+		if errors.As(err, &unknown) {
+			return "", fmt.Errorf("unknown group: %s", name)
+		}
+		return "", err
+	}
+	return g.Gid, nil
+}
@@ -0,0 +1,585 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package filemanager
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hoon-kr/weblin/config"
+	"github.com/hoon-kr/weblin/internal/audit"
+	"github.com/hoon-kr/weblin/internal/auth"
+	"github.com/hoon-kr/weblin/internal/logger"
+	"github.com/hoon-kr/weblin/internal/session"
+	"github.com/hoon-kr/weblin/internal/web"
+	"github.com/hoon-kr/weblin/pkg/utils/file"
+)
+
+const (
+	// UploadOffsetHeader 업로드 위치 헤더 (요청: 청크 시작 위치, 응답: 현재까지 수신한 크기)
+	UploadOffsetHeader = "Upload-Offset"
+	// 업로드 임시 파일 이름 접두사 (대상 디렉터리에 생성하여 원자적 이동 보장)
+	uploadTempPrefix = ".weblin-upload-"
+	// 만료 업로드 정리 주기
+	uploadReapInterval = time.Minute
+	// 임시 파일 정리 타임아웃
+	uploadCleanupTimeout = 10 * time.Second
+)
+
+// uploadRequest 업로드 생성 요청 구조체
+type uploadRequest struct {
+	Path      string `json:"path"`
+	Size      int64  `json:"size"`
+	Perm      string `json:"perm,omitempty"`
+	Overwrite bool   `json:"overwrite,omitempty"`
+	// 파일 전체의 SHA-256 (생성 또는 완료 요청 중 한 곳에서 반드시 전달)
+	Sha256 string `json:"sha256,omitempty"`
+}
+
+// completeRequest 업로드 완료 요청 구조체
+type completeRequest struct {
+	Sha256 string `json:"sha256,omitempty"`
+}
+
+// uploadStatus 업로드 상태 응답 구조체
+type uploadStatus struct {
+	ID        string    `json:"id"`
+	Path      string    `json:"path"`
+	Size      int64     `json:"size"`
+	Offset    int64     `json:"offset"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// upload 진행 중인 업로드 정보 구조체
+//
+// 청크는 순서대로만 기록되므로 수신과 동시에 체크섬을 계산한다.
+// 청크 수신 중(writing)에는 u.mu를 잠그지 않으며, 다른 청크 전송과 완료 요청은 거부한다.
+type upload struct {
+	mu        sync.Mutex
+	id        string
+	user      *auth.User
+	path      string
+	tempPath  string
+	size      int64
+	offset    int64
+	perm      fs.FileMode
+	overwrite bool
+	checksum  string
+	file      *os.File
+	hash      hash.Hash
+	updatedAt time.Time
+	writing   bool
+	finished  bool
+}
+
+// status 업로드 상태 생성 (u.mu 잠금 상태에서 호출)
+//
+// Returns:
+//   - *uploadStatus
+func (u *upload) status() *uploadStatus {
+	return &uploadStatus{
+		ID:        u.id,
+		Path:      u.path,
+		Size:      u.size,
+		Offset:    u.offset,
+		ExpiresAt: u.updatedAt.Add(uploadTimeout()),
+	}
+}
+
+// Write 청크 데이터 기록 및 체크섬 갱신 (io.Writer 구현, 청크 수신 중(writing) 상태에서 호출)
+//
+// Parameters:
+//   - p: 청크 데이터
+//
+// Returns:
+//   - int: 기록한 크기
+//   - error: 성공(nil), 실패(error)
+func (u *upload) Write(p []byte) (int, error) {
+	n, err := u.file.Write(p)
+	u.hash.Write(p[:n])
+	return n, err
+}
+
+// handleUploadCreate 업로드 생성 (POST /api/fs/upload)
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (m *Manager) handleUploadCreate(w http.ResponseWriter, r *http.Request) {
+	if !web.AllowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	var req uploadRequest
+	if err := web.ReadJSON(r, &req); err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	path, err := CleanPath(req.Path)
+	if err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	if req.Size < 0 {
+		web.WriteError(w, http.StatusBadRequest, "invalid size: %d", req.Size)
+		return
+	}
+	perm, err := parsePerm(req.Perm, defaultFilePerm)
+	if err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	checksum, err := parseChecksum(req.Sha256)
+	if err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	client, err := m.Client(r)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	// 덮어쓰기를 허용하지 않을 경우 전송 전에 미리 확인
+	if !req.Overwrite {
+		err := client.Call(r.Context(), opStat, &pathArgs{Path: path}, nil)
+		if err == nil {
+			WriteError(w, &os.PathError{Op: "upload", Path: path, Err: fs.ErrExist})
+			return
+		}
+		if !errors.Is(err, file.ErrNotFound) {
+			WriteError(w, err)
+			return
+		}
+	}
+
+	id, err := newID()
+	if err != nil {
+		web.WriteError(w, http.StatusInternalServerError, "%s", err)
+		return
+	}
+
+	// 계정 권한으로 대상 디렉터리에 임시 파일 생성
+	tempPath := filepath.Join(filepath.Dir(path), uploadTempPrefix+id)
+	f, err := client.Open(r.Context(), tempPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	u := &upload{
+		id:        id,
+		user:      client.User(),
+		path:      path,
+		tempPath:  tempPath,
+		size:      req.Size,
+		perm:      perm,
+		overwrite: req.Overwrite,
+		checksum:  checksum,
+		file:      f,
+		hash:      sha256.New(),
+		updatedAt: time.Now(),
+	}
+
+	m.mu.Lock()
+	m.uploads[id] = u
+	m.mu.Unlock()
+
+	logOperation(r, "upload started (id:%s, path:%s, size:%d)", id, path, req.Size)
+
+	w.Header().Set("Location", PathPrefix+"upload/"+id)
+	w.Header().Set(UploadOffsetHeader, "0")
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	web.WriteJSON(w, http.StatusCreated, u.status())
+}
+
+// handleUpload 업로드 상태 조회(GET, HEAD), 청크 전송(PUT), 완료(POST .../complete), 취소(DELETE)
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+//   - route: "upload/" 이후 경로 ({id} 또는 {id}/complete)
+func (m *Manager) handleUpload(w http.ResponseWriter, r *http.Request, route string) {
+	id, action, _ := strings.Cut(route, "/")
+
+	u, ok := m.lookupUpload(r, id)
+	if !ok {
+		web.WriteError(w, http.StatusNotFound, "upload not found")
+		return
+	}
+
+	switch action {
+	case "":
+		if !web.AllowMethods(w, r, http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete) {
+			return
+		}
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			u.mu.Lock()
+			defer u.mu.Unlock()
+			w.Header().Set(UploadOffsetHeader, strconv.FormatInt(u.offset, 10))
+			web.WriteJSON(w, http.StatusOK, u.status())
+		case http.MethodPut:
+			m.handleUploadChunk(w, r, u)
+		case http.MethodDelete:
+			m.abortUpload(u)
+			logOperation(r, "upload aborted (id:%s, path:%s)", u.id, u.path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	case "complete":
+		if !web.AllowMethods(w, r, http.MethodPost) {
+			return
+		}
+		m.handleUploadComplete(w, r, u)
+	default:
+		web.WriteError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// handleUploadChunk 청크 수신 (PUT, Upload-Offset 헤더 필요)
+//
+// 전송이 중간에 끊어진 경우에도 수신한 데이터까지는 반영되며, 클라이언트는 상태 조회로
+// 확인한 위치부터 다시 전송한다.
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+//   - u: 업로드 정보
+func (m *Manager) handleUploadChunk(w http.ResponseWriter, r *http.Request, u *upload) {
+	offset, err := strconv.ParseInt(r.Header.Get(UploadOffsetHeader), 10, 64)
+	if err != nil || offset < 0 {
+		web.WriteError(w, http.StatusBadRequest, "invalid %s header", UploadOffsetHeader)
+		return
+	}
+
+	u.mu.Lock()
+	if u.finished {
+		u.mu.Unlock()
+		web.WriteError(w, http.StatusNotFound, "upload not found")
+		return
+	}
+	w.Header().Set(UploadOffsetHeader, strconv.FormatInt(u.offset, 10))
+	if u.writing || offset != u.offset {
+		defer u.mu.Unlock()
+		web.WriteJSON(w, http.StatusConflict, u.status())
+		return
+	}
+
+	remaining := u.size - u.offset
+	if r.ContentLength > remaining {
+		u.mu.Unlock()
+		web.WriteError(w, http.StatusRequestEntityTooLarge, "chunk exceeds upload size")
+		return
+	}
+	u.writing = true
+	u.mu.Unlock()
+
+	// 청크 수신 중에는 잠금을 해제하여 상태 조회, 취소, 만료 정리가 대기하지 않도록 함
+	written, err := io.Copy(u, http.MaxBytesReader(w, r.Body, remaining))
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.writing = false
+	u.offset += written
+	u.updatedAt = time.Now()
+	w.Header().Set(UploadOffsetHeader, strconv.FormatInt(u.offset, 10))
+	if u.finished {
+		web.WriteError(w, http.StatusNotFound, "upload not found")
+		return
+	}
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			web.WriteError(w, http.StatusRequestEntityTooLarge, "chunk exceeds upload size")
+			return
+		}
+		logger.Log.LogWarn("Upload chunk interrupted (id:%s, offset:%d): %s", u.id, u.offset, err)
+		web.WriteError(w, http.StatusBadRequest, "failed to receive chunk: %s", err)
+		return
+	}
+
+	web.WriteJSON(w, http.StatusOK, u.status())
+}
+
+// handleUploadComplete 체크섬 검증 후 임시 파일을 대상 경로로 이동
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+//   - u: 업로드 정보
+func (m *Manager) handleUploadComplete(w http.ResponseWriter, r *http.Request, u *upload) {
+	var req completeRequest
+	if r.ContentLength != 0 {
+		if err := web.ReadJSON(r, &req); err != nil {
+			web.WriteError(w, http.StatusBadRequest, "%s", err)
+			return
+		}
+	}
+	checksum, err := parseChecksum(req.Sha256)
+	if err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	u.mu.Lock()
+	if u.finished {
+		u.mu.Unlock()
+		web.WriteError(w, http.StatusNotFound, "upload not found")
+		return
+	}
+	if u.writing || u.offset != u.size {
+		defer u.mu.Unlock()
+		w.Header().Set(UploadOffsetHeader, strconv.FormatInt(u.offset, 10))
+		web.WriteError(w, http.StatusConflict, "upload is incomplete (%d/%d bytes)", u.offset, u.size)
+		return
+	}
+
+	// 체크섬 검증 없이는 완료할 수 없음 (업로드는 유지되어 체크섬을 포함하여 다시 요청 가능)
+	if u.checksum == "" && checksum == "" {
+		u.mu.Unlock()
+		web.WriteError(w, http.StatusBadRequest, "sha256 checksum is required")
+		return
+	}
+
+	sum := hex.EncodeToString(u.hash.Sum(nil))
+	for _, expected := range []string{u.checksum, checksum} {
+		if expected != "" && expected != sum {
+			u.mu.Unlock()
+			m.abortUpload(u)
+			logOperation(r, "upload discarded (id:%s, path:%s): checksum mismatch", u.id, u.path)
+			web.WriteError(w, http.StatusUnprocessableEntity, "checksum mismatch (expected:%s, actual:%s)", expected, sum)
+			return
+		}
+	}
+
+	u.finished = true
+	err = u.file.Sync()
+	if closeErr := u.file.Close(); err == nil {
+		err = closeErr
+	}
+	u.mu.Unlock()
+	m.removeUpload(u.id)
+
+	if err != nil {
+		m.removeTempFile(u)
+		web.WriteError(w, http.StatusInternalServerError, "failed to write file: %s", err)
+		return
+	}
+
+	var info file.Info
+	err = m.call(r, opCommit, &commitArgs{
+		TempPath:  u.tempPath,
+		Path:      u.path,
+		Perm:      u.perm,
+		Overwrite: u.overwrite,
+	}, &info)
+	audit.Record(r, session.Username(r.Context()), opCommit, u.path, fmt.Sprintf("size:%d, sha256:%s", u.size, sum), err)
+	if err != nil {
+		m.removeTempFile(u)
+		WriteError(w, err)
+		return
+	}
+
+	logOperation(r, "upload completed (id:%s, path:%s, size:%d, sha256:%s)", u.id, u.path, u.size, sum)
+	w.Header().Set("Digest", "sha-256="+sum)
+	web.WriteJSON(w, http.StatusOK, &info)
+}
+
+// lookupUpload 요청한 계정의 업로드 조회 (다른 계정의 업로드는 조회 불가)
+//
+// Parameters:
+//   - r: 요청 정보 (로그인 세션 포함)
+//   - id: 업로드 ID
+//
+// Returns:
+//   - *upload
+//   - bool: 존재(true), 미존재(false)
+func (m *Manager) lookupUpload(r *http.Request, id string) (*upload, bool) {
+	s, ok := session.FromContext(r.Context())
+	if !ok {
+		return nil, false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	u, exists := m.uploads[id]
+	if !exists || u.user.Uid != s.User.Uid {
+		return nil, false
+	}
+	return u, true
+}
+
+// removeUpload 업로드 목록에서 제거
+//
+// Parameters:
+//   - id: 업로드 ID
+func (m *Manager) removeUpload(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.uploads, id)
+}
+
+// abortUpload 업로드 취소 및 임시 파일 삭제
+//
+// Parameters:
+//   - u: 업로드 정보
+func (m *Manager) abortUpload(u *upload) {
+	u.mu.Lock()
+	if u.finished {
+		u.mu.Unlock()
+		return
+	}
+	u.finished = true
+	u.file.Close()
+	u.mu.Unlock()
+
+	m.removeUpload(u.id)
+	m.removeTempFile(u)
+}
+
+// removeTempFile 계정 권한으로 업로드 임시 파일 삭제
+//
+// Parameters:
+//   - u: 업로드 정보
+func (m *Manager) removeTempFile(u *upload) {
+	client, err := m.pool.Get(u.user)
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), uploadCleanupTimeout)
+		defer cancel()
+		err = client.Call(ctx, opDelete, &pathArgs{Path: u.tempPath}, nil)
+	}
+	if err != nil && !errors.Is(err, file.ErrNotFound) {
+		logger.Log.LogWarn("Failed to remove upload temp file (user:%s, path:%s): %s",
+			u.user.Username, u.tempPath, err)
+	}
+}
+
+// UploadReaper 일정 시간 동안 청크가 수신되지 않은 업로드를 주기적으로 정리하는 고루틴 작업
+//
+// Parameters:
+//   - ctx: 작업 종료 컨텍스트
+func (m *Manager) UploadReaper(ctx context.Context) {
+	ticker := time.NewTicker(uploadReapInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, u := range m.expiredUploads(now) {
+				logger.Log.LogInfo("Upload expired (id:%s, user:%s, path:%s)", u.id, u.user.Username, u.path)
+				m.abortUpload(u)
+			}
+		}
+	}
+}
+
+// AbortUploads 진행 중인 모든 업로드 취소 (서버 종료 시 호출)
+func (m *Manager) AbortUploads() {
+	m.mu.Lock()
+	uploads := make([]*upload, 0, len(m.uploads))
+	for _, u := range m.uploads {
+		uploads = append(uploads, u)
+	}
+	m.mu.Unlock()
+
+	for _, u := range uploads {
+		m.abortUpload(u)
+	}
+}
+
+// expiredUploads 만료된 업로드 목록 조회
+//
+// Parameters:
+//   - now: 현재 시각
+//
+// Returns:
+//   - []*upload: 만료된 업로드 목록
+func (m *Manager) expiredUploads(now time.Time) []*upload {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var expired []*upload
+	for _, u := range m.uploads {
+		// 청크 수신 중인 업로드는 만료 대상이 아님
+		u.mu.Lock()
+		if !u.writing && now.Sub(u.updatedAt) > uploadTimeout() {
+			expired = append(expired, u)
+		}
+		u.mu.Unlock()
+	}
+	return expired
+}
+
+// uploadTimeout 업로드 만료 시간 반환
+//
+// Returns:
+//   - time.Duration: 만료 시간
+func uploadTimeout() time.Duration {
+	return time.Duration(config.Current().UploadTimeout) * time.Minute
+}
+
+// newID 임의의 업로드 및 작업 ID 생성
+//
+// Returns:
+//   - string: ID
+//   - error: 성공(nil), 실패(error)
+func newID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate id: %s", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// parseChecksum SHA-256 체크섬 문자열 검증 (소문자 16진수로 변환)
+//
+// Parameters:
+//   - checksum: 체크섬 문자열 (빈 문자열 허용)
+//
+// Returns:
+//   - string: 정규화된 체크섬
+//   - error: 성공(nil), 실패(error)
+func parseChecksum(checksum string) (string, error) {
+	if checksum == "" {
+		return "", nil
+	}
+	checksum = strings.ToLower(checksum)
+	if decoded, err := hex.DecodeString(checksum); err != nil || len(decoded) != sha256.Size {
+		return "", fmt.Errorf("invalid sha256 checksum: %s", checksum)
+	}
+	return checksum, nil
+}
@@ -0,0 +1,443 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package filemanager
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sha256Hex 데이터의 SHA-256 (16진수)
+//
+// Parameters:
+//   - data: 데이터
+//
+// Returns:
+//   - string
+func sha256Hex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// createUpload 업로드 생성 후 업로드 ID 반환
+//
+// Parameters:
+//   - t: 테스트 정보
+//   - ts: 테스트 서버
+//   - path: 업로드 대상 경로
+//   - size: 파일 크기
+//   - checksum: 파일 전체의 SHA-256 (생략 시 빈 문자열)
+//
+// Returns:
+//   - string: 업로드 ID
+func createUpload(t *testing.T, ts *testServer, path string, size int64, checksum string) string {
+	t.Helper()
+	body, _ := json.Marshal(&uploadRequest{Path: path, Size: size, Sha256: checksum})
+	resp, data := ts.do(t, http.MethodPost, "upload", nil, strings.NewReader(string(body)))
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create upload: status %d: %s", resp.StatusCode, data)
+	}
+	var status uploadStatus
+	if err := json.Unmarshal([]byte(data), &status); err != nil {
+		t.Fatal(err)
+	}
+	return status.ID
+}
+
+// putChunk 청크 전송 후 응답 상태 코드와 응답의 업로드 위치 반환
+//
+// Parameters:
+//   - t: 테스트 정보
+//   - ts: 테스트 서버
+//   - id: 업로드 ID
+//   - offset: 청크 시작 위치
+//   - chunk: 청크 데이터
+//
+// Returns:
+//   - int: 응답 상태 코드
+//   - string: 응답 Upload-Offset 헤더
+func putChunk(t *testing.T, ts *testServer, id string, offset int64, chunk string) (int, string) {
+	t.Helper()
+	resp, _ := ts.do(t, http.MethodPut, "upload/"+id,
+		[]string{UploadOffsetHeader, strconv.FormatInt(offset, 10)}, strings.NewReader(chunk))
+	return resp.StatusCode, resp.Header.Get(UploadOffsetHeader)
+}
+
+// completeUpload 업로드 완료 요청 후 응답 상태 코드와 본문 반환
+//
+// Parameters:
+//   - t: 테스트 정보
+//   - ts: 테스트 서버
+//   - id: 업로드 ID
+//   - checksum: 파일 전체의 SHA-256 (생략 시 빈 문자열)
+//
+// Returns:
+//   - int: 응답 상태 코드
+//   - string: 응답 본문
+func completeUpload(t *testing.T, ts *testServer, id, checksum string) (int, string) {
+	t.Helper()
+	var body io.Reader
+	if checksum != "" {
+		data, _ := json.Marshal(&completeRequest{Sha256: checksum})
+		body = strings.NewReader(string(data))
+	}
+	resp, data := ts.do(t, http.MethodPost, "upload/"+id+"/complete", nil, body)
+	return resp.StatusCode, data
+}
+
+// uploadOffset 업로드 상태 조회 후 응답 상태 코드와 수신한 크기 반환
+//
+// Parameters:
+//   - t: 테스트 정보
+//   - ts: 테스트 서버
+//   - id: 업로드 ID
+//
+// Returns:
+//   - int: 응답 상태 코드
+//   - string: 응답 Upload-Offset 헤더
+func uploadOffset(t *testing.T, ts *testServer, id string) (int, string) {
+	t.Helper()
+	resp, _ := ts.do(t, http.MethodGet, "upload/"+id, nil, nil)
+	return resp.StatusCode, resp.Header.Get(UploadOffsetHeader)
+}
+
+// assertNoUploadFiles 대상 디렉터리에 업로드 임시 파일이 없는지 확인
+//
+// Parameters:
+//   - t: 테스트 정보
+//   - dir: 대상 디렉터리
+func assertNoUploadFiles(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), uploadTempPrefix) {
+			t.Errorf("upload temp file left behind: %s", e.Name())
+		}
+	}
+}
+
+func TestUploadChunks(t *testing.T) {
+	ts := newTestServer(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.txt")
+	content := "hello, world"
+	id := createUpload(t, ts, path, int64(len(content)), sha256Hex(content))
+
+	tests := []struct {
+		name       string
+		offset     int64
+		chunk      string
+		wantStatus int
+		wantOffset string
+	}{
+		{"offset ahead", 5, "world", http.StatusConflict, "0"},
+		{"first chunk", 0, "hello", http.StatusOK, "5"},
+		{"chunk sent again", 0, "hello", http.StatusConflict, "5"},
+		{"offset behind", 3, "lo, w", http.StatusConflict, "5"},
+		{"offset ahead after chunk", 7, "world", http.StatusConflict, "5"},
+		{"chunk exceeds size", 5, ", world!", http.StatusRequestEntityTooLarge, "5"},
+		{"empty chunk", 5, "", http.StatusOK, "5"},
+		{"last chunk", 5, ", world", http.StatusOK, "12"},
+		{"chunk after end", 12, "!", http.StatusRequestEntityTooLarge, "12"},
+	}
+	for _, tt := range tests {
+		status, offset := putChunk(t, ts, id, tt.offset, tt.chunk)
+		if status != tt.wantStatus || offset != tt.wantOffset {
+			t.Fatalf("%s: PUT offset %d = %d (offset %s), want %d (offset %s)",
+				tt.name, tt.offset, status, offset, tt.wantStatus, tt.wantOffset)
+		}
+	}
+
+	resp, _ := ts.do(t, http.MethodPut, "upload/"+id, []string{UploadOffsetHeader, "x"}, strings.NewReader("!"))
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("PUT with invalid offset header = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+
+	if status, body := completeUpload(t, ts, id, ""); status != http.StatusOK {
+		t.Fatalf("complete = %d: %s", status, body)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != content {
+		t.Fatalf("uploaded file = %q, %v, want %q", data, err, content)
+	}
+	assertNoUploadFiles(t, dir)
+
+	if status, _ := uploadOffset(t, ts, id); status != http.StatusNotFound {
+		t.Errorf("GET after complete = %d, want %d", status, http.StatusNotFound)
+	}
+}
+
+func TestUploadResumeAfterPartialChunk(t *testing.T) {
+	ts := newTestServer(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.txt")
+	content := "0123456789abcdef"
+	id := createUpload(t, ts, path, int64(len(content)), "")
+
+	// 청크 전송 중 연결 종료 (Content-Length보다 적게 전송)
+	conn, err := net.Dial("tcp", ts.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprintf(conn, "PUT %supload/%s HTTP/1.1\r\nHost: weblin\r\nCookie: %s\r\n%s: 0\r\nContent-Length: 10\r\n\r\n%s",
+		PathPrefix, id, ts.cookie, UploadOffsetHeader, content[:6])
+	conn.(*net.TCPConn).CloseWrite()
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatalf("failed to read response of partial chunk: %v", err)
+	}
+	resp.Body.Close()
+	conn.Close()
+	if resp.StatusCode != http.StatusBadRequest || resp.Header.Get(UploadOffsetHeader) != "6" {
+		t.Fatalf("partial chunk = %d (offset %s), want %d (offset 6)",
+			resp.StatusCode, resp.Header.Get(UploadOffsetHeader), http.StatusBadRequest)
+	}
+
+	// 수신한 데이터까지는 반영되어 상태 조회로 확인한 위치부터 다시 전송
+	if status, offset := uploadOffset(t, ts, id); status != http.StatusOK || offset != "6" {
+		t.Fatalf("GET = %d (offset %s), want %d (offset 6)", status, offset, http.StatusOK)
+	}
+	if status, offset := putChunk(t, ts, id, 0, content); status != http.StatusConflict || offset != "6" {
+		t.Fatalf("PUT from start = %d (offset %s), want %d (offset 6)", status, offset, http.StatusConflict)
+	}
+	if status, offset := putChunk(t, ts, id, 6, content[6:]); status != http.StatusOK || offset != "16" {
+		t.Fatalf("PUT resume = %d (offset %s), want %d (offset 16)", status, offset, http.StatusOK)
+	}
+
+	if status, body := completeUpload(t, ts, id, sha256Hex(content)); status != http.StatusOK {
+		t.Fatalf("complete = %d: %s", status, body)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != content {
+		t.Fatalf("uploaded file = %q, %v, want %q", data, err, content)
+	}
+}
+
+func TestUploadChecksumMismatch(t *testing.T) {
+	content := "hello, world"
+	tests := []struct {
+		name   string
+		create string
+		commit string
+	}{
+		{"create checksum", sha256Hex("hello, World"), ""},
+		{"complete checksum", "", sha256Hex("hello, World")},
+		{"complete checksum differs from create", sha256Hex(content), sha256Hex("hello, World")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTestServer(t)
+			dir := t.TempDir()
+			path := filepath.Join(dir, "data.txt")
+			id := createUpload(t, ts, path, int64(len(content)), tt.create)
+			if status, _ := putChunk(t, ts, id, 0, content); status != http.StatusOK {
+				t.Fatalf("PUT = %d", status)
+			}
+
+			status, body := completeUpload(t, ts, id, tt.commit)
+			if status != http.StatusUnprocessableEntity || !strings.Contains(body, "checksum mismatch") {
+				t.Fatalf("complete = %d: %s, want %d", status, body, http.StatusUnprocessableEntity)
+			}
+
+			// 체크섬이 다르면 업로드를 취소하고 임시 파일 삭제
+			if status, _ := uploadOffset(t, ts, id); status != http.StatusNotFound {
+				t.Errorf("GET after mismatch = %d, want %d", status, http.StatusNotFound)
+			}
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("target file created: %v", err)
+			}
+			assertNoUploadFiles(t, dir)
+		})
+	}
+}
+
+func TestUploadChecksumRequired(t *testing.T) {
+	ts := newTestServer(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.txt")
+	content := "hello, world"
+	id := createUpload(t, ts, path, int64(len(content)), "")
+
+	if status, body := completeUpload(t, ts, id, ""); status != http.StatusConflict {
+		t.Fatalf("complete before all chunks = %d: %s, want %d", status, body, http.StatusConflict)
+	}
+	if status, _ := putChunk(t, ts, id, 0, content); status != http.StatusOK {
+		t.Fatalf("PUT = %d", status)
+	}
+
+	status, body := completeUpload(t, ts, id, "")
+	if status != http.StatusBadRequest || !strings.Contains(body, "sha256 checksum is required") {
+		t.Fatalf("complete without checksum = %d: %s, want %d", status, body, http.StatusBadRequest)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("target file created without checksum: %v", err)
+	}
+
+	// 업로드는 유지되어 체크섬을 포함하여 다시 요청 가능
+	if status, offset := uploadOffset(t, ts, id); status != http.StatusOK || offset != "12" {
+		t.Fatalf("GET = %d (offset %s), want %d (offset 12)", status, offset, http.StatusOK)
+	}
+	if status, body := completeUpload(t, ts, id, "not-a-checksum"); status != http.StatusBadRequest {
+		t.Fatalf("complete with invalid checksum = %d: %s, want %d", status, body, http.StatusBadRequest)
+	}
+	if status, body := completeUpload(t, ts, id, sha256Hex(content)); status != http.StatusOK {
+		t.Fatalf("complete = %d: %s", status, body)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != content {
+		t.Fatalf("uploaded file = %q, %v, want %q", data, err, content)
+	}
+}
+
+// startSlowChunk 본문을 pipe로 전송하는 청크 요청을 시작하고, 서버가 청크 수신을 시작할 때까지 대기
+//
+// Parameters:
+//   - t: 테스트 정보
+//   - ts: 테스트 서버
+//   - id: 업로드 ID
+//   - first: 먼저 전송할 데이터
+//
+// Returns:
+//   - *io.PipeWriter: 나머지 본문 writer (닫으면 요청 완료)
+//   - <-chan string: 청크 요청의 응답 상태 코드와 Upload-Offset ("<상태 코드> <위치>")
+func startSlowChunk(t *testing.T, ts *testServer, id, first string) (*io.PipeWriter, <-chan string) {
+	t.Helper()
+	pr, pw := io.Pipe()
+	req, err := http.NewRequest(http.MethodPut, ts.URL+PathPrefix+"upload/"+id, pr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.AddCookie(ts.cookie)
+	req.Header.Set(UploadOffsetHeader, "0")
+	// 테스트가 실패해도 요청이 종료되어 테스트 서버를 닫을 수 있도록 함
+	t.Cleanup(func() { pw.CloseWithError(io.ErrUnexpectedEOF) })
+
+	result := make(chan string, 1)
+	go func() {
+		resp, err := ts.Client().Do(req)
+		if err != nil {
+			result <- err.Error()
+			return
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		result <- fmt.Sprintf("%d %s", resp.StatusCode, resp.Header.Get(UploadOffsetHeader))
+	}()
+	if _, err := pw.Write([]byte(first)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 서버가 청크 수신을 시작하면 다른 청크 전송은 거부됨 (빈 청크는 수신 전이면 아무것도 변경하지 않음)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		status, _ := putChunk(t, ts, id, 0, "")
+		if status == http.StatusConflict {
+			return pw, result
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("chunk upload did not start (last status %d)", status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestUploadWhileWriting(t *testing.T) {
+	ts := newTestServer(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.txt")
+	content := "hello, world"
+	id := createUpload(t, ts, path, int64(len(content)), sha256Hex(content))
+
+	pw, result := startSlowChunk(t, ts, id, content[:5])
+
+	// 청크 수신 중에도 상태 조회는 대기하지 않으며, 다른 청크 전송과 완료 요청은 거부
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if status, offset := uploadOffset(t, ts, id); status != http.StatusOK || offset != "0" {
+			t.Errorf("GET while writing = %d (offset %s), want %d (offset 0)", status, offset, http.StatusOK)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("GET blocked while a chunk is being written")
+	}
+	if status, offset := putChunk(t, ts, id, 0, content); status != http.StatusConflict || offset != "0" {
+		t.Errorf("concurrent PUT = %d (offset %s), want %d (offset 0)", status, offset, http.StatusConflict)
+	}
+	if status, body := completeUpload(t, ts, id, ""); status != http.StatusConflict {
+		t.Errorf("complete while writing = %d: %s, want %d", status, body, http.StatusConflict)
+	}
+
+	if _, err := pw.Write([]byte(content[5:])); err != nil {
+		t.Fatal(err)
+	}
+	pw.Close()
+	if got := <-result; got != "200 12" {
+		t.Fatalf("slow PUT = %s, want 200 12", got)
+	}
+
+	if status, body := completeUpload(t, ts, id, ""); status != http.StatusOK {
+		t.Fatalf("complete = %d: %s", status, body)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != content {
+		t.Fatalf("uploaded file = %q, %v, want %q", data, err, content)
+	}
+}
+
+func TestUploadAbortWhileWriting(t *testing.T) {
+	ts := newTestServer(t)
+	dir := t.TempDir()
+	content := "hello, world"
+	id := createUpload(t, ts, filepath.Join(dir, "data.txt"), int64(len(content)), "")
+
+	pw, result := startSlowChunk(t, ts, id, content[:5])
+
+	resp, _ := ts.do(t, http.MethodDelete, "upload/"+id, nil, nil)
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("DELETE while writing = %d, want %d", resp.StatusCode, http.StatusNoContent)
+	}
+	assertNoUploadFiles(t, dir)
+
+	// 취소된 업로드의 청크 수신은 실패하며 임시 파일을 다시 만들지 않음
+	pw.Write([]byte(content[5:]))
+	pw.Close()
+	if got := <-result; !strings.HasPrefix(got, "404 ") {
+		t.Fatalf("slow PUT after abort = %s, want 404", got)
+	}
+	if status, _ := uploadOffset(t, ts, id); status != http.StatusNotFound {
+		t.Errorf("GET after abort = %d, want %d", status, http.StatusNotFound)
+	}
+	assertNoUploadFiles(t, dir)
+}
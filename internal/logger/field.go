@@ -0,0 +1,153 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package logger
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// 공통 필드 이름
+const (
+	// KeyRequestID 웹 요청 ID
+	KeyRequestID = "request_id"
+	// KeySessionID 로그인 세션 공개 ID
+	KeySessionID = "session_id"
+	// KeyUser 로그인 계정 이름
+	KeyUser = "user"
+	// KeyRemote 요청 원격 주소
+	KeyRemote = "remote"
+	// KeyTerminalID 웹 터미널 세션 ID
+	KeyTerminalID = "terminal_id"
+)
+
+// Field 구조화 로그 필드 (JSON 로그에는 개별 키로, 텍스트 로그에는 메시지 뒤에 기록)
+type Field = zap.Field
+
+// contextKey 요청 컨텍스트 키 타입
+type contextKey struct{}
+
+// String 문자열 필드 생성
+//
+// Parameters:
+//   - key: 필드 이름
+//   - value: 필드 값
+//
+// Returns:
+//   - Field
+func String(key, value string) Field {
+	return zap.String(key, value)
+}
+
+// Int 정수 필드 생성
+//
+// Parameters:
+//   - key: 필드 이름
+//   - value: 필드 값
+//
+// Returns:
+//   - Field
+func Int(key string, value int) Field {
+	return zap.Int(key, value)
+}
+
+// Int64 정수 필드 생성
+//
+// Parameters:
+//   - key: 필드 이름
+//   - value: 필드 값
+//
+// Returns:
+//   - Field
+func Int64(key string, value int64) Field {
+	return zap.Int64(key, value)
+}
+
+// Bool 참/거짓 필드 생성
+//
+// Parameters:
+//   - key: 필드 이름
+//   - value: 필드 값
+//
+// Returns:
+//   - Field
+func Bool(key string, value bool) Field {
+	return zap.Bool(key, value)
+}
+
+// Duration 시간 간격 필드 생성
+//
+// Parameters:
+//   - key: 필드 이름
+//   - value: 필드 값
+//
+// Returns:
+//   - Field
+func Duration(key string, value time.Duration) Field {
+	return zap.Duration(key, value)
+}
+
+// Err 에러 필드 생성 (필드 이름: error)
+//
+// Parameters:
+//   - err: 에러
+//
+// Returns:
+//   - Field
+func Err(err error) Field {
+	return zap.Error(err)
+}
+
+// Any 임의 값 필드 생성 (값 유형에 맞게 기록)
+//
+// Parameters:
+//   - key: 필드 이름
+//   - value: 필드 값
+//
+// Returns:
+//   - Field
+func Any(key string, value interface{}) Field {
+	return zap.Any(key, value)
+}
+
+// NewContext 로거를 저장한 컨텍스트 반환
+//
+// Parameters:
+//   - ctx: 상위 컨텍스트
+//   - l: 저장할 로거 (With로 생성한 하위 로거)
+//
+// Returns:
+//   - context.Context
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext 컨텍스트에 저장된 로거 반환 (저장된 로거가 없을 경우 Log)
+//
+// Parameters:
+//   - ctx: 컨텍스트
+//
+// Returns:
+//   - Logger
+func FromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(contextKey{}).(Logger); ok {
+		return l
+	}
+	return Log
+}
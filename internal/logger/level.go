@@ -0,0 +1,209 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package logger
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/hoon-kr/weblin/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// 로그 출력 대상 정의
+const (
+	// SinkText 텍스트 로그 파일 (log/weblin.log)
+	SinkText = "text"
+	// SinkJson JSON 로그 파일 (log/weblin_json.log)
+	SinkJson = "json"
+	// SinkConsole 표준 출력 (디버그 모드: stdout, 포그라운드 가동: stderr)
+	SinkConsole = "console"
+)
+
+// 터미널 출력용 로그 레벨 색상 (ANSI escape code)
+var levelColors = map[zapcore.Level]string{
+	zapcore.DebugLevel: "\x1b[35m",
+	zapcore.InfoLevel:  "\x1b[34m",
+	zapcore.WarnLevel:  "\x1b[33m",
+	zapcore.ErrorLevel: "\x1b[31m",
+}
+
+// 색상 초기화 (ANSI escape code)
+const colorReset = "\x1b[0m"
+
+// newLevels 사용하는 로그 출력 대상별 변경 가능한 로그 레벨 생성
+//
+// Parameters:
+//   - conf: 설정 정보
+//   - console: 표준 출력 사용 여부
+//
+// Returns:
+//   - map[string]zap.AtomicLevel: 로그 출력 대상별 로그 레벨
+func (s *SyncLogger) newLevels(conf *config.Config, console bool) map[string]zap.AtomicLevel {
+	levels := map[string]zap.AtomicLevel{
+		SinkText: zap.NewAtomicLevel(),
+		SinkJson: zap.NewAtomicLevel(),
+	}
+	if console {
+		levels[SinkConsole] = zap.NewAtomicLevel()
+	}
+
+	for sink, level := range levels {
+		level.SetLevel(configuredLevel(conf, sink))
+	}
+	return levels
+}
+
+// SetLevel 가동 중 로그 출력 대상의 로그 레벨 변경 (설정 다시 읽기로 해당 설정이 변경되면 설정 값으로 변경됨)
+//
+// Parameters:
+//   - sink: 로그 출력 대상 (text, json, console, 빈 문자열일 경우 전체)
+//   - level: 로그 레벨 (debug, info, warn, error)
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func (s *SyncLogger) SetLevel(sink, level string) error {
+	if !slices.Contains(config.LogLevels, level) {
+		return fmt.Errorf("invalid log level %q (expected one of %v)", level, config.LogLevels)
+	}
+	zapLevel, _ := zapcore.ParseLevel(level)
+
+	if sink == "" {
+		for _, atomicLevel := range s.levels {
+			atomicLevel.SetLevel(zapLevel)
+		}
+		return nil
+	}
+
+	atomicLevel, exists := s.levels[sink]
+	if !exists {
+		return fmt.Errorf("log sink %q is not in use (available: %v)", sink, s.sinks())
+	}
+	atomicLevel.SetLevel(zapLevel)
+	return nil
+}
+
+// Levels 로그 출력 대상별 현재 로그 레벨 반환
+//
+// Returns:
+//   - map[string]string: 로그 출력 대상별 로그 레벨
+func (s *SyncLogger) Levels() map[string]string {
+	levels := make(map[string]string, len(s.levels))
+	for sink, atomicLevel := range s.levels {
+		levels[sink] = atomicLevel.Level().String()
+	}
+	return levels
+}
+
+// ToggleDebug 전체 로그 출력 대상의 DEBUG 레벨 전환 (SIGUSR1)
+//
+// 모든 출력 대상이 DEBUG 레벨일 경우 설정 값으로 되돌리고, 아닐 경우 모두 DEBUG 레벨로 변경한다.
+//
+// Returns:
+//   - bool: DEBUG 레벨로 변경(true), 설정 값으로 되돌림(false)
+func (s *SyncLogger) ToggleDebug() bool {
+	allDebug := true
+	for _, atomicLevel := range s.levels {
+		if atomicLevel.Level() != zapcore.DebugLevel {
+			allDebug = false
+			break
+		}
+	}
+
+	conf := config.Current()
+	for sink, atomicLevel := range s.levels {
+		if allDebug {
+			atomicLevel.SetLevel(configuredLevel(conf, sink))
+		} else {
+			atomicLevel.SetLevel(zapcore.DebugLevel)
+		}
+	}
+	return !allDebug
+}
+
+// applyLevels 설정 다시 읽기 시 변경된 로그 레벨 설정 적용
+//
+// Parameters:
+//   - old: 이전 설정 정보
+//   - new: 새 설정 정보
+func (s *SyncLogger) applyLevels(old, new *config.Config) {
+	for sink, atomicLevel := range s.levels {
+		if configuredLevel(old, sink) != configuredLevel(new, sink) {
+			atomicLevel.SetLevel(configuredLevel(new, sink))
+		}
+	}
+}
+
+// sinks 사용 중인 로그 출력 대상 목록 반환
+//
+// Returns:
+//   - []string: 로그 출력 대상 목록 (이름순)
+func (s *SyncLogger) sinks() []string {
+	sinks := make([]string, 0, len(s.levels))
+	for sink := range s.levels {
+		sinks = append(sinks, sink)
+	}
+	sort.Strings(sinks)
+	return sinks
+}
+
+// colorLevelEncoder 터미널 출력용 색상을 입힌 capitalLevelEncoder
+//
+// Parameters:
+//   - l: zapcore 로그 레벨
+//   - enc: zapcore 배열 인터페이스
+func (s *SyncLogger) colorLevelEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	color, exists := levelColors[l]
+	if !exists {
+		// PANIC, FATAL은 ERROR와 같은 색상
+		color = levelColors[zapcore.ErrorLevel]
+	}
+	enc.AppendString(color + "[" + l.CapitalString() + "]" + colorReset)
+}
+
+// configuredLevel 설정 정보의 로그 출력 대상별 로그 레벨 반환 (디버그 모드일 경우 DEBUG)
+//
+// Parameters:
+//   - conf: 설정 정보
+//   - sink: 로그 출력 대상
+//
+// Returns:
+//   - zapcore.Level: 로그 레벨
+func configuredLevel(conf *config.Config, sink string) zapcore.Level {
+	if config.RunConf.DebugMode {
+		return zapcore.DebugLevel
+	}
+
+	var level string
+	switch sink {
+	case SinkText:
+		level = conf.TextLogLevel
+	case SinkJson:
+		level = conf.JsonLogLevel
+	case SinkConsole:
+		level = conf.ConsoleLogLevel
+	}
+
+	// 설정 값은 로드 시 검증되므로 해석에 실패하지 않음
+	zapLevel, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return zapcore.InfoLevel
+	}
+	return zapLevel
+}
@@ -21,9 +21,11 @@ package logger
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/hoon-kr/weblin/config"
+	"github.com/hoon-kr/weblin/internal/metrics"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -33,28 +35,44 @@ import (
 type Logger interface {
 	InitializeLogger()
 	FinalizeLogger()
+	ApplyConfig(old, new *config.Config)
+	SetLevel(sink, level string) error
+	Levels() map[string]string
+	ToggleDebug() bool
 	LogInfo(format string, args ...interface{})
 	LogWarn(format string, args ...interface{})
 	LogError(format string, args ...interface{})
 	LogDebug(format string, args ...interface{})
 	LogPanic(format string, args ...interface{})
 	LogFatal(format string, args ...interface{})
+	Debug(msg string, fields ...Field)
+	Info(msg string, fields ...Field)
+	Warn(msg string, fields ...Field)
+	Error(msg string, fields ...Field)
+	With(fields ...Field) Logger
 }
 
 // SyncLogger 로그 관리 정보 구조체
 type SyncLogger struct {
-	consoleFileLogger *lumberjack.Logger
-	jsonFileLogger    *lumberjack.Logger
+	consoleFileLogger *rotateWriter
+	jsonFileLogger    *rotateWriter
 	zapLogger         *zap.Logger
+	// 로그 출력 대상별 로그 레벨 (가동 중 변경 가능)
+	levels map[string]zap.AtomicLevel
 }
 
 var Log Logger = &SyncLogger{}
 
+// logLines 레벨별 기록된 로그 수
+var logLines = metrics.NewCounterVec("weblin_log_lines_total",
+	"Total number of log lines written by level.", "level")
+
 // InitializeLogger 로거 초기화
 func (s *SyncLogger) InitializeLogger() {
 	// Lumberjack 생성 (자동으로 로그 파일 관리)
-	s.consoleFileLogger = s.newLumberJackLogger(config.ConsoleLogFilePath)
-	s.jsonFileLogger = s.newLumberJackLogger(config.JsonLogFilePath)
+	conf := config.Current()
+	s.consoleFileLogger = &rotateWriter{logger: s.newLumberJackLogger(conf, config.Paths().ConsoleLogFile)}
+	s.jsonFileLogger = &rotateWriter{logger: s.newLumberJackLogger(conf, config.Paths().JsonLogFile)}
 
 	// 인코더 설정
 	consoleEncoderConfig := zapcore.EncoderConfig{
@@ -93,15 +111,31 @@ func (s *SyncLogger) InitializeLogger() {
 	consoleWriter := zapcore.AddSync(s.consoleFileLogger)
 	jsonWriter := zapcore.AddSync(s.jsonFileLogger)
 
+	// 로그 출력 대상별 로그 레벨 생성 (디버그 모드일 경우 DEBUG)
+	useConsole := config.RunConf.DebugMode || config.RunConf.Foreground
+	s.levels = s.newLevels(conf, useConsole)
+
 	// 코어 생성
-	core := zapcore.NewTee(
-		zapcore.NewCore(consoleEncoder, consoleWriter, zapcore.InfoLevel),
-		zapcore.NewCore(jsonEncoder, jsonWriter, zapcore.InfoLevel),
-	)
+	cores := []zapcore.Core{
+		zapcore.NewCore(consoleEncoder, consoleWriter, s.levels[SinkText]),
+		zapcore.NewCore(jsonEncoder, jsonWriter, s.levels[SinkJson]),
+	}
+	switch {
+	case config.RunConf.DebugMode:
+		// 디버그 모드일 경우 stdout에 색상을 입혀 출력
+		colorEncoderConfig := consoleEncoderConfig
+		colorEncoderConfig.EncodeLevel = s.colorLevelEncoder
+		cores = append(cores, zapcore.NewCore(zapcore.NewConsoleEncoder(colorEncoderConfig),
+			zapcore.Lock(os.Stdout), s.levels[SinkConsole]))
+	case config.RunConf.Foreground:
+		// 포그라운드 가동 시 stderr에도 출력 (systemd journal 등에서 수집)
+		cores = append(cores, zapcore.NewCore(consoleEncoder, zapcore.Lock(os.Stderr), s.levels[SinkConsole]))
+	}
+	core := zapcore.NewTee(cores...)
 
 	// 코어로 부터 로거 생성
 	s.zapLogger = zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1),
-		zap.AddStacktrace(zapcore.PanicLevel))
+		zap.AddStacktrace(zapcore.PanicLevel), zap.Hooks(s.countLine))
 }
 
 // FinalizeLogger 프로그램 종료 시 로그 자원 정리
@@ -113,23 +147,53 @@ func (s *SyncLogger) FinalizeLogger() {
 	s.jsonFileLogger.Close()
 }
 
+// ApplyConfig 설정 다시 읽기 시 변경된 로그 레벨 및 로그 파일 관리 정책 적용 (config.Subscribe 등록용)
+//
+// Parameters:
+//   - old: 이전 설정 정보
+//   - new: 새 설정 정보
+func (s *SyncLogger) ApplyConfig(old, new *config.Config) {
+	s.applyLevels(old, new)
+
+	if old.MaxLogFileSize == new.MaxLogFileSize && old.MaxLogFileBackup == new.MaxLogFileBackup &&
+		old.MaxLogFileAge == new.MaxLogFileAge && old.CompBakLogFile == new.CompBakLogFile {
+		return
+	}
+
+	s.consoleFileLogger.swap(s.newLumberJackLogger(new, config.Paths().ConsoleLogFile))
+	s.jsonFileLogger.swap(s.newLumberJackLogger(new, config.Paths().JsonLogFile))
+}
+
 // newLumberJackLogger Lumberjack 생성
 //
 // Parameters:
+//   - conf: 설정 정보
 //   - logFilePath: 로그 파일 경로
 //
 // Returns:
 //   - *lumberjack.Logger
-func (s *SyncLogger) newLumberJackLogger(logFilePath string) *lumberjack.Logger {
+func (s *SyncLogger) newLumberJackLogger(conf *config.Config, logFilePath string) *lumberjack.Logger {
 	return &lumberjack.Logger{
 		Filename:   logFilePath,
-		MaxSize:    config.Conf.MaxLogFileSize,
-		MaxBackups: config.Conf.MaxLogFileBackup,
-		MaxAge:     config.Conf.MaxLogFileAge,
-		Compress:   config.Conf.CompBakLogFile,
+		MaxSize:    conf.MaxLogFileSize,
+		MaxBackups: conf.MaxLogFileBackup,
+		MaxAge:     conf.MaxLogFileAge,
+		Compress:   conf.CompBakLogFile,
 	}
 }
 
+// countLine 레벨별 기록된 로그 수 집계 (zap hook)
+//
+// Parameters:
+//   - entry: 로그 항목
+//
+// Returns:
+//   - error: 항상 nil
+func (s *SyncLogger) countLine(entry zapcore.Entry) error {
+	logLines.Inc(entry.Level.String())
+	return nil
+}
+
 // capitalLevelEncoder zapcore의 CapitalLevelEncoder() 메서드 커스터마이징 함수
 // Parameters:
 //   - l: zapcore 로그 레벨
@@ -252,3 +316,55 @@ func (s *SyncLogger) LogFatal(format string, args ...interface{}) {
 	message := fmt.Sprintf(format, args...)
 	s.zapLogger.Fatal(message)
 }
+
+// Debug 구조화 로그 기록 (로그 레벨:DEBUG)
+//
+// Parameters:
+//   - msg: 로그 메시지
+//   - fields: 로그 필드
+func (s *SyncLogger) Debug(msg string, fields ...Field) {
+	s.zapLogger.Debug(msg, fields...)
+}
+
+// Info 구조화 로그 기록 (로그 레벨:INFO)
+//
+// Parameters:
+//   - msg: 로그 메시지
+//   - fields: 로그 필드
+func (s *SyncLogger) Info(msg string, fields ...Field) {
+	s.zapLogger.Info(msg, fields...)
+}
+
+// Warn 구조화 로그 기록 (로그 레벨:WARN)
+//
+// Parameters:
+//   - msg: 로그 메시지
+//   - fields: 로그 필드
+func (s *SyncLogger) Warn(msg string, fields ...Field) {
+	s.zapLogger.Warn(msg, fields...)
+}
+
+// Error 구조화 로그 기록 (로그 레벨:ERROR)
+//
+// Parameters:
+//   - msg: 로그 메시지
+//   - fields: 로그 필드
+func (s *SyncLogger) Error(msg string, fields ...Field) {
+	s.zapLogger.Error(msg, fields...)
+}
+
+// With 필드를 추가한 하위 로거 생성
+//
+// 하위 로거가 기록하는 모든 로그(printf 형식 포함)에 필드가 추가되며,
+// 로그 파일과 로그 레벨은 상위 로거와 공유한다. 로거 초기화 후에 호출해야 한다.
+//
+// Parameters:
+//   - fields: 추가할 로그 필드
+//
+// Returns:
+//   - Logger: 하위 로거
+func (s *SyncLogger) With(fields ...Field) Logger {
+	child := *s
+	child.zapLogger = s.zapLogger.With(fields...)
+	return &child
+}
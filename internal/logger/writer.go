@@ -0,0 +1,78 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package logger
+
+import (
+	"sync"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// rotateWriter 로그 파일 관리 정책(Lumberjack)을 가동 중 교체할 수 있는 writer 구조체
+//
+// Lumberjack의 설정 필드는 내부 고루틴에서도 읽으므로 값을 변경하지 않고 새 Lumberjack으로 교체한다.
+type rotateWriter struct {
+	mu     sync.Mutex
+	logger *lumberjack.Logger
+}
+
+// Write io.Writer 구현
+//
+// Parameters:
+//   - p: 로그 메시지
+//
+// Returns:
+//   - int: 기록한 바이트 수
+//   - error: 성공(nil), 실패(error)
+func (w *rotateWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.logger.Write(p)
+}
+
+// Sync zapcore.WriteSyncer 구현 (Lumberjack은 버퍼를 사용하지 않음)
+//
+// Returns:
+//   - error: 항상 nil
+func (w *rotateWriter) Sync() error {
+	return nil
+}
+
+// Close 로그 파일 닫기
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func (w *rotateWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.logger.Close()
+}
+
+// swap 새 Lumberjack으로 교체하고 이전 Lumberjack의 로그 파일 닫기
+//
+// Parameters:
+//   - logger: 새 Lumberjack (같은 로그 파일에 이어서 기록)
+func (w *rotateWriter) swap(logger *lumberjack.Logger) {
+	w.mu.Lock()
+	old := w.logger
+	w.logger = logger
+	w.mu.Unlock()
+
+	old.Close()
+}
@@ -0,0 +1,415 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+/*
+Package metrics Prometheus 텍스트 형식(exposition format) 내부 지표 패키지
+
+각 패키지는 지표를 패키지 변수로 생성(생성 시 기본 레지스트리에 등록)하여 갱신하고,
+Handler는 등록된 모든 지표를 이름 순으로 출력한다.
+*/
+package metrics
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType Prometheus 텍스트 형식 Content-Type
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefBuckets 기본 히스토그램 구간 (초)
+var DefBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// collector 레지스트리에 등록되는 지표 인터페이스
+type collector interface {
+	// name 지표 이름
+	name() string
+	// write 지표를 텍스트 형식으로 출력
+	write(w *bufio.Writer)
+}
+
+// registry 지표 등록 정보 구조체
+type registry struct {
+	mu         sync.Mutex
+	collectors map[string]collector
+}
+
+// defaultRegistry 지표 생성 시 등록되는 기본 레지스트리
+var defaultRegistry = &registry{collectors: make(map[string]collector)}
+
+// register 기본 레지스트리에 지표 등록 (같은 이름이 이미 등록된 경우 panic)
+//
+// Parameters:
+//   - c: 지표
+func register(c collector) {
+	defaultRegistry.mu.Lock()
+	defer defaultRegistry.mu.Unlock()
+
+	if _, exists := defaultRegistry.collectors[c.name()]; exists {
+		panic(fmt.Sprintf("metrics: duplicate metric name: %s", c.name()))
+	}
+	defaultRegistry.collectors[c.name()] = c
+}
+
+// Handler 기본 레지스트리의 지표를 출력하는 HTTP 핸들러 반환
+//
+// Parameters:
+//   - token: Bearer 토큰 (빈 문자열일 경우 인증하지 않음)
+//
+// Returns:
+//   - http.Handler
+func Handler(token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if token != "" {
+			auth, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !found || subtle.ConstantTimeCompare([]byte(auth), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", ContentType)
+		w.Header().Set("Cache-Control", "no-store")
+		bw := bufio.NewWriter(w)
+		defaultRegistry.write(bw)
+		bw.Flush()
+	})
+}
+
+// write 등록된 모든 지표를 이름 순으로 출력
+//
+// Parameters:
+//   - w: 출력 writer
+func (reg *registry) write(w *bufio.Writer) {
+	reg.mu.Lock()
+	collectors := make([]collector, 0, len(reg.collectors))
+	for _, c := range reg.collectors {
+		collectors = append(collectors, c)
+	}
+	reg.mu.Unlock()
+
+	sort.Slice(collectors, func(i, j int) bool { return collectors[i].name() < collectors[j].name() })
+	for _, c := range collectors {
+		c.write(w)
+	}
+}
+
+// desc 지표 공통 정보 구조체
+type desc struct {
+	metricName string
+	help       string
+	typ        string
+	labels     []string
+}
+
+// name 지표 이름
+func (d *desc) name() string {
+	return d.metricName
+}
+
+// writeHeader HELP, TYPE 주석 출력
+//
+// Parameters:
+//   - w: 출력 writer
+func (d *desc) writeHeader(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", d.metricName, strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(d.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", d.metricName, d.typ)
+}
+
+// labelKey 레이블 값 목록을 맵 키로 변환
+//
+// Parameters:
+//   - values: 레이블 값 목록 (레이블 수와 다를 경우 panic)
+//
+// Returns:
+//   - string: 맵 키
+func (d *desc) labelKey(values []string) string {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("metrics: %s: expected %d label values, got %d", d.metricName, len(d.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// formatLabels 레이블을 텍스트 형식으로 변환 (예: {route="/api",code="200"})
+//
+// Parameters:
+//   - key: labelKey로 생성한 맵 키
+//   - extra: 추가 레이블 (예: le="0.5", 빈 문자열일 경우 생략)
+//
+// Returns:
+//   - string: 레이블 문자열 (레이블이 없을 경우 빈 문자열)
+func (d *desc) formatLabels(key, extra string) string {
+	var pairs []string
+	if len(d.labels) > 0 {
+		for i, value := range strings.Split(key, "\xff") {
+			pairs = append(pairs, d.labels[i]+`="`+escapeLabel(value)+`"`)
+		}
+	}
+	if extra != "" {
+		pairs = append(pairs, extra)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// escapeLabel 레이블 값 이스케이프
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// formatValue 지표 값을 텍스트 형식으로 변환
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+// sortedKeys 맵 키를 정렬하여 반환
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// CounterVec 레이블별 누적 카운터 구조체
+type CounterVec struct {
+	desc
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewCounterVec 레이블별 누적 카운터 생성 및 등록
+//
+// Parameters:
+//   - name: 지표 이름
+//   - help: 지표 설명
+//   - labels: 레이블 이름 목록
+//
+// Returns:
+//   - *CounterVec
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{
+		desc:   desc{metricName: name, help: help, typ: "counter", labels: labels},
+		values: make(map[string]float64),
+	}
+	register(c)
+	return c
+}
+
+// Inc 카운터 1 증가
+//
+// Parameters:
+//   - labelValues: 레이블 값 목록 (레이블 이름 순서)
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add 카운터 증가 (음수는 무시)
+//
+// Parameters:
+//   - value: 증가 값
+//   - labelValues: 레이블 값 목록 (레이블 이름 순서)
+func (c *CounterVec) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		return
+	}
+	key := c.labelKey(labelValues)
+	c.mu.Lock()
+	c.values[key] += value
+	c.mu.Unlock()
+}
+
+// write 카운터 출력
+func (c *CounterVec) write(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.writeHeader(w)
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s%s %s\n", c.metricName, c.formatLabels(key, ""), formatValue(c.values[key]))
+	}
+}
+
+// histogram 레이블 조합별 히스토그램 값 구조체
+type histogram struct {
+	// 구간별 관측 수 (누적 아님)
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// HistogramVec 레이블별 히스토그램 구조체
+type HistogramVec struct {
+	desc
+	buckets []float64
+	mu      sync.Mutex
+	values  map[string]*histogram
+}
+
+// NewHistogramVec 레이블별 히스토그램 생성 및 등록
+//
+// Parameters:
+//   - name: 지표 이름
+//   - help: 지표 설명
+//   - buckets: 구간 상한 목록 (오름차순, +Inf는 자동 추가)
+//   - labels: 레이블 이름 목록
+//
+// Returns:
+//   - *HistogramVec
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	h := &HistogramVec{
+		desc:    desc{metricName: name, help: help, typ: "histogram", labels: labels},
+		buckets: buckets,
+		values:  make(map[string]*histogram),
+	}
+	register(h)
+	return h
+}
+
+// Observe 관측 값 기록
+//
+// Parameters:
+//   - value: 관측 값
+//   - labelValues: 레이블 값 목록 (레이블 이름 순서)
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	key := h.labelKey(labelValues)
+	i := sort.SearchFloat64s(h.buckets, value)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	v, exists := h.values[key]
+	if !exists {
+		v = &histogram{counts: make([]uint64, len(h.buckets)+1)}
+		h.values[key] = v
+	}
+	v.counts[i]++
+	v.count++
+	v.sum += value
+}
+
+// write 히스토그램 출력 (구간별 누적 관측 수, 합계, 전체 관측 수)
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.writeHeader(w)
+	for _, key := range sortedKeys(h.values) {
+		v := h.values[key]
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += v.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName,
+				h.formatLabels(key, `le="`+formatValue(upper)+`"`), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, h.formatLabels(key, `le="+Inf"`), v.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.metricName, h.formatLabels(key, ""), formatValue(v.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.metricName, h.formatLabels(key, ""), v.count)
+	}
+}
+
+// FuncVec 출력 시점에 함수로 값을 조회하는 지표 구조체
+type FuncVec struct {
+	desc
+	fn func() map[string]float64
+}
+
+// NewGaugeFunc 출력 시점에 값을 조회하는 게이지 생성 및 등록
+//
+// Parameters:
+//   - name: 지표 이름
+//   - help: 지표 설명
+//   - fn: 값 조회 함수
+//
+// Returns:
+//   - *FuncVec
+func NewGaugeFunc(name, help string, fn func() float64) *FuncVec {
+	return newFuncVec(name, help, "gauge", "", func() map[string]float64 {
+		return map[string]float64{"": fn()}
+	})
+}
+
+// NewGaugeFuncVec 출력 시점에 레이블별 값을 조회하는 게이지 생성 및 등록
+//
+// Parameters:
+//   - name: 지표 이름
+//   - help: 지표 설명
+//   - label: 레이블 이름
+//   - fn: 레이블 값별 값 조회 함수
+//
+// Returns:
+//   - *FuncVec
+func NewGaugeFuncVec(name, help, label string, fn func() map[string]float64) *FuncVec {
+	return newFuncVec(name, help, "gauge", label, fn)
+}
+
+// newFuncVec 함수 지표 생성 및 등록
+//
+// Parameters:
+//   - name: 지표 이름
+//   - help: 지표 설명
+//   - typ: 지표 유형
+//   - label: 레이블 이름 (빈 문자열일 경우 레이블 없음)
+//   - fn: 값 조회 함수
+//
+// Returns:
+//   - *FuncVec
+func newFuncVec(name, help, typ, label string, fn func() map[string]float64) *FuncVec {
+	f := &FuncVec{
+		desc: desc{metricName: name, help: help, typ: typ},
+		fn:   fn,
+	}
+	if label != "" {
+		f.labels = []string{label}
+	}
+	register(f)
+	return f
+}
+
+// write 함수 지표 출력
+func (f *FuncVec) write(w *bufio.Writer) {
+	values := f.fn()
+
+	f.writeHeader(w)
+	for _, key := range sortedKeys(values) {
+		fmt.Fprintf(w, "%s%s %s\n", f.metricName, f.formatLabels(key, ""), formatValue(values[key]))
+	}
+}
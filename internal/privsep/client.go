@@ -0,0 +1,508 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package privsep
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hoon-kr/weblin/internal/auth"
+	"github.com/hoon-kr/weblin/internal/logger"
+)
+
+const (
+	// 유휴 헬퍼 프로세스 종료 시간
+	helperIdleTimeout = 10 * time.Minute
+	// 유휴 헬퍼 프로세스 정리 주기
+	helperReapInterval = time.Minute
+	// 헬퍼 프로세스 종료 대기 시간 (초과 시 SIGKILL)
+	helperKillWait = 3 * time.Second
+	// 현재 실행 파일 경로
+	selfExePath = "/proc/self/exe"
+)
+
+// ErrHelperClosed 헬퍼 프로세스가 종료된 경우 에러
+var ErrHelperClosed = errors.New("privsep helper is closed")
+
+// Request 헬퍼 프로세스 요청 정보 구조체
+type Request struct {
+	// 요청 이름
+	Op string
+	// 요청 인자 (JSON으로 변환)
+	Args interface{}
+	// 헬퍼 프로세스에 전달할 파일 목록
+	Files []*os.File
+	// 진행 상황 수신 시 호출할 함수 (nil일 경우 무시)
+	OnProgress func(data json.RawMessage)
+}
+
+// Response 헬퍼 프로세스 응답 정보 구조체
+type Response struct {
+	// 헬퍼 프로세스가 전달한 파일 목록 (호출자가 닫아야 함)
+	Files []*os.File
+}
+
+// pendingCall 응답 대기 중인 요청 정보 구조체
+type pendingCall struct {
+	onProgress func(data json.RawMessage)
+	done       chan *reply
+}
+
+// reply 최종 응답 정보 구조체
+type reply struct {
+	frame *frame
+	files []*os.File
+	err   error
+}
+
+// Client 계정별 헬퍼 프로세스 연결 정보 구조체
+type Client struct {
+	user *auth.User
+	cmd  *exec.Cmd
+	conn *net.UnixConn
+
+	writeMu  sync.Mutex
+	mu       sync.Mutex
+	nextID   uint64
+	pending  map[uint64]*pendingCall
+	lastUsed time.Time
+	closed   bool
+	done     chan struct{}
+}
+
+// startClient 계정 권한으로 헬퍼 프로세스를 실행하고 연결
+//
+// Parameters:
+//   - u: 로그인 계정 정보
+//
+// Returns:
+//   - *Client
+//   - error: 성공(nil), 실패(error)
+func startClient(u *auth.User) (*Client, error) {
+	attr, err := SysProcAttr(u)
+	if err != nil {
+		return nil, err
+	}
+
+	fds, err := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_STREAM|syscall.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create socket pair: %s", err)
+	}
+	local := os.NewFile(uintptr(fds[0]), "privsep")
+	remote := os.NewFile(uintptr(fds[1]), "privsep")
+	defer remote.Close()
+
+	fc, err := net.FileConn(local)
+	local.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open socket: %s", err)
+	}
+
+	// 실행 파일 경로의 상위 디렉터리에 계정 접근 권한이 없더라도 실행할 수 있도록 /proc/self/exe 사용
+	cmd := exec.Command(selfExePath, HelperCommand)
+	cmd.Dir = HomeDir(u)
+	cmd.Env = append(Environ(u), helperEnv+"=1")
+	cmd.ExtraFiles = []*os.File{remote}
+	cmd.SysProcAttr = attr
+	// 데몬 프로세스 그룹의 시그널(SIGINT 등)을 전달받지 않도록 분리
+	cmd.SysProcAttr.Setpgid = true
+
+	if err := cmd.Start(); err != nil {
+		fc.Close()
+		return nil, fmt.Errorf("failed to start privsep helper (user:%s): %s", u.Username, err)
+	}
+
+	c := &Client{
+		user:     u,
+		cmd:      cmd,
+		conn:     fc.(*net.UnixConn),
+		pending:  make(map[uint64]*pendingCall),
+		lastUsed: time.Now(),
+		done:     make(chan struct{}),
+	}
+	go c.readLoop()
+
+	logger.Log.LogInfo("Privsep helper started (user:%s, pid:%d)", u.Username, cmd.Process.Pid)
+	return c, nil
+}
+
+// User 헬퍼 프로세스 실행 계정 반환
+//
+// Returns:
+//   - *auth.User: 계정 정보
+func (c *Client) User() *auth.User {
+	return c.user
+}
+
+// Call 헬퍼 프로세스에 요청을 전달하고 결과 수신
+//
+// Parameters:
+//   - ctx: 요청 취소 컨텍스트
+//   - op: 요청 이름
+//   - args: 요청 인자
+//   - result: 결과를 저장할 구조체 포인터 (nil일 경우 무시)
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func (c *Client) Call(ctx context.Context, op string, args, result interface{}) error {
+	resp, err := c.Do(ctx, &Request{Op: op, Args: args}, result)
+	if err != nil {
+		return err
+	}
+	closeFiles(resp.Files)
+	return nil
+}
+
+// Do 파일 전달, 진행 상황 수신을 포함한 헬퍼 프로세스 요청
+//
+// Parameters:
+//   - ctx: 요청 취소 컨텍스트 (취소 시 헬퍼 프로세스의 작업도 취소)
+//   - req: 요청 정보
+//   - result: 결과를 저장할 구조체 포인터 (nil일 경우 무시)
+//
+// Returns:
+//   - *Response: 응답 정보
+//   - error: 성공(nil), 실패(error)
+func (c *Client) Do(ctx context.Context, req *Request, result interface{}) (*Response, error) {
+	var args json.RawMessage
+	if req.Args != nil {
+		data, err := json.Marshal(req.Args)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode arguments: %s", err)
+		}
+		args = data
+	}
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, ErrHelperClosed
+	}
+	c.nextID++
+	id := c.nextID
+	call := &pendingCall{onProgress: req.OnProgress, done: make(chan *reply, 1)}
+	c.pending[id] = call
+	c.lastUsed = time.Now()
+	c.mu.Unlock()
+
+	if err := c.send(&frame{ID: id, Op: req.Op, Args: args}, req.Files); err != nil {
+		c.removePending(id)
+		return nil, fmt.Errorf("failed to send request (op:%s): %s", req.Op, err)
+	}
+
+	var r *reply
+	select {
+	case r = <-call.done:
+	case <-ctx.Done():
+		// 헬퍼 프로세스에 취소 요청 후 응답 대기
+		c.send(&frame{ID: id, Op: opCancel}, nil)
+		r = <-call.done
+	}
+
+	c.mu.Lock()
+	c.lastUsed = time.Now()
+	c.mu.Unlock()
+
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.frame.Error != nil {
+		closeFiles(r.files)
+		return nil, r.frame.Error
+	}
+	if result != nil && len(r.frame.Result) > 0 {
+		if err := json.Unmarshal(r.frame.Result, result); err != nil {
+			closeFiles(r.files)
+			return nil, fmt.Errorf("failed to decode result (op:%s): %s", req.Op, err)
+		}
+	}
+
+	return &Response{Files: r.files}, nil
+}
+
+// Open 계정 권한으로 파일 열기
+//
+// Parameters:
+//   - ctx: 요청 취소 컨텍스트
+//   - path: 파일 경로
+//   - flag: 열기 옵션 (os.O_RDONLY 등)
+//   - perm: 파일 생성 시 권한
+//
+// Returns:
+//   - *os.File
+//   - error: 성공(nil), 실패(error)
+func (c *Client) Open(ctx context.Context, path string, flag int, perm os.FileMode) (*os.File, error) {
+	resp, err := c.Do(ctx, &Request{
+		Op:   "open",
+		Args: &openArgs{Path: path, Flag: flag, Perm: perm},
+	}, nil)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: path, Err: unwrapErrno(err)}
+	}
+	if len(resp.Files) != 1 {
+		closeFiles(resp.Files)
+		return nil, fmt.Errorf("privsep helper returned %d files", len(resp.Files))
+	}
+
+	// 에러 메시지와 Stat 결과에 경로 이름이 사용되도록 디스크립터를 복제하여 이름 지정
+	fd, err := syscall.Dup(int(resp.Files[0].Fd()))
+	resp.Files[0].Close()
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: path, Err: err}
+	}
+	syscall.CloseOnExec(fd)
+
+	return os.NewFile(uintptr(fd), path), nil
+}
+
+// Close 헬퍼 프로세스 종료
+func (c *Client) Close() {
+	c.conn.Close()
+
+	// 연결이 끊어지면 헬퍼 프로세스가 스스로 종료하며, 응답이 없을 경우 강제 종료
+	select {
+	case <-c.done:
+	case <-time.After(helperKillWait):
+		c.cmd.Process.Kill()
+		<-c.done
+	}
+}
+
+// idle 요청이 없는 상태로 timeout 이상 경과했는지 확인
+//
+// Parameters:
+//   - now: 현재 시각
+//   - timeout: 유휴 시간
+//
+// Returns:
+//   - bool: 유휴(true), 사용 중(false)
+func (c *Client) idle(now time.Time, timeout time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.pending) == 0 && now.Sub(c.lastUsed) > timeout
+}
+
+// isClosed 헬퍼 프로세스 종료 여부 확인
+//
+// Returns:
+//   - bool: 종료(true), 동작 중(false)
+func (c *Client) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.closed
+}
+
+// send 요청 프레임 전송 (동시 전송 방지)
+//
+// Parameters:
+//   - f: 요청 프레임
+//   - files: 첨부할 파일 목록
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func (c *Client) send(f *frame, files []*os.File) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	return writeFrame(c.conn, f, files)
+}
+
+// removePending 응답 대기 목록에서 요청 제거
+//
+// Parameters:
+//   - id: 요청 ID
+func (c *Client) removePending(id uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.pending, id)
+}
+
+// readLoop 헬퍼 프로세스 응답을 수신하여 대기 중인 요청에 전달
+func (c *Client) readLoop() {
+	defer close(c.done)
+
+	for {
+		f, files, err := readFrame(c.conn)
+		if err != nil {
+			break
+		}
+
+		c.mu.Lock()
+		call, exists := c.pending[f.ID]
+		if exists && !f.Progress {
+			delete(c.pending, f.ID)
+		}
+		c.mu.Unlock()
+
+		if !exists {
+			closeFiles(files)
+			continue
+		}
+		if f.Progress {
+			closeFiles(files)
+			if call.onProgress != nil {
+				call.onProgress(f.Result)
+			}
+			continue
+		}
+		call.done <- &reply{frame: f, files: files}
+	}
+
+	// 연결 종료 시 대기 중인 모든 요청 실패 처리
+	c.mu.Lock()
+	c.closed = true
+	for id, call := range c.pending {
+		delete(c.pending, id)
+		call.done <- &reply{err: ErrHelperClosed}
+	}
+	c.mu.Unlock()
+
+	c.conn.Close()
+	c.cmd.Wait()
+	logger.Log.LogInfo("Privsep helper stopped (user:%s, pid:%d)", c.user.Username, c.cmd.Process.Pid)
+}
+
+// unwrapErrno 헬퍼 프로세스 에러에서 errno 추출 (os.PathError 등으로 감싸기 위함)
+//
+// Parameters:
+//   - err: 헬퍼 프로세스 에러
+//
+// Returns:
+//   - error: errno (없을 경우 원본 에러)
+func unwrapErrno(err error) error {
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		return errno
+	}
+	return err
+}
+
+// Pool 계정별 헬퍼 프로세스 관리 정보 구조체
+type Pool struct {
+	mu      sync.Mutex
+	clients map[uint32]*Client
+	closed  bool
+}
+
+// NewPool 헬퍼 프로세스 관리 구조체 생성
+//
+// Returns:
+//   - *Pool
+func NewPool() *Pool {
+	return &Pool{
+		clients: make(map[uint32]*Client),
+	}
+}
+
+// Get 계정의 헬퍼 프로세스 연결 반환 (없거나 종료된 경우 새로 실행)
+//
+// Parameters:
+//   - u: 로그인 계정 정보
+//
+// Returns:
+//   - *Client
+//   - error: 성공(nil), 실패(error)
+func (p *Pool) Get(u *auth.User) (*Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, ErrHelperClosed
+	}
+
+	if c, exists := p.clients[u.Uid]; exists {
+		if !c.isClosed() {
+			return c, nil
+		}
+		delete(p.clients, u.Uid)
+	}
+
+	c, err := startClient(u)
+	if err != nil {
+		return nil, err
+	}
+	p.clients[u.Uid] = c
+
+	return c, nil
+}
+
+// Count 동작 중인 헬퍼 프로세스 개수 반환
+//
+// Returns:
+//   - int: 헬퍼 프로세스 개수
+func (p *Pool) Count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.clients)
+}
+
+// Reaper 유휴 헬퍼 프로세스를 주기적으로 종료하는 고루틴 작업
+//
+// Parameters:
+//   - ctx: 작업 종료 컨텍스트
+func (p *Pool) Reaper(ctx context.Context) {
+	ticker := time.NewTicker(helperReapInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// 서버 종료 시 모든 헬퍼 프로세스 종료 (이후 새로운 헬퍼 프로세스를 실행하지 않음)
+			p.mu.Lock()
+			p.closed = true
+			p.mu.Unlock()
+			p.closeIf(func(*Client) bool { return true })
+			return
+		case now := <-ticker.C:
+			p.closeIf(func(c *Client) bool {
+				return c.isClosed() || c.idle(now, helperIdleTimeout)
+			})
+		}
+	}
+}
+
+// closeIf 조건에 맞는 헬퍼 프로세스 종료
+//
+// Parameters:
+//   - cond: 종료 조건
+func (p *Pool) closeIf(cond func(c *Client) bool) {
+	p.mu.Lock()
+	var targets []*Client
+	for uid, c := range p.clients {
+		if cond(c) {
+			delete(p.clients, uid)
+			targets = append(targets, c)
+		}
+	}
+	p.mu.Unlock()
+
+	for _, c := range targets {
+		c.Close()
+	}
+}
@@ -0,0 +1,327 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package privsep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+
+	"github.com/hoon-kr/weblin/config"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// HelperCommand 헬퍼 프로세스 실행 명령어 (숨김 명령어)
+	HelperCommand = "privsep-helper"
+	// 헬퍼 프로세스 식별 환경 변수
+	helperEnv = "WEBLIN_PRIVSEP_HELPER"
+	// 헬퍼 프로세스에 전달되는 소켓 디스크립터 (ExtraFiles[0])
+	helperFd = 3
+)
+
+// Handler 헬퍼 프로세스에서 요청을 처리하는 함수
+//
+// 반환값은 JSON으로 변환되어 데몬에 전달된다.
+type Handler func(ctx context.Context, call *Call) (interface{}, error)
+
+var (
+	handlersMu sync.RWMutex
+	// handlers 요청 이름별 처리 함수
+	handlers = map[string]Handler{
+		"ping": handlePing,
+		"open": handleOpen,
+	}
+)
+
+// Call 헬퍼 프로세스에서 처리 중인 요청 정보 구조체
+type Call struct {
+	// 데몬이 첨부한 파일 목록 (처리 완료 후 자동으로 닫힘)
+	Files []*os.File
+
+	args     json.RawMessage
+	outFiles []*os.File
+	progress func(v interface{})
+}
+
+// Decode 요청 인자를 구조체로 변환
+//
+// Parameters:
+//   - v: 변환 결과를 저장할 구조체 포인터
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func (c *Call) Decode(v interface{}) error {
+	if len(c.args) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(c.args, v); err != nil {
+		return fmt.Errorf("invalid arguments: %s", err)
+	}
+	return nil
+}
+
+// Progress 진행 상황을 데몬에 전달
+//
+// Parameters:
+//   - v: 진행 상황 데이터
+func (c *Call) Progress(v interface{}) {
+	c.progress(v)
+}
+
+// AttachFile 응답과 함께 데몬에 전달할 파일 추가 (전송 후 자동으로 닫힘)
+//
+// Parameters:
+//   - f: 전달할 파일
+func (c *Call) AttachFile(f *os.File) {
+	c.outFiles = append(c.outFiles, f)
+}
+
+// RegisterHandler 헬퍼 프로세스 요청 처리 함수 등록
+//
+// 헬퍼 프로세스는 데몬과 같은 실행 파일이므로 각 패키지의 init에서 등록한다.
+//
+// Parameters:
+//   - op: 요청 이름
+//   - h: 처리 함수
+func RegisterHandler(op string, h Handler) {
+	handlersMu.Lock()
+	defer handlersMu.Unlock()
+
+	if _, exists := handlers[op]; exists {
+		panic("privsep: duplicate handler " + op)
+	}
+	handlers[op] = h
+}
+
+// RunHelper 헬퍼 프로세스 실행 (데몬이 계정 권한으로 실행하는 숨김 명령어)
+//
+// Parameters:
+//   - cmd: 명령어 정보
+//
+// Returns:
+//   - int: 정상 종료(0), 비정상 종료(>=1)
+//   - error: 정상 종료(nil), 비정상 종료(error)
+func RunHelper(cmd *cobra.Command) (int, error) {
+	if os.Getenv(helperEnv) != "1" {
+		fmt.Fprintf(os.Stderr, "[ERROR] %s is for internal use only\n", HelperCommand)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	file := os.NewFile(helperFd, "privsep")
+	fc, err := net.FileConn(file)
+	file.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] failed to open privsep socket: %s\n", err)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+	conn, ok := fc.(*net.UnixConn)
+	if !ok {
+		fc.Close()
+		fmt.Fprintf(os.Stderr, "[ERROR] privsep socket is not a unix socket\n")
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	// 일반적인 로그인 환경과 동일한 파일 생성 권한 적용
+	syscall.Umask(0o022)
+
+	newHelper(conn).serve()
+	return config.ExitCodeSuccess, nil
+}
+
+// helper 헬퍼 프로세스 요청 처리 정보 구조체
+type helper struct {
+	conn    *net.UnixConn
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	cancels map[uint64]context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// newHelper 헬퍼 요청 처리 구조체 생성
+//
+// Parameters:
+//   - conn: 데몬과 연결된 소켓
+//
+// Returns:
+//   - *helper
+func newHelper(conn *net.UnixConn) *helper {
+	return &helper{
+		conn:    conn,
+		cancels: make(map[uint64]context.CancelFunc),
+	}
+}
+
+// serve 데몬 연결이 끊어질 때까지 요청 수신 및 처리 (요청별 고루틴에서 동시 처리)
+func (h *helper) serve() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for {
+		req, files, err := readFrame(h.conn)
+		if err != nil {
+			break
+		}
+
+		if req.Op == opCancel {
+			h.mu.Lock()
+			if cancelCall, exists := h.cancels[req.ID]; exists {
+				cancelCall()
+			}
+			h.mu.Unlock()
+			closeFiles(files)
+			continue
+		}
+
+		callCtx, cancelCall := context.WithCancel(ctx)
+		h.mu.Lock()
+		h.cancels[req.ID] = cancelCall
+		h.mu.Unlock()
+
+		h.wg.Add(1)
+		go func() {
+			defer h.wg.Done()
+			h.handle(callCtx, req, files)
+
+			h.mu.Lock()
+			delete(h.cancels, req.ID)
+			h.mu.Unlock()
+			cancelCall()
+		}()
+	}
+
+	// 데몬 연결 종료 시 처리 중인 요청 취소
+	cancel()
+	h.wg.Wait()
+	h.conn.Close()
+}
+
+// handle 요청 처리 후 응답 전송
+//
+// Parameters:
+//   - ctx: 요청 취소 컨텍스트
+//   - req: 요청 프레임
+//   - files: 요청에 첨부된 파일 목록
+func (h *helper) handle(ctx context.Context, req *frame, files []*os.File) {
+	defer closeFiles(files)
+
+	call := &Call{
+		Files: files,
+		args:  req.Args,
+		progress: func(v interface{}) {
+			data, err := json.Marshal(v)
+			if err != nil {
+				return
+			}
+			h.send(&frame{ID: req.ID, Progress: true, Result: data}, nil)
+		},
+	}
+
+	resp := &frame{ID: req.ID}
+
+	handlersMu.RLock()
+	handler, exists := handlers[req.Op]
+	handlersMu.RUnlock()
+
+	if !exists {
+		resp.Error = &Error{Message: fmt.Sprintf("unknown operation: %s", req.Op)}
+		h.send(resp, nil)
+		return
+	}
+
+	result, err := handler(ctx, call)
+	defer closeFiles(call.outFiles)
+	if err != nil {
+		resp.Error = toError(err)
+		h.send(resp, nil)
+		return
+	}
+
+	if result != nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			resp.Error = &Error{Message: fmt.Sprintf("failed to encode result: %s", err)}
+			h.send(resp, nil)
+			return
+		}
+		resp.Result = data
+	}
+	h.send(resp, call.outFiles)
+}
+
+// send 응답 프레임 전송 (동시 전송 방지)
+//
+// Parameters:
+//   - f: 응답 프레임
+//   - files: 첨부할 파일 목록
+func (h *helper) send(f *frame, files []*os.File) {
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+
+	if err := writeFrame(h.conn, f, files); err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] failed to send response (id:%d): %s\n", f.ID, err)
+	}
+}
+
+// pingResult ping 요청 응답 구조체
+type pingResult struct {
+	Pid    int   `json:"pid"`
+	Uid    int   `json:"uid"`
+	Gid    int   `json:"gid"`
+	Groups []int `json:"groups"`
+}
+
+// handlePing 헬퍼 프로세스 상태 및 실행 권한 확인
+func handlePing(_ context.Context, _ *Call) (interface{}, error) {
+	groups, err := os.Getgroups()
+	if err != nil {
+		return nil, err
+	}
+	return &pingResult{
+		Pid:    os.Getpid(),
+		Uid:    os.Getuid(),
+		Gid:    os.Getgid(),
+		Groups: groups,
+	}, nil
+}
+
+// openArgs open 요청 인자 구조체
+type openArgs struct {
+	Path string      `json:"path"`
+	Flag int         `json:"flag"`
+	Perm os.FileMode `json:"perm"`
+}
+
+// handleOpen 계정 권한으로 파일을 열어 디스크립터 전달
+func handleOpen(_ context.Context, call *Call) (interface{}, error) {
+	var args openArgs
+	if err := call.Decode(&args); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(args.Path, args.Flag, args.Perm)
+	if err != nil {
+		return nil, err
+	}
+	call.AttachFile(f)
+
+	return nil, nil
+}
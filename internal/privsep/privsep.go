@@ -0,0 +1,174 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+/*
+Package privsep 권한 분리 패키지
+
+웹에서 요청된 셸과 파일 작업은 데몬 권한(일반적으로 root)이 아닌 로그인한 리눅스 계정의
+uid, gid, 보조 그룹 권한으로 수행되어야 한다. 자식 프로세스는 SysProcAttr.Credential을
+통해 계정 권한으로 실행하며, 파일 입출력은 계정별 헬퍼 프로세스를 통해 수행한다.
+*/
+package privsep
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"syscall"
+
+	"github.com/hoon-kr/weblin/internal/auth"
+)
+
+// DefaultPath 계정 권한으로 실행되는 프로세스의 기본 PATH
+const DefaultPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// ErrSwitchUser 데몬 권한으로 다른 계정 전환이 불가능할 경우 에러
+var ErrSwitchUser = errors.New("insufficient privilege to switch user")
+
+// Credential 계정 권한으로 프로세스를 실행하기 위한 자격 증명 생성
+//
+// 데몬이 root가 아닌 경우 자기 자신의 계정으로만 실행할 수 있으며, 이때는 권한 전환이
+// 필요 없으므로 nil을 반환한다.
+//
+// Parameters:
+//   - u: 로그인 계정 정보
+//
+// Returns:
+//   - *syscall.Credential: 자격 증명 (권한 전환 불필요 시 nil)
+//   - error: 성공(nil), 실패(error)
+func Credential(u *auth.User) (*syscall.Credential, error) {
+	if os.Geteuid() != 0 {
+		if u.Uid != uint32(os.Getuid()) {
+			return nil, ErrSwitchUser
+		}
+		return nil, nil
+	}
+
+	groups, err := supplementaryGroups(u)
+	if err != nil {
+		return nil, err
+	}
+
+	return &syscall.Credential{
+		Uid:    u.Uid,
+		Gid:    u.Gid,
+		Groups: groups,
+	}, nil
+}
+
+// SysProcAttr 계정 권한으로 자식 프로세스를 실행하기 위한 속성 생성
+//
+// 데몬이 종료되면 자식 프로세스도 함께 종료되도록 Pdeathsig를 설정한다.
+//
+// Parameters:
+//   - u: 로그인 계정 정보
+//
+// Returns:
+//   - *syscall.SysProcAttr
+//   - error: 성공(nil), 실패(error)
+func SysProcAttr(u *auth.User) (*syscall.SysProcAttr, error) {
+	cred, err := Credential(u)
+	if err != nil {
+		return nil, err
+	}
+
+	return &syscall.SysProcAttr{
+		Credential: cred,
+		Pdeathsig:  syscall.SIGKILL,
+	}, nil
+}
+
+// Environ 계정 로그인 환경 변수 생성
+//
+// Parameters:
+//   - u: 로그인 계정 정보
+//
+// Returns:
+//   - []string: 환경 변수 목록
+func Environ(u *auth.User) []string {
+	env := []string{
+		"HOME=" + HomeDir(u),
+		"USER=" + u.Username,
+		"LOGNAME=" + u.Username,
+		"SHELL=" + Shell(u),
+		"PATH=" + DefaultPath,
+	}
+	if lang := os.Getenv("LANG"); lang != "" {
+		env = append(env, "LANG="+lang)
+	}
+	return env
+}
+
+// HomeDir 계정 홈 디렉터리 반환 (존재하지 않을 경우 "/")
+//
+// Parameters:
+//   - u: 로그인 계정 정보
+//
+// Returns:
+//   - string: 홈 디렉터리 경로
+func HomeDir(u *auth.User) string {
+	if info, err := os.Stat(u.Home); err == nil && info.IsDir() {
+		return u.Home
+	}
+	return "/"
+}
+
+// Shell 계정 로그인 셸 반환 (미설정 시 /bin/sh)
+//
+// Parameters:
+//   - u: 로그인 계정 정보
+//
+// Returns:
+//   - string: 셸 경로
+func Shell(u *auth.User) string {
+	if u.Shell == "" {
+		return "/bin/sh"
+	}
+	return u.Shell
+}
+
+// supplementaryGroups 계정이 속한 보조 그룹 ID 목록 조회
+//
+// Parameters:
+//   - u: 로그인 계정 정보
+//
+// Returns:
+//   - []uint32: 그룹 ID 목록
+//   - error: 성공(nil), 실패(error)
+func supplementaryGroups(u *auth.User) ([]uint32, error) {
+	lu, err := user.Lookup(u.Username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup user (user:%s): %s", u.Username, err)
+	}
+
+	ids, err := lu.GroupIds()
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup groups (user:%s): %s", u.Username, err)
+	}
+
+	groups := make([]uint32, 0, len(ids))
+	for _, id := range ids {
+		gid, err := strconv.ParseUint(id, 10, 32)
+		if err != nil {
+			continue
+		}
+		groups = append(groups, uint32(gid))
+	}
+
+	return groups, nil
+}
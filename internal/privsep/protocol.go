@@ -0,0 +1,236 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package privsep
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+)
+
+const (
+	// 프레임 헤더 크기 (본문 길이, big endian)
+	frameHeaderSize = 4
+	// 프레임 본문 최대 크기
+	maxFrameSize = 16 << 20
+	// 프레임 하나에 첨부 가능한 최대 파일 디스크립터 수
+	maxFrameFiles = 16
+)
+
+// 헬퍼 프로세스 제어 요청
+const opCancel = "cancel"
+
+// frame 데몬 <-> 헬퍼 프로세스 메시지 구조체
+//
+// 요청(Op 설정), 진행 상황(Progress), 최종 응답(Result, Error)을 모두 같은 구조체로 표현하며
+// ID로 요청과 응답을 연결한다. 파일 디스크립터는 SCM_RIGHTS로 함께 전달된다.
+type frame struct {
+	ID       uint64          `json:"id"`
+	Op       string          `json:"op,omitempty"`
+	Args     json.RawMessage `json:"args,omitempty"`
+	Result   json.RawMessage `json:"result,omitempty"`
+	Error    *Error          `json:"error,omitempty"`
+	Progress bool            `json:"progress,omitempty"`
+}
+
+// Error 헬퍼 프로세스 작업 실패 정보 구조체
+//
+// errno를 함께 전달하므로 errors.Is(err, fs.ErrNotExist) 등으로 원인을 확인할 수 있다.
+type Error struct {
+	Message string        `json:"message"`
+	Errno   syscall.Errno `json:"errno,omitempty"`
+}
+
+// Error error 인터페이스 구현
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap 원인 errno 반환
+func (e *Error) Unwrap() error {
+	if e.Errno == 0 {
+		return nil
+	}
+	return e.Errno
+}
+
+// toError 헬퍼 프로세스에서 발생한 에러를 전송 가능한 형태로 변환
+//
+// Parameters:
+//   - err: 작업 에러
+//
+// Returns:
+//   - *Error
+func toError(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	e = &Error{Message: err.Error()}
+	errors.As(err, &e.Errno)
+	return e
+}
+
+// writeFrame 프레임 전송 (파일 디스크립터 첨부 가능)
+//
+// Parameters:
+//   - conn: 유닉스 소켓 연결
+//   - f: 전송할 프레임
+//   - files: 첨부할 파일 목록
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func writeFrame(conn *net.UnixConn, f *frame, files []*os.File) error {
+	if len(files) > maxFrameFiles {
+		return fmt.Errorf("too many files in frame (%d)", len(files))
+	}
+
+	body, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("failed to encode frame: %s", err)
+	}
+	if len(body) > maxFrameSize {
+		return fmt.Errorf("frame too large (%d bytes)", len(body))
+	}
+
+	buf := make([]byte, frameHeaderSize+len(body))
+	binary.BigEndian.PutUint32(buf, uint32(len(body)))
+	copy(buf[frameHeaderSize:], body)
+
+	var oob []byte
+	if len(files) > 0 {
+		fds := make([]int, len(files))
+		for i, file := range files {
+			fds[i] = int(file.Fd())
+		}
+		oob = syscall.UnixRights(fds...)
+	}
+
+	// 파일 디스크립터는 첫 번째 바이트와 함께 전달되므로 헤더와 본문을 한 번에 전송
+	n, _, err := conn.WriteMsgUnix(buf, oob, nil)
+	if err != nil {
+		return err
+	}
+	if n < len(buf) {
+		_, err = conn.Write(buf[n:])
+	}
+	return err
+}
+
+// readFrame 프레임 수신
+//
+// 프레임 경계에서만 읽기를 시작하므로 첨부된 파일 디스크립터는 항상 헤더와 함께 수신된다.
+//
+// Parameters:
+//   - conn: 유닉스 소켓 연결
+//
+// Returns:
+//   - *frame: 수신한 프레임
+//   - []*os.File: 첨부된 파일 목록
+//   - error: 성공(nil), 실패(error)
+func readFrame(conn *net.UnixConn) (*frame, []*os.File, error) {
+	header := make([]byte, frameHeaderSize)
+	oob := make([]byte, syscall.CmsgSpace(maxFrameFiles*4))
+
+	n, oobn, _, _, err := conn.ReadMsgUnix(header, oob)
+	if err != nil {
+		return nil, nil, err
+	}
+	if n == 0 {
+		return nil, nil, io.EOF
+	}
+
+	files, err := parseRights(oob[:oobn])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if n < frameHeaderSize {
+		if _, err := io.ReadFull(conn, header[n:]); err != nil {
+			closeFiles(files)
+			return nil, nil, err
+		}
+	}
+
+	size := binary.BigEndian.Uint32(header)
+	if size > maxFrameSize {
+		closeFiles(files)
+		return nil, nil, fmt.Errorf("frame too large (%d bytes)", size)
+	}
+
+	body := make([]byte, size)
+	if _, err := io.ReadFull(conn, body); err != nil {
+		closeFiles(files)
+		return nil, nil, err
+	}
+
+	var f frame
+	if err := json.Unmarshal(body, &f); err != nil {
+		closeFiles(files)
+		return nil, nil, fmt.Errorf("failed to decode frame: %s", err)
+	}
+
+	return &f, files, nil
+}
+
+// parseRights 제어 메시지에서 파일 디스크립터 추출
+//
+// Parameters:
+//   - oob: 제어 메시지
+//
+// Returns:
+//   - []*os.File: 파일 목록
+//   - error: 성공(nil), 실패(error)
+func parseRights(oob []byte) ([]*os.File, error) {
+	if len(oob) == 0 {
+		return nil, nil
+	}
+
+	msgs, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse control message: %s", err)
+	}
+
+	var files []*os.File
+	for i := range msgs {
+		fds, err := syscall.ParseUnixRights(&msgs[i])
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			files = append(files, os.NewFile(uintptr(fd), "privsep"))
+		}
+	}
+
+	return files, nil
+}
+
+// closeFiles 파일 목록 닫기
+//
+// Parameters:
+//   - files: 파일 목록
+func closeFiles(files []*os.File) {
+	for _, file := range files {
+		file.Close()
+	}
+}
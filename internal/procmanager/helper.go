@@ -0,0 +1,74 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package procmanager
+
+import (
+	"context"
+	"syscall"
+
+	"github.com/hoon-kr/weblin/internal/privsep"
+	"github.com/hoon-kr/weblin/pkg/utils/process"
+)
+
+// 헬퍼 프로세스 요청 이름
+const (
+	opSignal  = "proc.signal"
+	opRenice  = "proc.renice"
+	opVisible = "proc.visible"
+)
+
+// signalArgs 시그널 전송 요청 인자 구조체
+type signalArgs struct {
+	PID    int `json:"pid"`
+	Signal int `json:"signal"`
+}
+
+// reniceArgs 우선순위 변경 요청 인자 구조체
+type reniceArgs struct {
+	PID  int `json:"pid"`
+	Nice int `json:"nice"`
+}
+
+// init 헬퍼 프로세스 요청 처리 함수 등록
+func init() {
+	privsep.RegisterHandler(opSignal, helperSignal)
+	privsep.RegisterHandler(opRenice, helperRenice)
+	privsep.RegisterHandler(opVisible, helperVisible)
+}
+
+// helperSignal 시그널 전송
+func helperSignal(_ context.Context, call *privsep.Call) (interface{}, error) {
+	var args signalArgs
+	if err := call.Decode(&args); err != nil {
+		return nil, err
+	}
+	return nil, process.SendSignal(args.PID, syscall.Signal(args.Signal))
+}
+
+// helperRenice 우선순위 변경
+func helperRenice(_ context.Context, call *privsep.Call) (interface{}, error) {
+	var args reniceArgs
+	if err := call.Decode(&args); err != nil {
+		return nil, err
+	}
+	return nil, process.SetNice(args.PID, args.Nice)
+}
+
+// helperVisible 계정 권한으로 조회할 수 있는 프로세스의 명령행 목록 조회
+func helperVisible(_ context.Context, _ *privsep.Call) (interface{}, error) {
+	return process.VisibleCmdlines()
+}
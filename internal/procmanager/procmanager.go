@@ -0,0 +1,464 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+/*
+Package procmanager 웹 프로세스 관리 API 패키지
+
+프로세스 목록은 데몬에서 /proc을 읽어 제공하고, 시그널 전송 및 우선순위 변경은
+로그인 계정 권한으로 동작하는 헬퍼 프로세스(privsep)를 통해 수행하여 커널이 권한을 검사하도록 한다.
+*/
+package procmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/hoon-kr/weblin/internal/audit"
+	"github.com/hoon-kr/weblin/internal/logger"
+	"github.com/hoon-kr/weblin/internal/privsep"
+	"github.com/hoon-kr/weblin/internal/session"
+	"github.com/hoon-kr/weblin/internal/web"
+	"github.com/hoon-kr/weblin/pkg/utils/process"
+)
+
+// PathPrefix 프로세스 관리 API 경로
+const PathPrefix = "/api/proc/"
+
+// signalRequest 시그널 전송 요청 구조체 (시그널 이름 또는 번호)
+type signalRequest struct {
+	PID    int    `json:"pid"`
+	Signal string `json:"signal"`
+}
+
+// killRequest 프로세스 종료 요청 구조체 (force 지정 시 SIGKILL, 아닐 경우 SIGTERM)
+type killRequest struct {
+	PID   int  `json:"pid"`
+	Force bool `json:"force,omitempty"`
+}
+
+// reniceRequest 우선순위 변경 요청 구조체
+type reniceRequest struct {
+	PID  int `json:"pid"`
+	Nice int `json:"nice"`
+}
+
+// listFilter 프로세스 목록 필터 구조체
+type listFilter struct {
+	// 계정 이름 또는 uid
+	user string
+	// 이름 또는 명령행 검색어 (대소문자 무시)
+	query string
+	// 상태 문자 목록 (예: "RS")
+	state string
+	pid   int
+}
+
+// Manager 프로세스 관리 API 정보 구조체
+type Manager struct {
+	pool    *privsep.Pool
+	sampler *process.Sampler
+}
+
+// NewManager 프로세스 관리 API 구조체 생성
+//
+// Parameters:
+//   - pool: 계정별 헬퍼 프로세스 관리 구조체
+//
+// Returns:
+//   - *Manager
+func NewManager(pool *privsep.Pool) *Manager {
+	return &Manager{
+		pool:    pool,
+		sampler: process.NewSampler(),
+	}
+}
+
+// ServeHTTP 프로세스 관리 API 요청 처리 (로그인 세션 필요)
+//
+//   - GET  /api/proc/list?user=&q=&state=&pid=&sort=&order=&limit=&tree=  프로세스 목록
+//   - POST /api/proc/signal     시그널 전송 {pid, signal}
+//   - POST /api/proc/kill       프로세스 종료 {pid, force}
+//   - POST /api/proc/renice     우선순위 변경 {pid, nice}
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (m *Manager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch strings.TrimPrefix(r.URL.Path, PathPrefix) {
+	case "list":
+		m.handleList(w, r)
+	case "signal":
+		m.handleSignal(w, r)
+	case "kill":
+		m.handleKill(w, r)
+	case "renice":
+		m.handleRenice(w, r)
+	default:
+		web.WriteError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// handleList 프로세스 목록 조회 (GET /api/proc/list)
+//
+// 목록은 로그인 계정이 조회할 수 있는 프로세스(/proc의 hidepid 옵션 등)로 제한하며,
+// 명령행은 로그인 계정의 헬퍼 프로세스가 읽은 값을 사용한다.
+// sort는 pid, cpu, rss, start, name 중 하나이며 tree=true일 경우 부모-자식 트리로 전달한다.
+// 트리 보기에서 부모가 필터링된 프로세스는 최상위에 위치한다.
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (m *Manager) handleList(w http.ResponseWriter, r *http.Request) {
+	if !web.AllowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	query := r.URL.Query()
+	filter := listFilter{
+		user:  query.Get("user"),
+		query: strings.ToLower(query.Get("q")),
+		state: strings.ToUpper(query.Get("state")),
+	}
+	if pidStr := query.Get("pid"); pidStr != "" {
+		pid, err := strconv.Atoi(pidStr)
+		if err != nil || pid <= 0 {
+			web.WriteError(w, http.StatusBadRequest, "invalid pid: %s", pidStr)
+			return
+		}
+		filter.pid = pid
+	}
+	limit := 0
+	if limitStr := query.Get("limit"); limitStr != "" {
+		value, err := strconv.Atoi(limitStr)
+		if err != nil || value < 0 {
+			web.WriteError(w, http.StatusBadRequest, "invalid limit: %s", limitStr)
+			return
+		}
+		limit = value
+	}
+	tree, _ := strconv.ParseBool(query.Get("tree"))
+
+	// CPU 사용률은 로그인 세션별 이전 조회 이후 사용률
+	s, _ := session.FromContext(r.Context())
+	procs, err := m.sampler.List(s.PublicID())
+	if err != nil {
+		web.WriteError(w, http.StatusInternalServerError, "%s", err)
+		return
+	}
+	var visible map[int]string
+	if err := m.call(r, opVisible, nil, &visible); err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	matched := procs[:0]
+	for _, p := range procs {
+		cmdline, ok := visible[p.PID]
+		if !ok {
+			continue
+		}
+		p.SetCmdline(cmdline)
+		if filter.match(p) {
+			matched = append(matched, p)
+		}
+	}
+
+	if err := sortProcesses(matched, query.Get("sort"), query.Get("order")); err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	if tree {
+		matched = process.BuildTree(matched)
+	} else if limit > 0 && len(matched) > limit {
+		matched = matched[:limit]
+	}
+
+	web.WriteJSON(w, http.StatusOK, matched)
+}
+
+// handleSignal 시그널 전송 (POST /api/proc/signal)
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (m *Manager) handleSignal(w http.ResponseWriter, r *http.Request) {
+	if !web.AllowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	var req signalRequest
+	if err := web.ReadJSON(r, &req); err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	sig, err := process.ParseSignal(req.Signal)
+	if err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	m.sendSignal(w, r, req.PID, sig)
+}
+
+// handleKill 프로세스 종료 (POST /api/proc/kill)
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (m *Manager) handleKill(w http.ResponseWriter, r *http.Request) {
+	if !web.AllowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	var req killRequest
+	if err := web.ReadJSON(r, &req); err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	sig := syscall.SIGTERM
+	if req.Force {
+		sig = syscall.SIGKILL
+	}
+	m.sendSignal(w, r, req.PID, sig)
+}
+
+// sendSignal 시그널 전송 공통 처리
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+//   - pid: 프로세스 ID
+//   - sig: 시그널
+func (m *Manager) sendSignal(w http.ResponseWriter, r *http.Request, pid int, sig syscall.Signal) {
+	if err := checkTarget(pid); err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	err := m.call(r, opSignal, &signalArgs{PID: pid, Signal: int(sig)}, nil)
+	audit.Record(r, session.Username(r.Context()), opSignal, strconv.Itoa(pid), fmt.Sprintf("signal:%d", int(sig)), err)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	logOperation(r, "%s (pid:%d, signal:%d)", opSignal, pid, int(sig))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRenice 우선순위 변경 (POST /api/proc/renice)
+//
+// 일반 계정은 자신의 프로세스 우선순위를 낮추는 것(nice 증가)만 가능하다.
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (m *Manager) handleRenice(w http.ResponseWriter, r *http.Request) {
+	if !web.AllowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	var req reniceRequest
+	if err := web.ReadJSON(r, &req); err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	if err := checkTarget(req.PID); err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	err := m.call(r, opRenice, &reniceArgs{PID: req.PID, Nice: req.Nice}, nil)
+	audit.Record(r, session.Username(r.Context()), opRenice, strconv.Itoa(req.PID), fmt.Sprintf("nice:%d", req.Nice), err)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	logOperation(r, "%s (pid:%d, nice:%d)", opRenice, req.PID, req.Nice)
+
+	proc, err := process.ReadProcess(req.PID)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	web.WriteJSON(w, http.StatusOK, proc)
+}
+
+// call 로그인 계정의 헬퍼 프로세스에 요청 전달
+//
+// Parameters:
+//   - r: 요청 정보 (로그인 세션 포함)
+//   - op: 헬퍼 프로세스 요청 이름
+//   - args: 요청 인자
+//   - result: 결과를 저장할 구조체 포인터
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func (m *Manager) call(r *http.Request, op string, args, result interface{}) error {
+	s, ok := session.FromContext(r.Context())
+	if !ok {
+		return errors.New("login required")
+	}
+	client, err := m.pool.Get(s.User)
+	if err != nil {
+		return err
+	}
+	return client.Call(r.Context(), op, args, result)
+}
+
+// checkTarget 작업 대상 프로세스 검증
+//
+// 웹 서버 자신은 대상에서 제외한다 (서버 종료는 stop 명령 사용).
+//
+// Parameters:
+//   - pid: 프로세스 ID
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func checkTarget(pid int) error {
+	if pid <= 0 {
+		return fmt.Errorf("invalid pid: %d: %w", pid, syscall.EINVAL)
+	}
+	if pid == os.Getpid() {
+		return fmt.Errorf("operation on the server process is not allowed: %w", syscall.EPERM)
+	}
+	return nil
+}
+
+// match 필터 조건 일치 여부 확인
+//
+// Parameters:
+//   - p: 프로세스 정보
+//
+// Returns:
+//   - bool: 일치(true), 불일치(false)
+func (f *listFilter) match(p *process.Process) bool {
+	if f.pid != 0 && p.PID != f.pid {
+		return false
+	}
+	if f.user != "" && f.user != p.User && f.user != strconv.FormatUint(uint64(p.UID), 10) {
+		return false
+	}
+	if f.state != "" && !strings.Contains(f.state, p.State) {
+		return false
+	}
+	if f.query != "" && !strings.Contains(strings.ToLower(p.Name), f.query) &&
+		!strings.Contains(strings.ToLower(p.Cmdline), f.query) {
+		return false
+	}
+	return true
+}
+
+// sortProcesses 프로세스 목록 정렬
+//
+// Parameters:
+//   - procs: 프로세스 목록
+//   - key: 정렬 기준 (pid, cpu, rss, start, name, 기본값 pid)
+//   - order: 정렬 순서 (asc, desc, 기본값은 pid, name은 asc 그 외 desc)
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func sortProcesses(procs []*process.Process, key, order string) error {
+	var less func(a, b *process.Process) bool
+	desc := true
+	switch key {
+	case "", "pid":
+		less = func(a, b *process.Process) bool { return a.PID < b.PID }
+		desc = false
+	case "name":
+		less = func(a, b *process.Process) bool { return a.Name < b.Name }
+		desc = false
+	case "cpu":
+		less = func(a, b *process.Process) bool { return a.CPUPercent < b.CPUPercent }
+	case "rss":
+		less = func(a, b *process.Process) bool { return a.RSS < b.RSS }
+	case "start":
+		less = func(a, b *process.Process) bool { return a.StartTime.Before(b.StartTime) }
+	default:
+		return fmt.Errorf("unsupported sort key: %s", key)
+	}
+
+	switch order {
+	case "":
+	case "asc":
+		desc = false
+	case "desc":
+		desc = true
+	default:
+		return fmt.Errorf("unsupported sort order: %s", order)
+	}
+
+	// 값이 같을 경우 PID 순서 유지
+	sort.SliceStable(procs, func(i, j int) bool {
+		if desc {
+			return less(procs[j], procs[i])
+		}
+		return less(procs[i], procs[j])
+	})
+	return nil
+}
+
+// WriteError 프로세스 작업 에러를 HTTP 상태 코드로 변환하여 응답 전송
+//
+// Parameters:
+//   - w: 응답 writer
+//   - err: 프로세스 작업 에러
+func WriteError(w http.ResponseWriter, err error) {
+	web.WriteError(w, errorStatus(err), "%s", err)
+}
+
+// errorStatus 프로세스 작업 에러에 해당하는 HTTP 상태 코드 반환
+//
+// Parameters:
+//   - err: 프로세스 작업 에러
+//
+// Returns:
+//   - int: HTTP 상태 코드
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, syscall.ESRCH), errors.Is(err, os.ErrNotExist):
+		return http.StatusNotFound
+	case errors.Is(err, syscall.EPERM), errors.Is(err, syscall.EACCES):
+		return http.StatusForbidden
+	case errors.Is(err, syscall.EINVAL):
+		return http.StatusBadRequest
+	case errors.Is(err, context.Canceled):
+		// 클라이언트 연결 종료 (nginx 관례)
+		return 499
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// logOperation 프로세스 작업 로그 기록 (작업 계정 및 원격 주소 포함)
+//
+// Parameters:
+//   - r: 요청 정보
+//   - format: 작업 내용 형식
+//   - args: 형식 인자
+func logOperation(r *http.Request, format string, args ...interface{}) {
+	logger.FromContext(r.Context()).LogInfo("Process operation: %s", fmt.Sprintf(format, args...))
+}
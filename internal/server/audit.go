@@ -0,0 +1,116 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package server
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hoon-kr/weblin/config"
+	"github.com/hoon-kr/weblin/internal/audit"
+	"github.com/spf13/cobra"
+)
+
+// VerifyAudit 감사 로그 해시 체인 검증 (weblin audit verify [file])
+//
+// 파일을 지정하지 않으면 서버가 기록하는 감사 로그를 검증하며, 마지막 항목 기록 파일과 대조하여
+// 끝부분 항목의 삭제도 검출한다. 지정한 파일(보관한 이전 감사 로그 등)은 해시 체인만 검증한다.
+//
+// Parameters:
+//   - cmd: 명령어 정보
+//
+// Returns:
+//   - int: 정상 종료(0), 비정상 종료(>=1)
+//   - error: 정상 종료(nil), 비정상 종료(error)
+func VerifyAudit(cmd *cobra.Command) (int, error) {
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "[WARNING] invalid parameter: [*cobra.Command] is nil\n")
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	// 작업 경로 변경 전에 현재 경로 기준의 파일 경로를 절대 경로로 변환
+	filePath := cmd.Flags().Arg(0)
+	if filePath != "" {
+		absPath, err := filepath.Abs(filePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
+			return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+		}
+		filePath = absPath
+	}
+
+	// 작업 경로를 파일 배치 방식의 작업 경로로 변경 (감사 로그 상대 경로 기준)
+	err := config.ChangeWorkDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+	// 서버가 기록 중인 경우 기록 파일이 감사 로그보다 앞서지 않도록 기록 파일을 먼저 읽음
+	var anchor *audit.Anchor
+	if filePath == "" {
+		filePath = config.Paths().AuditLogFile
+		anchor, err = audit.ReadAnchor(config.Paths().AuditAnchorFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
+			return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+		}
+	}
+
+	key, err := audit.LoadKey(config.Paths().AuditKeyFile, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] failed to open audit log: %s\n", err)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+	defer file.Close()
+
+	result, err := audit.Verify(file, key, anchor)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+	if len(result.Errs) > 0 {
+		for _, verr := range result.Errs {
+			fmt.Fprintf(os.Stderr, "[ERROR] %s\n", verr)
+		}
+		fmt.Fprintf(os.Stderr, "[ERROR] %s: audit log has been tampered with (%d problem(s) in %d entries)\n",
+			filePath, len(result.Errs), result.Entries)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	if result.Last == nil {
+		fmt.Fprintf(os.Stdout, "[INFO] %s: audit log is empty\n", filePath)
+		return config.ExitCodeSuccess, nil
+	}
+
+	fmt.Fprintf(os.Stdout, "[INFO] %s: hash chain is valid (entries:%d, last seq:%d, last hash:%s)\n",
+		filePath, result.Entries, result.Last.Seq, result.Last.Hash)
+	if anchor == nil {
+		fmt.Fprintf(os.Stdout, "[WARNING] removal of trailing entries is not checked for a file other than %s\n",
+			config.Paths().AuditLogFile)
+	} else if result.Last.Action != audit.ActionAuditClose {
+		fmt.Fprintf(os.Stdout, "[INFO] last entry is not %s: %s is running or was not stopped normally\n",
+			audit.ActionAuditClose, config.ModuleName)
+	}
+	return config.ExitCodeSuccess, nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package server
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/hoon-kr/weblin/config"
+	"github.com/spf13/cobra"
+)
+
+// CheckConfig 설정 파일 검증 (weblin config check [file])
+//
+// 파일을 지정하지 않으면 서버가 사용하는 설정 파일을 검증한다 (환경 변수, 명령행 플래그 제외).
+//
+// Parameters:
+//   - cmd: 명령어 정보
+//
+// Returns:
+//   - int: 정상 종료(0), 비정상 종료(>=1)
+//   - error: 정상 종료(nil), 비정상 종료(error)
+func CheckConfig(cmd *cobra.Command) (int, error) {
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "[WARNING] invalid parameter: [*cobra.Command] is nil\n")
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	// 작업 경로 변경 전에 현재 경로 기준의 파일 경로를 절대 경로로 변환
+	filePath := cmd.Flags().Arg(0)
+	if filePath != "" {
+		absPath, err := filepath.Abs(filePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
+			return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+		}
+		filePath = absPath
+	}
+
+	// 작업 경로를 파일 배치 방식의 작업 경로로 변경 (설정 파일 내 상대 경로 기준)
+	err := config.ChangeWorkDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+	if filePath == "" {
+		filePath, _ = config.FilePath()
+	}
+
+	if _, err := config.LoadFile(filePath); err != nil {
+		printConfigError(err)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	fmt.Fprintf(os.Stdout, "[INFO] %s: configuration is valid\n", filePath)
+	return config.ExitCodeSuccess, nil
+}
+
+// ShowConfig 서버가 사용할 최종 설정 값과 출처 출력 (weblin config show)
+//
+// 설정 파일, 환경 변수(WEBLIN_*), 명령행 플래그를 모두 반영하며 비밀 값은 설정 여부만 표시한다.
+//
+// Parameters:
+//   - cmd: 명령어 정보
+//
+// Returns:
+//   - int: 정상 종료(0), 비정상 종료(>=1)
+//   - error: 정상 종료(nil), 비정상 종료(error)
+func ShowConfig(cmd *cobra.Command) (int, error) {
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "[WARNING] invalid parameter: [*cobra.Command] is nil\n")
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	// 작업 경로를 파일 배치 방식의 작업 경로로 변경 (기본: 실행 파일이 위치한 경로)
+	err := config.ChangeWorkDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	conf, err := config.Load()
+	if err != nil {
+		printConfigError(err)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+	for i := range config.Options {
+		opt := &config.Options[i]
+		value := opt.Get(conf)
+		if opt.Secret && value != "" {
+			value = "********"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", opt.Key, value, conf.Source(opt.Key))
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	return config.ExitCodeSuccess, nil
+}
+
+// PrintDefaultConfig 전체 설정 항목을 기본 값으로 주석 처리한 설정 파일 출력 (weblin config defaults)
+//
+// Parameters:
+//   - cmd: 명령어 정보
+//
+// Returns:
+//   - int: 정상 종료(0), 비정상 종료(>=1)
+//   - error: 정상 종료(nil), 비정상 종료(error)
+func PrintDefaultConfig(cmd *cobra.Command) (int, error) {
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "[WARNING] invalid parameter: [*cobra.Command] is nil\n")
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	if err := config.WriteDefaults(os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	return config.ExitCodeSuccess, nil
+}
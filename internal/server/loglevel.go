@@ -0,0 +1,133 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/hoon-kr/weblin/config"
+	"github.com/hoon-kr/weblin/internal/control"
+	"github.com/hoon-kr/weblin/internal/logger"
+	"github.com/hoon-kr/weblin/internal/web"
+	"github.com/spf13/cobra"
+)
+
+// handleLogLevel 로그 레벨 조회 및 변경 (제어 API GET, POST /log-level)
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func handleLogLevel(w http.ResponseWriter, r *http.Request) {
+	if !web.AllowMethods(w, r, http.MethodGet, http.MethodPost) {
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		var req control.LogLevelRequest
+		if err := web.ReadJSON(r, &req); err != nil {
+			web.WriteError(w, http.StatusBadRequest, "%s", err)
+			return
+		}
+		if err := logger.Log.SetLevel(req.Sink, req.Level); err != nil {
+			web.WriteError(w, http.StatusBadRequest, "%s", err)
+			return
+		}
+		sink := req.Sink
+		if sink == "" {
+			sink = "all"
+		}
+		logger.Log.LogInfo("Log level changed (sink:%s, level:%s)", sink, req.Level)
+	}
+
+	web.WriteJSON(w, http.StatusOK, &control.LogLevels{Levels: logger.Log.Levels()})
+}
+
+// LogLevelServer 동작 중인 서버의 로그 레벨 조회 및 변경 (weblin log-level [level] [--sink])
+//
+// 변경한 로그 레벨은 서버를 재가동하거나 설정 다시 읽기로 해당 설정이 변경되기 전까지 유지된다.
+//
+// Parameters:
+//   - cmd: 명령어 정보
+//
+// Returns:
+//   - int: 정상 종료(0), 비정상 종료(>=1)
+//   - error: 정상 종료(nil), 비정상 종료(error)
+func LogLevelServer(cmd *cobra.Command) (int, error) {
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "[WARNING] invalid parameter: [*cobra.Command] is nil\n")
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+	sink, _ := cmd.Flags().GetString("sink")
+
+	// 작업 경로를 파일 배치 방식의 작업 경로로 변경 (기본: 실행 파일이 위치한 경로)
+	err := config.ChangeWorkDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	// 동작 중인 프로세스가 존재하는지 확인
+	var pid int
+	if !isRunning(&pid) {
+		fmt.Fprintf(os.Stderr, "[ERROR] there is no process in operation\n")
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), statusTimeout)
+	defer cancel()
+
+	// 로그 레벨을 지정하지 않은 경우 조회만 수행
+	levels := &control.LogLevels{}
+	if level := cmd.Flags().Arg(0); level != "" {
+		req := &control.LogLevelRequest{Sink: sink, Level: level}
+		err = control.Post(ctx, config.Paths().ControlSocket, control.LogLevelPath, req, levels)
+	} else {
+		err = control.Get(ctx, config.Paths().ControlSocket, control.LogLevelPath, levels)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	printLogLevels(levels.Levels)
+
+	return config.ExitCodeSuccess, nil
+}
+
+// printLogLevels 로그 출력 대상별 로그 레벨 출력
+//
+// Parameters:
+//   - levels: 로그 출력 대상별 로그 레벨
+func printLogLevels(levels map[string]string) {
+	sinks := make([]string, 0, len(levels))
+	for sink := range levels {
+		sinks = append(sinks, sink)
+	}
+	sort.Strings(sinks)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "SINK\tLEVEL\n")
+	for _, sink := range sinks {
+		fmt.Fprintf(w, "%s\t%s\n", sink, levels[sink])
+	}
+	w.Flush()
+}
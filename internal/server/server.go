@@ -20,20 +20,71 @@ Package server 메인 서버 패키지
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"io"
+	"net/http"
 	"os"
 	"os/signal"
-	"strconv"
+	"runtime"
+	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/hoon-kr/weblin/config"
+	"github.com/hoon-kr/weblin/internal/audit"
+	"github.com/hoon-kr/weblin/internal/auth"
+	"github.com/hoon-kr/weblin/internal/control"
+	"github.com/hoon-kr/weblin/internal/filemanager"
 	"github.com/hoon-kr/weblin/internal/logger"
-	"github.com/hoon-kr/weblin/pkg/utils/file"
+	"github.com/hoon-kr/weblin/internal/metrics"
+	"github.com/hoon-kr/weblin/internal/privsep"
+	"github.com/hoon-kr/weblin/internal/procmanager"
+	"github.com/hoon-kr/weblin/internal/session"
+	"github.com/hoon-kr/weblin/internal/sysmon"
+	"github.com/hoon-kr/weblin/internal/terminal"
+	"github.com/hoon-kr/weblin/internal/web"
+	"github.com/hoon-kr/weblin/pkg/utils/goroutine"
 	"github.com/hoon-kr/weblin/pkg/utils/process"
+	"github.com/hoon-kr/weblin/pkg/utils/systemd"
 	"github.com/spf13/cobra"
 )
 
+const (
+	// 고루틴 종료 대기 타임아웃
+	taskStopTimeout = 10 * time.Second
+	// 강제 종료(SIGKILL) 후 프로세스 종료 대기 타임아웃
+	killWaitTimeout = 5 * time.Second
+	// 프로세스 종료 확인 주기
+	exitPollInterval = 100 * time.Millisecond
+)
+
+var (
+	// webServer HTTP/HTTPS 웹 서버
+	webServer = web.NewServer()
+	// taskManager 서버 전체 고루틴 관리
+	taskManager = goroutine.NewGoroutineManager()
+	// sessionManager 로그인 세션 관리
+	sessionManager = session.NewManager(auth.NewShadowAuthenticator())
+	// helperPool 계정별 파일 작업 헬퍼 프로세스 관리
+	helperPool = privsep.NewPool()
+	// fileManager 웹 파일 관리 API
+	fileManager = filemanager.NewManager(helperPool, taskManager)
+	// terminalManager 웹 터미널 세션 관리
+	terminalManager = terminal.NewManager(taskManager)
+	// procManager 웹 프로세스 관리 API
+	procManager = procmanager.NewManager(helperPool)
+	// systemMonitor 시스템 자원 사용량 수집 및 조회 API
+	systemMonitor = sysmon.NewCollector()
+	// controlServer 로컬 제어 API (Unix 소켓)
+	controlServer = control.NewServer()
+	// coreTasks 서버 가동 중 항상 동작해야 하는 고루틴 작업 (상태 확인 시 사용)
+	coreTasks = make(map[string]bool)
+	// reloadMu 설정 다시 읽기 직렬화 (SIGHUP, 제어 API)
+	reloadMu sync.Mutex
+)
+
 // StartServer 서버 가동
 //
 // Parameters:
@@ -48,41 +99,63 @@ func StartServer(cmd *cobra.Command) (int, error) {
 		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
 	}
 
-	// 작업 경로를 실행 파일이 위치한 경로로 변경
-	err := file.ChangeWorkPathToModulePath()
+	// 작업 경로를 파일 배치 방식의 작업 경로로 변경 (기본: 실행 파일이 위치한 경로)
+	// 데몬 프로세스에서 DaemonizeProcess 호출 전에 가동을 중단하는 경우에도 부모 프로세스에 알림
+	err := config.ChangeWorkDir()
 	if err != nil {
+		process.NotifyReady(err)
 		fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
 		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
 	}
+	config.RunConf.Foreground, _ = cmd.Flags().GetBool("foreground")
 
 	// 이미 동작 중인 프로세스가 존재하는지 확인
 	var pid int
 	if isRunning(&pid) {
+		// 부모 프로세스의 확인 이후 다른 프로세스가 먼저 가동된 경우 데몬 프로세스는 가동 실패로 알림
+		process.NotifyReady(fmt.Errorf("there is already a process in operation (pid:%d)", pid))
+		// 포그라운드 가동 시 서비스 관리자가 정상 가동으로 오인하지 않도록 실패 처리
+		if config.RunConf.Foreground {
+			fmt.Fprintf(os.Stderr, "[ERROR] there is already a process in operation (pid:%d)\n", pid)
+			return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+		}
 		fmt.Fprintf(os.Stdout, "[INFO] there is already a process in operation (pid:%d)\n", pid)
 		return config.ExitCodeSuccess, nil
 	}
 
-	// 데몬 프로세스 생성
-	err = process.DaemonizeProcess()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
+	// 설정 파일 로드 (잘못된 설정 파일일 경우 가동 중단)
+	if err := config.LoadConfig(); err != nil {
+		process.NotifyReady(err)
+		printConfigError(err)
 		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
 	}
 
-	// 현재 프로세스의 PID 값 저장
+	// 데몬 프로세스 생성 (부모 프로세스는 데몬 프로세스가 가동 결과를 알릴 때까지 대기 후 종료)
+	if !config.RunConf.Foreground {
+		err = process.DaemonizeProcess(cmd.Use == "debug")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
+			return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+		}
+	}
+
+	// 현재 프로세스의 PID 값 및 가동 시각 저장
 	config.RunConf.Pid = os.Getpid()
+	config.RunConf.StartTime = time.Now()
 
-	// 현재 프로세스의 PID 값을 파일에 기록
-	err = file.WriteDataToTextFile(config.PidFilePath, config.RunConf.Pid, true)
+	// 현재 프로세스의 PID 값을 파일에 기록하고 가동 중 잠금 유지 (종료 시 PID 파일 삭제)
+	pidFile, err := process.LockPidFile(config.Paths().PidFile, config.RunConf.Pid)
 	if err != nil {
+		process.NotifyReady(err)
 		fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
 		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
 	}
+	defer pidFile.Release()
 
-	// 디버그 모드 체크 (디버그 모드일 경우 stdout, stderr 출력)
+	// 디버그 모드 체크 (디버그 모드 또는 포그라운드 가동일 경우 stdout, stderr 출력)
 	if cmd.Use == "debug" {
 		config.RunConf.DebugMode = true
-	} else {
+	} else if !config.RunConf.Foreground {
 		os.Stdout = nil
 		os.Stderr = nil
 	}
@@ -95,22 +168,99 @@ func StartServer(cmd *cobra.Command) (int, error) {
 	// 서버 종료 시 자원 정리
 	defer finalization()
 
-	logger.Log.LogInfo("Start %s (pid:%d, mode:%s)", config.ModuleName, config.RunConf.Pid,
-		func() string {
-			if config.RunConf.DebugMode {
-				return "debug"
+	logger.Log.LogInfo("Start %s (pid:%d, mode:%s)", config.ModuleName, config.RunConf.Pid, runMode())
+
+	// 감사 로그 기록 시작 (감사 로그를 기록할 수 없을 경우 가동 중단)
+	err = audit.Log.Open(config.Paths().AuditLogFile, config.Paths().AuditKeyFile, config.Paths().AuditAnchorFile)
+	if err != nil {
+		process.NotifyReady(err)
+		logger.Log.LogError("%s", err)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	// 등록된 고루틴 작업 가동
+	taskManager.StartAll()
+
+	// systemd 소켓 활성화로 전달받은 리스너가 있을 경우 웹 서버에서 사용
+	err = useActivatedSocket()
+	if err != nil {
+		process.NotifyReady(err)
+		logger.Log.LogError("%s", err)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	// 웹 서버 가동
+	err = webServer.Start()
+	if err != nil {
+		process.NotifyReady(err)
+		logger.Log.LogError("%s", err)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	// 제어 API 가동 (실패하더라도 웹 서버는 계속 동작하며 status 명령어로 상태 조회만 불가)
+	err = controlServer.Start(config.Paths().ControlSocket)
+	if err != nil {
+		logger.Log.LogError("%s", err)
+	}
+
+	// 수신 대기를 시작했으므로 부모 프로세스(weblin start) 및 systemd에 가동 완료 알림
+	process.NotifyReady(nil)
+	notifySystemd(systemd.StateReady, listenStatus())
+
+	// 종료 시그널 대기 (SIGINT, SIGTERM), SIGHUP 수신 시 설정 다시 읽기, SIGUSR1 수신 시 DEBUG 로그 전환
+	exitCode := config.ExitCodeSuccess
+wait:
+	for {
+		select {
+		case sig := <-sigChan:
+			logger.Log.LogInfo("Received %s signal (%d)", sig.String(), sig)
+			switch sig {
+			case syscall.SIGHUP:
+				reloadConfig()
+				continue
+			case syscall.SIGUSR1:
+				toggleDebugLog()
+				continue
 			}
-			return "normal"
-		}())
+			break wait
+		case err := <-webServer.Err():
+			logger.Log.LogError("%s", err)
+			exitCode = config.ExitCodeFailure
+			break wait
+		}
+	}
+
+	notifySystemd(systemd.StateStopping, systemd.Status("Stopping"))
+
+	// 제어 API 정지
+	controlServer.Shutdown()
 
-	// 종료 시그널 대기 (SIGINT, SIGTERM)
-	sig := <-sigChan
-	logger.Log.LogInfo("Received %s signal (%d)", sig.String(), sig)
+	// 종료되지 않는 SSE 스트림을 먼저 종료
+	systemMonitor.CloseStreams()
 
+	// 처리 중인 요청이 완료될 때까지 대기한 후 웹 서버 정지
+	err = webServer.Shutdown(time.Duration(config.Current().ShutdownTimeout) * time.Second)
+	if err != nil {
+		logger.Log.LogWarn("%s", err)
+	}
+
+	// 헬퍼 프로세스가 정지되기 전에 진행 중인 업로드의 임시 파일 정리
+	fileManager.AbortUploads()
+
+	// 웹 터미널 세션 등 모든 고루틴 작업 정지
+	err = taskManager.StopAll(taskStopTimeout)
+	if err != nil {
+		logger.Log.LogWarn("%s", err)
+	}
+	logger.Log.LogInfo("Stop %s (pid:%d)", config.ModuleName, config.RunConf.Pid)
+
+	if exitCode != config.ExitCodeSuccess {
+		return exitCode, fmt.Errorf("%s(%d)", config.ExitFailure, exitCode)
+	}
 	return config.ExitCodeSuccess, nil
 }
 
-// StopServer 서버 정지
+// StopServer 서버 정지 (프로세스가 종료될 때까지 대기)
 //
 // Parameters:
 //   - cmd: 명령어 정보
@@ -124,57 +274,300 @@ func StopServer(cmd *cobra.Command) (int, error) {
 		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
 	}
 
-	// 작업 경로를 실행 파일이 위치한 경로로 변경
-	err := file.ChangeWorkPathToModulePath()
+	// 작업 경로를 파일 배치 방식의 작업 경로로 변경 (기본: 실행 파일이 위치한 경로)
+	err := config.ChangeWorkDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	timeout, _ := cmd.Flags().GetInt("timeout")
+	force, _ := cmd.Flags().GetBool("force")
+	if err := stopServer(time.Duration(timeout)*time.Second, force); err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	return config.ExitCodeSuccess, nil
+}
+
+// RestartServer 서버 재가동 (동작 중인 서버 정지 후 가동)
+//
+// 설정 파일을 먼저 검증하여 잘못된 설정 파일일 경우 동작 중인 서버를 정지하지 않는다.
+//
+// Parameters:
+//   - cmd: 명령어 정보
+//
+// Returns:
+//   - int: 정상 종료(0), 비정상 종료(>=1)
+//   - error: 정상 종료(nil), 비정상 종료(error)
+func RestartServer(cmd *cobra.Command) (int, error) {
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "[WARNING] invalid parameter: [*cobra.Command] is nil\n")
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	// 작업 경로를 파일 배치 방식의 작업 경로로 변경 (기본: 실행 파일이 위치한 경로)
+	err := config.ChangeWorkDir()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
 		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
 	}
 
+	// 설정 파일 검증
+	if _, err := config.Load(); err != nil {
+		printConfigError(err)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	// 동작 중인 서버 정지 (데몬 프로세스로 다시 실행된 경우 이미 정지되어 있음)
+	timeout, _ := cmd.Flags().GetInt("timeout")
+	force, _ := cmd.Flags().GetBool("force")
+	if err := stopServer(time.Duration(timeout)*time.Second, force); err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	return StartServer(cmd)
+}
+
+// stopServer 동작 중인 서버에 정지 시그널(SIGTERM)을 전송하고 프로세스가 종료될 때까지 대기
+//
+// Parameters:
+//   - timeout: 종료 대기 타임아웃
+//   - force: 타임아웃 시 강제 종료(SIGKILL) 여부
+//
+// Returns:
+//   - error: 종료됨 또는 동작 중이지 않음(nil), 실패(error)
+func stopServer(timeout time.Duration, force bool) error {
 	// 동작 중인 프로세스가 존재하는지 확인
 	var pid int
 	if !isRunning(&pid) {
-		return config.ExitCodeSuccess, nil
+		return nil
 	}
 
 	// 서버에 정지 시그널 전송 (SIGTERM)
 	if err := process.SendSignal(pid, syscall.SIGTERM); err != nil {
-		fmt.Fprintf(os.Stderr, "[WARNING] %s\n", err)
+		return err
+	}
+	if waitForExit(timeout) {
+		return nil
+	}
+	if !force {
+		return fmt.Errorf("%s (pid:%d) did not stop within %s", config.ModuleName, pid, timeout)
+	}
+
+	// 타임아웃 시 강제 종료 (SIGKILL, 잠금이 해제되므로 PID 파일은 다음 확인 시 삭제됨)
+	fmt.Fprintf(os.Stderr, "[WARNING] %s (pid:%d) did not stop within %s, sending SIGKILL\n",
+		config.ModuleName, pid, timeout)
+	if err := process.SendSignal(pid, syscall.SIGKILL); err != nil {
+		return err
+	}
+	if !waitForExit(killWaitTimeout) {
+		return fmt.Errorf("%s (pid:%d) did not stop after SIGKILL", config.ModuleName, pid)
+	}
+	return nil
+}
+
+// waitForExit 서버 프로세스가 종료될 때까지 대기
+//
+// Parameters:
+//   - timeout: 종료 대기 타임아웃
+//
+// Returns:
+//   - bool: 종료됨(true), 타임아웃(false)
+func waitForExit(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		var pid int
+		if !isRunning(&pid) {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(exitPollInterval)
+	}
+}
+
+// ReloadServer 동작 중인 서버에 설정 다시 읽기 요청 (제어 API POST /reload)
+//
+// 서버가 사용 중인 설정 파일을 서버가 직접 다시 읽으며, 잘못된 설정 파일일 경우
+// 서버가 반환한 에러를 출력하고 비정상 종료한다.
+//
+// Parameters:
+//   - cmd: 명령어 정보
+//
+// Returns:
+//   - int: 정상 종료(0), 비정상 종료(>=1)
+//   - error: 정상 종료(nil), 비정상 종료(error)
+func ReloadServer(cmd *cobra.Command) (int, error) {
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "[WARNING] invalid parameter: [*cobra.Command] is nil\n")
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	// 작업 경로를 파일 배치 방식의 작업 경로로 변경 (기본: 실행 파일이 위치한 경로)
+	err := config.ChangeWorkDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	// 동작 중인 프로세스가 존재하는지 확인
+	var pid int
+	if !isRunning(&pid) {
+		fmt.Fprintf(os.Stderr, "[ERROR] there is no process in operation\n")
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), statusTimeout)
+	defer cancel()
+
+	result := &control.ReloadResult{}
+	if err := control.Post(ctx, config.Paths().ControlSocket, control.ReloadPath, nil, result); err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
 		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
 	}
 
+	if len(result.Changed) == 0 {
+		fmt.Printf("[INFO] configuration reloaded (no changes)\n")
+	} else {
+		fmt.Printf("[INFO] configuration reloaded (changed: %s)\n", strings.Join(result.Changed, ", "))
+	}
+
 	return config.ExitCodeSuccess, nil
 }
 
-// isRunning 서버가 동작 중인지 확인
+// handleReload 설정 파일 다시 읽기 (제어 API POST /reload)
 //
-// Returns:
-//   - bool: 동작(true), 미동작(false)
-func isRunning(pid *int) bool {
-	if pid == nil {
-		return false
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func handleReload(w http.ResponseWriter, r *http.Request) {
+	if !web.AllowMethods(w, r, http.MethodPost) {
+		return
 	}
 
-	file, err := os.Open(config.PidFilePath)
+	changed, err := reloadConfig()
 	if err != nil {
-		return false
+		web.WriteError(w, http.StatusUnprocessableEntity, "%s", err)
+		return
+	}
+	if changed == nil {
+		changed = []string{}
 	}
-	defer file.Close()
+	web.WriteJSON(w, http.StatusOK, &control.ReloadResult{Changed: changed})
+}
+
+// reloadConfig 설정 파일을 다시 읽어 적용 (잘못된 설정 파일일 경우 현재 설정 유지)
+//
+// SIGHUP 수신과 제어 API 요청이 동시에 들어와도 순서대로 적용한다.
+//
+// Returns:
+//   - []string: 변경된 설정 항목 이름 목록
+//   - error: 성공(nil), 실패(error)
+func reloadConfig() ([]string, error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
 
-	// PID 값 읽기
-	pidStr, err := io.ReadAll(file)
+	changed, err := config.Reload()
 	if err != nil {
+		logger.Log.LogError("Failed to reload configuration, keep current configuration: %s", err)
+		notifySystemd(systemd.Status("Failed to reload configuration: %s", err))
+		return nil, err
+	}
+	logger.Log.LogInfo("Configuration reloaded (changed:%v)", changed)
+	notifySystemd(listenStatus())
+	return changed, nil
+}
+
+// toggleDebugLog 전체 로그 출력 대상의 DEBUG 레벨 전환 (다시 전환하면 설정 값으로 되돌림)
+func toggleDebugLog() {
+	if logger.Log.ToggleDebug() {
+		logger.Log.LogInfo("Debug logging enabled (levels:%v)", logger.Log.Levels())
+		return
+	}
+	logger.Log.LogInfo("Debug logging disabled, log levels restored from configuration (levels:%v)", logger.Log.Levels())
+}
+
+// useActivatedSocket systemd 소켓 활성화(LISTEN_FDS)로 전달받은 리스너를 웹 서버에 설정
+//
+// 웹 서버는 하나의 리스너만 사용하므로 두 번째 이후의 리스너는 닫는다.
+//
+// Returns:
+//   - error: 성공 또는 소켓 활성화가 아님(nil), 실패(error)
+func useActivatedSocket() error {
+	listeners, err := systemd.Listeners()
+	if err != nil || len(listeners) == 0 {
+		return err
+	}
+
+	webServer.SetListener(listeners[0])
+	logger.Log.LogInfo("Use socket passed by systemd (%s)", listeners[0].Addr())
+	for _, listener := range listeners[1:] {
+		logger.Log.LogWarn("Ignore extra socket passed by systemd (%s)", listener.Addr())
+		listener.Close()
+	}
+	return nil
+}
+
+// notifySystemd systemd에 서비스 상태 알림 (systemd에서 실행하지 않은 경우 무시)
+//
+// Parameters:
+//   - states: 상태 메시지 목록 (예: READY=1, STATUS=...)
+func notifySystemd(states ...string) {
+	if err := systemd.Notify(states...); err != nil {
+		logger.Log.LogWarn("%s", err)
+	}
+}
+
+// listenStatus 웹 서버 수신 주소를 systemd 상태 설명 메시지로 반환
+//
+// Returns:
+//   - string: 상태 메시지 (STATUS=...)
+func listenStatus() string {
+	return systemd.Status("Listening on %s", strings.Join(webServer.Addresses(), ", "))
+}
+
+// printConfigError 설정 파일 오류 출력 (검증 오류는 한 줄에 하나씩 출력)
+//
+// Parameters:
+//   - err: 설정 파일 로드 오류
+func printConfigError(err error) {
+	var validationErr *config.ValidationError
+	if !errors.As(err, &validationErr) {
+		fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
+		return
+	}
+	for _, err := range validationErr.Errs {
+		fmt.Fprintf(os.Stderr, "[ERROR] invalid configuration: %s\n", err)
+	}
+}
+
+// isRunning 서버가 동작 중인지 확인
+//
+// PID 파일 잠금을 보유한 프로세스가 없으면 오래된 PID 파일로 보고 삭제하며,
+// 잠금이 유지되고 있더라도 PID가 weblin 프로세스가 아니면 동작 중이지 않은 것으로 본다.
+//
+// Parameters:
+//   - pid: 동작 중인 프로세스의 PID를 저장할 포인터
+//
+// Returns:
+//   - bool: 동작(true), 미동작(false)
+func isRunning(pid *int) bool {
+	if pid == nil {
 		return false
 	}
 
-	// PID 값을 정수로 변환
-	*pid, err = strconv.Atoi(string(pidStr))
-	if err != nil {
+	var err error
+	*pid, err = process.ReadPidFile(config.Paths().PidFile)
+	if err != nil || *pid == 0 {
 		return false
 	}
 
-	// 프로세스 동작 확인
-	return process.IsProcessRun(*pid)
+	// 프로세스 동작 및 실행 파일 확인 (재사용된 PID에 시그널을 전송하지 않도록)
+	return process.IsSameExecutable(*pid)
 }
 
 // setupSignal 시그널 설정
@@ -183,10 +576,10 @@ func isRunning(pid *int) bool {
 //   - chan os.Signal: signal channel
 func setupSignal() chan os.Signal {
 	sigChan := make(chan os.Signal, 1)
-	// 수신할 시그널 설정 (SIGINT, SIGTERM)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	// 수신할 시그널 설정 (SIGINT, SIGTERM, SIGHUP(설정 다시 읽기), SIGUSR1(DEBUG 로그 전환))
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGUSR1)
 	// 무시할 시그널 설정
-	signal.Ignore(syscall.SIGABRT, syscall.SIGALRM, syscall.SIGFPE, syscall.SIGHUP,
+	signal.Ignore(syscall.SIGABRT, syscall.SIGALRM, syscall.SIGFPE,
 		syscall.SIGILL, syscall.SIGPROF, syscall.SIGQUIT, syscall.SIGTSTP,
 		syscall.SIGVTALRM)
 
@@ -195,14 +588,106 @@ func setupSignal() chan os.Signal {
 
 // initialization 서버 초기화
 func initialization() {
-	// 설정 파일 로드
-	config.LoadConfig(config.ConfFilePath)
 	// 로거 초기화
 	logger.Log.InitializeLogger()
+	// 설정 다시 읽기 시 변경 사항을 적용할 대상 등록
+	config.Subscribe(logger.Log.ApplyConfig)
+	config.Subscribe(webServer.ApplyConfig)
+	// 고루틴 작업 등록
+	registerTasks()
+	// 내부 상태 지표 등록
+	registerMetrics()
+	// 웹 서버 핸들러 등록
+	registerHandlers()
+}
+
+// registerTasks 서버 가동 시 함께 가동할 고루틴 작업 등록
+func registerTasks() {
+	// 만료 세션 정리
+	addCoreTask("session-reaper", sessionManager.Reaper)
+	// 유휴 헬퍼 프로세스 정리
+	addCoreTask("privsep-reaper", helperPool.Reaper)
+	// 중단된 업로드 정리
+	addCoreTask("upload-reaper", fileManager.UploadReaper)
+	// 시스템 자원 사용량 수집
+	addCoreTask("metrics-collector", systemMonitor.Run)
+	// systemd watchdog 갱신 (watchdog 사용 시)
+	if systemd.WatchdogInterval() > 0 {
+		addCoreTask("systemd-watchdog", systemd.Watchdog)
+	}
+}
+
+// addCoreTask 서버 가동 중 항상 동작해야 하는 고루틴 작업 등록
+//
+// Parameters:
+//   - name: 작업명
+//   - task: 작업 함수
+func addCoreTask(name string, task func(ctx context.Context)) {
+	taskManager.AddTask(name, task)
+	coreTasks[name] = true
+}
+
+// registerMetrics Prometheus 지표로 제공할 내부 상태 등록
+func registerMetrics() {
+	metrics.NewGaugeFuncVec("weblin_build_info", "Build information of weblin (always 1).", "version",
+		func() map[string]float64 { return map[string]float64{config.Version: 1} })
+	metrics.NewGaugeFunc("weblin_start_time_seconds", "Start time of the weblin process since unix epoch in seconds.",
+		func() float64 { return float64(config.RunConf.StartTime.Unix()) })
+	metrics.NewGaugeFunc("weblin_sessions_active", "Number of active login sessions.",
+		func() float64 { return float64(sessionManager.Count()) })
+	metrics.NewGaugeFunc("weblin_terminals_active", "Number of open web terminal sessions.",
+		func() float64 { return float64(terminalManager.Count()) })
+	metrics.NewGaugeFuncVec("weblin_tasks", "Number of goroutine tasks registered in the task manager by state.", "state",
+		func() map[string]float64 {
+			registered, running := taskManager.Count()
+			return map[string]float64{"registered": float64(registered), "running": float64(running)}
+		})
+	metrics.NewGaugeFunc("go_goroutines", "Number of goroutines that currently exist.",
+		func() float64 { return float64(runtime.NumGoroutine()) })
+}
+
+// registerHandlers 웹 서버 요청 핸들러 등록
+func registerHandlers() {
+	// 서버 상태 확인
+	webServer.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+	})
+	// 로그인, 로그아웃 및 세션 관리
+	webServer.HandleFunc("/api/auth/login", sessionManager.HandleLogin)
+	webServer.Handle("/api/auth/logout", sessionManager.Require(http.HandlerFunc(sessionManager.HandleLogout)))
+	webServer.Handle("/api/auth/session", sessionManager.Require(http.HandlerFunc(sessionManager.HandleCurrent)))
+	webServer.Handle("/api/sessions", sessionManager.Require(http.HandlerFunc(sessionManager.HandleSessions)))
+	webServer.Handle("/api/sessions/", sessionManager.Require(http.HandlerFunc(sessionManager.HandleSessions)))
+	// 파일 관리
+	webServer.Handle(filemanager.PathPrefix, sessionManager.Require(fileManager))
+	// 프로세스 관리
+	webServer.Handle(procmanager.PathPrefix, sessionManager.Require(procManager))
+	// 시스템 자원 사용량
+	webServer.Handle(sysmon.PathPrefix, sessionManager.Require(systemMonitor))
+	// Prometheus 지표 (설정 다시 읽기를 반영할 수 있도록 요청마다 설정 확인)
+	webServer.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		conf := config.Current()
+		if !conf.EnablePrometheus {
+			http.NotFound(w, r)
+			return
+		}
+		metrics.Handler(conf.PrometheusToken).ServeHTTP(w, r)
+	})
+	// 웹 터미널 (WebSocket)
+	webServer.Handle("/ws/terminal", sessionManager.Require(terminalManager))
+
+	// 제어 API
+	controlServer.HandleFunc(control.StatusPath, handleStatus)
+	controlServer.HandleFunc(control.LogLevelPath, handleLogLevel)
+	controlServer.HandleFunc(control.ReloadPath, handleReload)
 }
 
 // finalization 서버 종료 시 자원 정리
 func finalization() {
+	// 감사 로그 기록 종료
+	audit.Log.Close()
 	// 로그 자원 정리
 	logger.Log.FinalizeLogger()
 }
@@ -0,0 +1,178 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hoon-kr/weblin/config"
+	"github.com/hoon-kr/weblin/internal/control"
+	"github.com/hoon-kr/weblin/internal/web"
+	"github.com/spf13/cobra"
+)
+
+// 상태 조회 타임아웃
+const statusTimeout = 5 * time.Second
+
+// handleStatus 서버 상태 조회 (제어 API GET /status)
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func handleStatus(w http.ResponseWriter, r *http.Request) {
+	if !web.AllowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	startTime := config.RunConf.StartTime
+	status := &control.Status{
+		Running:   true,
+		Healthy:   true,
+		Pid:       config.RunConf.Pid,
+		Version:   config.Version,
+		BuildTime: config.BuildTime,
+		Mode:      runMode(),
+		StartTime: &startTime,
+		Uptime:    int64(time.Since(startTime).Seconds()),
+		Listen:    webServer.Addresses(),
+		Sessions:  sessionManager.Count(),
+		Terminals: terminalManager.Count(),
+	}
+
+	for _, task := range taskManager.Tasks() {
+		required := coreTasks[task.Name]
+		if required && !task.Running {
+			status.Healthy = false
+		}
+		status.Tasks = append(status.Tasks, control.TaskStatus{TaskStatus: task, Required: required})
+	}
+
+	web.WriteJSON(w, http.StatusOK, status)
+}
+
+// StatusServer 서버 상태 출력 (weblin status [--json])
+//
+// 종료 코드: 동작 중이며 정상(0), 동작 중이나 비정상 또는 상태 조회 실패(1), 동작 중이지 않음(3)
+//
+// Parameters:
+//   - cmd: 명령어 정보
+//
+// Returns:
+//   - int: 정상 종료(0), 비정상 종료(>=1)
+//   - error: 정상 종료(nil), 비정상 종료(error)
+func StatusServer(cmd *cobra.Command) (int, error) {
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "[WARNING] invalid parameter: [*cobra.Command] is nil\n")
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	// 작업 경로를 파일 배치 방식의 작업 경로로 변경 (기본: 실행 파일이 위치한 경로)
+	err := config.ChangeWorkDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] %s\n", err)
+		return config.ExitCodeFailure, fmt.Errorf("%s(%d)", config.ExitFailure, config.ExitCodeFailure)
+	}
+
+	status := &control.Status{}
+	var pid int
+	if isRunning(&pid) {
+		ctx, cancel := context.WithTimeout(context.Background(), statusTimeout)
+		defer cancel()
+
+		if err := control.Get(ctx, config.Paths().ControlSocket, control.StatusPath, status); err != nil {
+			status = &control.Status{Running: true, Pid: pid, Error: err.Error()}
+		}
+	}
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(status, "", "  ")
+		fmt.Fprintf(os.Stdout, "%s\n", data)
+	} else {
+		printStatus(status)
+	}
+
+	switch {
+	case !status.Running:
+		return config.ExitCodeNotRunning, fmt.Errorf("%s is not running", config.ModuleName)
+	case !status.Healthy:
+		return config.ExitCodeFailure, fmt.Errorf("%s is not healthy", config.ModuleName)
+	}
+	return config.ExitCodeSuccess, nil
+}
+
+// printStatus 서버 상태를 사람이 읽기 쉬운 형식으로 출력
+//
+// Parameters:
+//   - status: 서버 상태
+func printStatus(status *control.Status) {
+	if !status.Running {
+		fmt.Fprintf(os.Stdout, "%s is not running\n", config.ModuleName)
+		return
+	}
+	if status.Error != "" {
+		fmt.Fprintf(os.Stdout, "%s is running (pid:%d) but its status is unavailable: %s\n",
+			config.ModuleName, status.Pid, status.Error)
+		return
+	}
+
+	health := "healthy"
+	if !status.Healthy {
+		health = "unhealthy"
+	}
+	fmt.Fprintf(os.Stdout, "%s is running (pid:%d, %s)\n", config.ModuleName, status.Pid, health)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "  Version:\t%s (build: %s)\n", status.Version, status.BuildTime)
+	fmt.Fprintf(w, "  Mode:\t%s\n", status.Mode)
+	if status.StartTime != nil {
+		fmt.Fprintf(w, "  Uptime:\t%s (since %s)\n", time.Duration(status.Uptime)*time.Second,
+			status.StartTime.Local().Format("2006-01-02 15:04:05"))
+	}
+	fmt.Fprintf(w, "  Listen:\t%s\n", strings.Join(status.Listen, ", "))
+	fmt.Fprintf(w, "  Sessions:\t%d (terminals: %d)\n", status.Sessions, status.Terminals)
+	fmt.Fprintf(w, "  Tasks:\t\n")
+	for _, task := range status.Tasks {
+		state := "running"
+		if !task.Running {
+			state = "stopped"
+		}
+		if task.Required {
+			state += " (required)"
+		}
+		fmt.Fprintf(w, "    %s\t%s\n", task.Name, state)
+	}
+	w.Flush()
+}
+
+// runMode 가동 모드 반환
+//
+// Returns:
+//   - string: 가동 모드 (normal, debug)
+func runMode() string {
+	if config.RunConf.DebugMode {
+		return "debug"
+	}
+	return "normal"
+}
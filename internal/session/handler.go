@@ -0,0 +1,270 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package session
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hoon-kr/weblin/config"
+	"github.com/hoon-kr/weblin/internal/audit"
+	"github.com/hoon-kr/weblin/internal/auth"
+	"github.com/hoon-kr/weblin/internal/logger"
+	"github.com/hoon-kr/weblin/internal/web"
+)
+
+// contextKey 요청 컨텍스트 키 타입
+type contextKey struct{}
+
+// loginRequest 로그인 요청 구조체
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// userResponse 로그인 계정 정보 응답 구조체
+type userResponse struct {
+	Username  string    `json:"username"`
+	Uid       uint32    `json:"uid"`
+	Gid       uint32    `json:"gid"`
+	Home      string    `json:"home"`
+	Shell     string    `json:"shell"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// FromContext 요청 컨텍스트에서 로그인 세션 추출
+//
+// Parameters:
+//   - ctx: 요청 컨텍스트
+//
+// Returns:
+//   - *Session
+//   - bool: 세션 존재(true), 미존재(false)
+func FromContext(ctx context.Context) (*Session, bool) {
+	s, ok := ctx.Value(contextKey{}).(*Session)
+	return s, ok
+}
+
+// Username 요청 컨텍스트의 로그인 계정 이름
+//
+// Parameters:
+//   - ctx: 요청 컨텍스트
+//
+// Returns:
+//   - string: 로그인 계정 이름 (로그인 세션이 없을 경우 빈 문자열)
+func Username(ctx context.Context) string {
+	if s, ok := FromContext(ctx); ok {
+		return s.User.Username
+	}
+	return ""
+}
+
+// Require 로그인 세션이 있는 요청만 허용하는 미들웨어
+//
+// Parameters:
+//   - next: 세션 확인 후 호출할 핸들러
+//
+// Returns:
+//   - http.Handler
+func (m *Manager) Require(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(CookieName)
+		if err != nil {
+			web.WriteError(w, http.StatusUnauthorized, "login required")
+			return
+		}
+
+		s, ok := m.Lookup(cookie.Value)
+		if !ok {
+			clearCookie(w, r)
+			web.WriteError(w, http.StatusUnauthorized, "session expired")
+			return
+		}
+
+		// 요청 처리 중 기록하는 로그에 세션 정보 추가
+		log := logger.FromContext(r.Context()).With(
+			logger.String(logger.KeySessionID, s.PublicID()),
+			logger.String(logger.KeyUser, s.User.Username),
+		)
+		ctx := context.WithValue(r.Context(), contextKey{}, s)
+		ctx = logger.NewContext(ctx, log)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// HandleLogin 로그인 요청 처리 (POST)
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (m *Manager) HandleLogin(w http.ResponseWriter, r *http.Request) {
+	if !web.AllowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	var req loginRequest
+	if err := web.ReadJSON(r, &req); err != nil {
+		web.WriteError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	log := logger.FromContext(r.Context()).With(logger.String(logger.KeyUser, req.Username))
+	s, err := m.Login(req.Username, req.Password, r.RemoteAddr)
+	if err != nil {
+		audit.Log.Record(audit.NewEntry(r, req.Username, audit.ActionLogin, ""), err)
+		if auth.IsAuthError(err) {
+			log.Warn("Login failed", logger.Err(err))
+			// 잠긴 계정(로그인 불가 셸 등)은 계정 상태를 알려주지 않도록 계정 정보 불일치로 응답
+			// (지원하지 않는 해시 형식 등 로그에만 기록하는 상세 내용도 제외)
+			if errors.Is(err, auth.ErrAccountLocked) || errors.Is(err, auth.ErrInvalidCredentials) {
+				err = auth.ErrInvalidCredentials
+			}
+			web.WriteError(w, http.StatusUnauthorized, "%s", err)
+			return
+		}
+		log.Error("Login error", logger.Err(err))
+		web.WriteError(w, http.StatusInternalServerError, "authentication unavailable")
+		return
+	}
+
+	log.Info("Login succeeded", logger.String(logger.KeySessionID, s.PublicID()))
+	audit.Log.Record(audit.NewEntry(r, s.User.Username, audit.ActionLogin, s.PublicID()), nil)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     CookieName,
+		Value:    s.ID,
+		Path:     "/",
+		Expires:  s.ExpiresAt,
+		Secure:   isSecure(r),
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+	web.WriteJSON(w, http.StatusOK, newUserResponse(s))
+}
+
+// HandleLogout 로그아웃 요청 처리 (POST, 세션 필요)
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (m *Manager) HandleLogout(w http.ResponseWriter, r *http.Request) {
+	if !web.AllowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	s, _ := FromContext(r.Context())
+	m.Revoke(s.ID)
+	logger.FromContext(r.Context()).Info("Logout")
+	audit.Log.Record(audit.NewEntry(r, s.User.Username, audit.ActionLogout, s.PublicID()), nil)
+
+	clearCookie(w, r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleCurrent 현재 로그인 계정 정보 조회 (GET, 세션 필요)
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (m *Manager) HandleCurrent(w http.ResponseWriter, r *http.Request) {
+	if !web.AllowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	s, _ := FromContext(r.Context())
+	web.WriteJSON(w, http.StatusOK, newUserResponse(s))
+}
+
+// HandleSessions 세션 목록 조회(GET /api/sessions) 및 폐기(DELETE /api/sessions/{id}) (세션 필요)
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (m *Manager) HandleSessions(w http.ResponseWriter, r *http.Request) {
+	if !web.AllowMethods(w, r, http.MethodGet, http.MethodDelete) {
+		return
+	}
+
+	s, _ := FromContext(r.Context())
+	pubID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/sessions"), "/")
+
+	switch r.Method {
+	case http.MethodGet:
+		web.WriteJSON(w, http.StatusOK, m.List(s))
+	case http.MethodDelete:
+		if pubID == "" {
+			web.WriteError(w, http.StatusBadRequest, "session id is required")
+			return
+		}
+		if !m.RevokeByPublicID(pubID, s) {
+			web.WriteError(w, http.StatusNotFound, "session not found")
+			return
+		}
+		logger.FromContext(r.Context()).Info("Session revoked", logger.String("revoked_session_id", pubID))
+		audit.Log.Record(audit.NewEntry(r, s.User.Username, audit.ActionSessionRevoke, pubID), nil)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// newUserResponse 로그인 계정 정보 응답 생성
+//
+// Parameters:
+//   - s: 로그인 세션
+//
+// Returns:
+//   - userResponse
+func newUserResponse(s *Session) userResponse {
+	return userResponse{
+		Username:  s.User.Username,
+		Uid:       s.User.Uid,
+		Gid:       s.User.Gid,
+		Home:      s.User.Home,
+		Shell:     s.User.Shell,
+		ExpiresAt: s.ExpiresAt,
+	}
+}
+
+// clearCookie 세션 쿠키 삭제
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func clearCookie(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     CookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		Secure:   isSecure(r),
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// isSecure HTTPS 요청인지 확인 (Secure 쿠키 설정 여부)
+//
+// Parameters:
+//   - r: 요청 정보
+//
+// Returns:
+//   - bool: HTTPS(true), HTTP(false)
+func isSecure(r *http.Request) bool {
+	return r.TLS != nil || config.Current().EnableTLS
+}
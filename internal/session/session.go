@@ -0,0 +1,406 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+/*
+Package session 로그인 세션 관리 패키지
+*/
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hoon-kr/weblin/config"
+	"github.com/hoon-kr/weblin/internal/auth"
+	"github.com/hoon-kr/weblin/internal/logger"
+)
+
+const (
+	// CookieName 세션 쿠키 이름
+	CookieName = "weblin_session"
+	// 만료 세션 정리 주기
+	reapInterval = time.Minute
+	// 세션 ID 바이트 수
+	sessionIDBytes = 32
+)
+
+// Session 개별 로그인 세션 정보 구조체
+type Session struct {
+	ID         string
+	User       *auth.User
+	RemoteAddr string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+
+	mu       sync.Mutex
+	lastSeen time.Time
+	done     chan struct{}
+	once     sync.Once
+}
+
+// Info 세션 조회 결과 구조체 (세션 ID 원문은 노출하지 않음)
+type Info struct {
+	ID         string    `json:"id"`
+	Username   string    `json:"username"`
+	RemoteAddr string    `json:"remoteAddr"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeen   time.Time `json:"lastSeen"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Current    bool      `json:"current"`
+}
+
+// Manager 로그인 세션 관리 정보 구조체
+type Manager struct {
+	mu       sync.Mutex
+	auth     auth.Authenticator
+	sessions map[string]*Session
+}
+
+// NewManager 세션 관리 구조체 생성
+//
+// Parameters:
+//   - authenticator: 계정 인증 인터페이스
+//
+// Returns:
+//   - *Manager
+func NewManager(authenticator auth.Authenticator) *Manager {
+	return &Manager{
+		auth:     authenticator,
+		sessions: make(map[string]*Session),
+	}
+}
+
+// Touch 세션 사용 시각 갱신 (유휴 만료 시간 연장)
+func (s *Session) Touch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastSeen = time.Now()
+}
+
+// LastSeen 마지막 세션 사용 시각 반환
+//
+// Returns:
+//   - time.Time: 마지막 사용 시각
+func (s *Session) LastSeen() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastSeen
+}
+
+// Done 세션이 만료되거나 폐기될 때 닫히는 채널 반환
+//
+// Returns:
+//   - <-chan struct{}: 세션 종료 채널
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// expired 세션 만료 여부 확인 (절대 만료 시간 및 유휴 만료 시간)
+//
+// Parameters:
+//   - now: 현재 시각
+//
+// Returns:
+//   - bool: 만료(true), 유효(false)
+func (s *Session) expired(now time.Time) bool {
+	idle := time.Duration(config.Current().SessionIdleTimeout) * time.Minute
+	return now.After(s.ExpiresAt) || now.Sub(s.LastSeen()) > idle
+}
+
+// close 세션 종료 알림
+func (s *Session) close() {
+	s.once.Do(func() {
+		close(s.done)
+	})
+}
+
+// info 세션 조회 결과 생성
+//
+// Parameters:
+//   - current: 요청한 세션과 동일한지 여부
+//
+// Returns:
+//   - Info: 세션 조회 결과
+func (s *Session) info(current bool) Info {
+	return Info{
+		ID:         publicID(s.ID),
+		Username:   s.User.Username,
+		RemoteAddr: s.RemoteAddr,
+		CreatedAt:  s.CreatedAt,
+		LastSeen:   s.LastSeen(),
+		ExpiresAt:  s.ExpiresAt,
+		Current:    current,
+	}
+}
+
+// Login 계정을 인증하고 새로운 세션 생성
+//
+// Parameters:
+//   - username: 사용자명
+//   - password: 평문 비밀번호
+//   - remoteAddr: 클라이언트 주소
+//
+// Returns:
+//   - *Session
+//   - error: 성공(nil), 실패(error)
+func (m *Manager) Login(username, password, remoteAddr string) (*Session, error) {
+	user, err := m.auth.Authenticate(username, password)
+	if err != nil {
+		return nil, err
+	}
+	return m.Create(user, remoteAddr)
+}
+
+// Create 인증된 계정에 대한 새로운 세션 생성
+//
+// Parameters:
+//   - user: 인증된 계정 정보
+//   - remoteAddr: 클라이언트 주소
+//
+// Returns:
+//   - *Session
+//   - error: 성공(nil), 실패(error)
+func (m *Manager) Create(user *auth.User, remoteAddr string) (*Session, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	s := &Session{
+		ID:         id,
+		User:       user,
+		RemoteAddr: remoteAddr,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(time.Duration(config.Current().SessionTimeout) * time.Minute),
+		lastSeen:   now,
+		done:       make(chan struct{}),
+	}
+
+	m.mu.Lock()
+	m.sessions[id] = s
+	m.mu.Unlock()
+
+	return s, nil
+}
+
+// Lookup 세션 ID로 유효한 세션 조회 (조회 시 사용 시각 갱신)
+//
+// Parameters:
+//   - id: 세션 ID
+//
+// Returns:
+//   - *Session
+//   - bool: 유효한 세션 존재(true), 미존재 또는 만료(false)
+func (m *Manager) Lookup(id string) (*Session, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, exists := m.sessions[id]
+	if !exists {
+		return nil, false
+	}
+
+	if s.expired(time.Now()) {
+		delete(m.sessions, id)
+		s.close()
+		return nil, false
+	}
+
+	s.Touch()
+	return s, true
+}
+
+// Revoke 세션 폐기
+//
+// Parameters:
+//   - id: 세션 ID
+//
+// Returns:
+//   - bool: 폐기(true), 미존재(false)
+func (m *Manager) Revoke(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, exists := m.sessions[id]
+	if !exists {
+		return false
+	}
+	delete(m.sessions, id)
+	s.close()
+
+	return true
+}
+
+// RevokeByPublicID 공개 ID로 세션 폐기
+//
+// Parameters:
+//   - pubID: 세션 공개 ID
+//   - owner: 폐기를 요청한 세션 (root가 아닐 경우 본인 세션만 폐기 가능)
+//
+// Returns:
+//   - bool: 폐기(true), 미존재 또는 권한 없음(false)
+func (m *Manager) RevokeByPublicID(pubID string, owner *Session) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, s := range m.sessions {
+		if publicID(id) != pubID {
+			continue
+		}
+		if owner.User.Uid != 0 && s.User.Uid != owner.User.Uid {
+			return false
+		}
+		delete(m.sessions, id)
+		s.close()
+		return true
+	}
+
+	return false
+}
+
+// List 세션 목록 조회
+//
+// Parameters:
+//   - owner: 조회를 요청한 세션 (root가 아닐 경우 본인 세션만 조회)
+//
+// Returns:
+//   - []Info: 세션 목록 (생성 시각 순)
+func (m *Manager) List(owner *Session) []Info {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	list := make([]Info, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		if s.expired(now) {
+			continue
+		}
+		if owner.User.Uid != 0 && s.User.Uid != owner.User.Uid {
+			continue
+		}
+		list = append(list, s.info(s == owner))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+
+	return list
+}
+
+// Count 유효한 세션 개수 반환 (정리 주기 전의 만료된 세션 제외)
+//
+// Returns:
+//   - int: 세션 개수
+func (m *Manager) Count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	count := 0
+	for _, s := range m.sessions {
+		if !s.expired(now) {
+			count++
+		}
+	}
+	return count
+}
+
+// Reaper 만료된 세션을 주기적으로 정리하는 고루틴 작업
+//
+// Parameters:
+//   - ctx: 작업 종료 컨텍스트
+func (m *Manager) Reaper(ctx context.Context) {
+	ticker := time.NewTicker(reapInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// 서버 종료 시 모든 세션 종료
+			m.mu.Lock()
+			for id, s := range m.sessions {
+				delete(m.sessions, id)
+				s.close()
+			}
+			m.mu.Unlock()
+			return
+		case now := <-ticker.C:
+			m.reap(now)
+		}
+	}
+}
+
+// reap 만료된 세션 정리
+//
+// Parameters:
+//   - now: 현재 시각
+func (m *Manager) reap(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, s := range m.sessions {
+		if s.expired(now) {
+			delete(m.sessions, id)
+			s.close()
+			logger.Log.Info("Session expired",
+				logger.String(logger.KeySessionID, s.PublicID()),
+				logger.String(logger.KeyUser, s.User.Username),
+				logger.String(logger.KeyRemote, s.RemoteAddr))
+		}
+	}
+}
+
+// newSessionID 임의의 세션 ID 생성
+//
+// Returns:
+//   - string: 세션 ID
+//   - error: 성공(nil), 실패(error)
+func newSessionID() (string, error) {
+	buf := make([]byte, sessionIDBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// PublicID 세션 공개 ID 반환 (로그 및 세션 목록에서 세션 ID 원문 대신 사용)
+//
+// Returns:
+//   - string: 공개 ID
+func (s *Session) PublicID() string {
+	return publicID(s.ID)
+}
+
+// publicID 세션 ID 원문 대신 목록 조회/폐기에 사용하는 공개 ID 생성
+//
+// Parameters:
+//   - id: 세션 ID
+//
+// Returns:
+//   - string: 공개 ID
+func publicID(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:8])
+}
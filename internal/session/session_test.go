@@ -0,0 +1,68 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hoon-kr/weblin/config"
+	"github.com/hoon-kr/weblin/internal/auth"
+)
+
+func TestCountExcludesExpired(t *testing.T) {
+	m := NewManager(nil)
+	alice := &auth.User{Username: "alice", Uid: 1000}
+	idle := time.Duration(config.Current().SessionIdleTimeout) * time.Minute
+
+	tests := []struct {
+		name   string
+		expire func(s *Session)
+		valid  bool
+	}{
+		{"valid", func(s *Session) {}, true},
+		{"lifetime exceeded", func(s *Session) { s.ExpiresAt = time.Now().Add(-time.Second) }, false},
+		{"idle timeout exceeded", func(s *Session) { s.lastSeen = time.Now().Add(-idle - time.Second) }, false},
+		{"recently used", func(s *Session) { s.lastSeen = time.Now().Add(-idle + time.Minute) }, true},
+	}
+	ids := make(map[string]string, len(tests))
+	for _, tt := range tests {
+		s, err := m.Create(alice, "127.0.0.1")
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		tt.expire(s)
+		ids[tt.name] = s.ID
+	}
+
+	// 만료된 세션은 정리 전에도 개수와 목록에서 제외
+	if got := m.Count(); got != 2 {
+		t.Errorf("Count() = %d, want 2", got)
+	}
+	owner, _ := m.Lookup(ids["valid"])
+	if got := len(m.List(owner)); got != 2 {
+		t.Errorf("len(List()) = %d, want 2", got)
+	}
+	for _, tt := range tests {
+		if _, ok := m.Lookup(ids[tt.name]); ok != tt.valid {
+			t.Errorf("Lookup(%s) = %t, want %t", tt.name, ok, tt.valid)
+		}
+	}
+	if got := m.Count(); got != 2 {
+		t.Errorf("Count() after Lookup = %d, want 2", got)
+	}
+}
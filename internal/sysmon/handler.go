@@ -0,0 +1,188 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package sysmon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hoon-kr/weblin/config"
+	"github.com/hoon-kr/weblin/internal/session"
+	"github.com/hoon-kr/weblin/internal/web"
+)
+
+// PathPrefix 시스템 자원 사용량 API 경로
+const PathPrefix = "/api/system/"
+
+// historyResponse 사용량 이력 응답 구조체
+type historyResponse struct {
+	// 수집 주기(초)
+	Interval int         `json:"interval"`
+	Samples  []*Snapshot `json:"samples"`
+}
+
+// ServeHTTP 시스템 자원 사용량 API 요청 처리 (로그인 세션 필요)
+//
+//   - GET /api/system/current               현재 사용량
+//   - GET /api/system/history?since=&limit= 사용량 이력 (since: RFC3339 또는 unix 시간(초))
+//   - GET /api/system/stream                사용량 SSE 스트림 (event: sample)
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !web.AllowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	switch strings.TrimPrefix(r.URL.Path, PathPrefix) {
+	case "current":
+		c.handleCurrent(w, r)
+	case "history":
+		c.handleHistory(w, r)
+	case "stream":
+		c.handleStream(w, r)
+	default:
+		web.WriteError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// handleCurrent 현재 사용량 조회 (GET /api/system/current)
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (c *Collector) handleCurrent(w http.ResponseWriter, _ *http.Request) {
+	s := c.Latest()
+	if s == nil {
+		web.WriteError(w, http.StatusServiceUnavailable, "metrics not collected yet")
+		return
+	}
+	web.WriteJSON(w, http.StatusOK, s)
+}
+
+// handleHistory 사용량 이력 조회 (GET /api/system/history)
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (c *Collector) handleHistory(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var since time.Time
+	if sinceStr := query.Get("since"); sinceStr != "" {
+		if sec, err := strconv.ParseInt(sinceStr, 10, 64); err == nil {
+			since = time.Unix(sec, 0)
+		} else if since, err = time.Parse(time.RFC3339, sinceStr); err != nil {
+			web.WriteError(w, http.StatusBadRequest, "invalid since: %s", sinceStr)
+			return
+		}
+	}
+
+	limit := 0
+	if limitStr := query.Get("limit"); limitStr != "" {
+		value, err := strconv.Atoi(limitStr)
+		if err != nil || value < 0 {
+			web.WriteError(w, http.StatusBadRequest, "invalid limit: %s", limitStr)
+			return
+		}
+		limit = value
+	}
+
+	web.WriteJSON(w, http.StatusOK, &historyResponse{
+		Interval: config.Current().MetricsInterval,
+		Samples:  c.History(since, limit),
+	})
+}
+
+// handleStream 사용량 SSE 스트림 (GET /api/system/stream)
+//
+// 연결 직후 현재 사용량을 전달하고 이후 수집할 때마다 전달한다.
+// 클라이언트 연결 종료, 로그인 세션 만료 또는 서버 종료 시 스트림을 종료한다.
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (c *Collector) handleStream(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	// 장시간 유지되는 응답이므로 쓰기 타임아웃(WriteTimeout) 해제
+	rc.SetWriteDeadline(time.Time{})
+
+	var sessionDone <-chan struct{}
+	if s, ok := session.FromContext(r.Context()); ok {
+		sessionDone = s.Done()
+	}
+
+	ch := c.subscribe()
+	defer c.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	// 리버스 프록시(nginx)의 응답 버퍼링 해제
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// 연결이 끊긴 경우 수집 주기 후 재연결하도록 안내
+	fmt.Fprintf(w, "retry: %d\n\n", config.Current().MetricsInterval*1000)
+	if s := c.Latest(); s != nil {
+		if err := writeEvent(w, s); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-sessionDone:
+			return
+		case <-c.closed:
+			return
+		case s := <-ch:
+			if err := writeEvent(w, s); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeEvent 사용량을 SSE 이벤트 형식으로 전송
+//
+// Parameters:
+//   - w: 응답 writer
+//   - s: 사용량
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func writeEvent(w http.ResponseWriter, s *Snapshot) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: sample\ndata: %s\n\n", s.Time.UnixMilli(), data)
+	return err
+}
@@ -0,0 +1,431 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+/*
+Package sysmon 시스템 자원 사용량 수집 및 조회 API 패키지
+
+수집 작업(Run)은 설정된 주기로 CPU, 메모리, 부하, 네트워크, 디스크, 파일 시스템 사용량을
+수집하여 메모리 내 이력(ring buffer)에 보관하고, 구독 중인 SSE 스트림에 전달한다.
+*/
+package sysmon
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hoon-kr/weblin/config"
+	"github.com/hoon-kr/weblin/internal/logger"
+	"github.com/hoon-kr/weblin/pkg/utils/sysstat"
+)
+
+// 수집 작업 시작 후 첫 사용량 계산까지의 최대 대기 시간
+const firstSampleDelay = time.Second
+
+// Snapshot 특정 시점의 시스템 자원 사용량 구조체
+type Snapshot struct {
+	Time        time.Time            `json:"time"`
+	CPU         *CPUUsage            `json:"cpu,omitempty"`
+	Memory      *sysstat.Memory      `json:"memory,omitempty"`
+	Load        *sysstat.Load        `json:"load,omitempty"`
+	Network     []NetUsage           `json:"network"`
+	Disks       []DiskUsage          `json:"disks"`
+	Filesystems []sysstat.Filesystem `json:"filesystems"`
+}
+
+// CPUUsage CPU 사용률 구조체 (%)
+type CPUUsage struct {
+	Percent float64 `json:"percent"`
+	// user, nice 합계
+	User   float64 `json:"user"`
+	System float64 `json:"system"`
+	IOWait float64 `json:"iowait"`
+	Steal  float64 `json:"steal"`
+	// 코어별 사용률
+	Cores []float64 `json:"cores"`
+}
+
+// NetUsage 네트워크 인터페이스 사용량 구조체 (rate: 초당 바이트)
+type NetUsage struct {
+	Name     string  `json:"name"`
+	RxBytes  uint64  `json:"rxBytes"`
+	TxBytes  uint64  `json:"txBytes"`
+	RxRate   float64 `json:"rxRate"`
+	TxRate   float64 `json:"txRate"`
+	RxErrors uint64  `json:"rxErrors"`
+	TxErrors uint64  `json:"txErrors"`
+}
+
+// DiskUsage 블록 장치 I/O 사용량 구조체 (rate: 초당 바이트)
+type DiskUsage struct {
+	Name       string  `json:"name"`
+	ReadBytes  uint64  `json:"readBytes"`
+	WriteBytes uint64  `json:"writeBytes"`
+	ReadRate   float64 `json:"readRate"`
+	WriteRate  float64 `json:"writeRate"`
+	ReadIOPS   float64 `json:"readIops"`
+	WriteIOPS  float64 `json:"writeIops"`
+	// I/O 처리 중이었던 시간 비율 (%)
+	Utilization float64 `json:"utilization"`
+}
+
+// Collector 시스템 자원 사용량 수집 정보 구조체
+type Collector struct {
+	mu sync.Mutex
+	// 사용량 이력 (ring buffer)
+	history []*Snapshot
+	head    int
+	count   int
+	// SSE 스트림 구독 채널
+	subscribers map[chan *Snapshot]struct{}
+	closed      chan struct{}
+	closeOnce   sync.Once
+
+	// 이전 수집 시점의 누적 값 (수집 작업 고루틴에서만 사용)
+	prevTime time.Time
+	prevCPU  []sysstat.CPUTimes
+	prevNet  map[string]sysstat.NetDev
+	prevDisk map[string]sysstat.DiskStat
+	lastErr  string
+}
+
+// NewCollector 시스템 자원 사용량 수집 구조체 생성
+//
+// Returns:
+//   - *Collector
+func NewCollector() *Collector {
+	return &Collector{
+		subscribers: make(map[chan *Snapshot]struct{}),
+		closed:      make(chan struct{}),
+	}
+}
+
+// Run 설정된 주기로 시스템 자원 사용량 수집 (고루틴 작업)
+//
+// Parameters:
+//   - ctx: 작업 종료 컨텍스트
+func (c *Collector) Run(ctx context.Context) {
+	defer c.CloseStreams()
+
+	conf := config.Current()
+	interval := time.Duration(conf.MetricsInterval) * time.Second
+	c.resize(conf.MetricsHistorySize)
+
+	// 누적 값의 기준을 수집한 뒤 짧은 간격으로 첫 사용량을 계산하여
+	// 수집 주기가 길더라도 가동 직후부터 현재 사용량을 조회할 수 있도록 함
+	c.sample(time.Now())
+	select {
+	case <-ctx.Done():
+		return
+	case now := <-time.After(min(interval, firstSampleDelay)):
+		c.publish(c.sample(now))
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			c.publish(c.sample(now))
+
+			// 설정 다시 읽기로 변경된 수집 주기 및 이력 보관 개수 반영
+			conf := config.Current()
+			if d := time.Duration(conf.MetricsInterval) * time.Second; d != interval {
+				interval = d
+				ticker.Reset(interval)
+			}
+			c.resize(conf.MetricsHistorySize)
+		}
+	}
+}
+
+// resize 이력 보관 개수 변경 (최근 이력부터 유지)
+//
+// Parameters:
+//   - size: 이력 보관 개수
+func (c *Collector) resize(size int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if size == len(c.history) {
+		return
+	}
+
+	history := make([]*Snapshot, size)
+	keep := min(c.count, size)
+	start := c.head - keep + len(c.history)
+	for i := 0; i < keep; i++ {
+		history[i] = c.history[(start+i)%len(c.history)]
+	}
+	c.history, c.head, c.count = history, keep%size, keep
+}
+
+// Latest 가장 최근 사용량 반환
+//
+// Returns:
+//   - *Snapshot: 최근 사용량 (수집 전일 경우 nil)
+func (c *Collector) Latest() *Snapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.count == 0 {
+		return nil
+	}
+	return c.history[(c.head+len(c.history)-1)%len(c.history)]
+}
+
+// History 사용량 이력 반환 (오래된 순)
+//
+// Parameters:
+//   - since: 이 시각 이후의 이력만 반환 (zero value일 경우 전체)
+//   - limit: 최근 이력 최대 개수 (0일 경우 전체)
+//
+// Returns:
+//   - []*Snapshot: 사용량 이력
+func (c *Collector) History(since time.Time, limit int) []*Snapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshots := make([]*Snapshot, 0, c.count)
+	start := c.head - c.count + len(c.history)
+	for i := 0; i < c.count; i++ {
+		s := c.history[(start+i)%len(c.history)]
+		if s.Time.After(since) {
+			snapshots = append(snapshots, s)
+		}
+	}
+
+	if limit > 0 && len(snapshots) > limit {
+		snapshots = snapshots[len(snapshots)-limit:]
+	}
+	return snapshots
+}
+
+// CloseStreams 모든 SSE 스트림 종료 (서버 종료 시 웹 서버 정지 전에 호출)
+//
+// SSE 스트림은 클라이언트가 연결을 끊기 전까지 요청이 끝나지 않으므로
+// 먼저 종료하지 않으면 웹 서버 정지가 타임아웃까지 대기한다.
+func (c *Collector) CloseStreams() {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+}
+
+// subscribe 신규 사용량을 전달받을 채널 등록
+//
+// Returns:
+//   - chan *Snapshot: 구독 채널
+func (c *Collector) subscribe() chan *Snapshot {
+	ch := make(chan *Snapshot, 1)
+	c.mu.Lock()
+	c.subscribers[ch] = struct{}{}
+	c.mu.Unlock()
+	return ch
+}
+
+// unsubscribe 구독 채널 해제
+//
+// Parameters:
+//   - ch: 구독 채널
+func (c *Collector) unsubscribe(ch chan *Snapshot) {
+	c.mu.Lock()
+	delete(c.subscribers, ch)
+	c.mu.Unlock()
+}
+
+// publish 사용량을 이력에 저장하고 구독 채널에 전달
+//
+// 수신이 밀린 구독 채널에는 전달하지 않는다 (느린 클라이언트로 인한 수집 지연 방지).
+//
+// Parameters:
+//   - s: 사용량
+func (c *Collector) publish(s *Snapshot) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.history[c.head] = s
+	c.head = (c.head + 1) % len(c.history)
+	c.count = min(c.count+1, len(c.history))
+
+	for ch := range c.subscribers {
+		select {
+		case ch <- s:
+		default:
+		}
+	}
+}
+
+// sample 시스템 자원 사용량 수집 및 이전 수집 시점 대비 사용률, 변화량 계산
+//
+// 일부 항목을 읽지 못하더라도(컨테이너 환경 등) 나머지 항목은 수집한다.
+//
+// Parameters:
+//   - now: 수집 시각
+//
+// Returns:
+//   - *Snapshot: 사용량
+func (c *Collector) sample(now time.Time) *Snapshot {
+	s := &Snapshot{Time: now}
+	elapsed := now.Sub(c.prevTime).Seconds()
+	var errs []string
+
+	if cpus, err := sysstat.ReadCPU(); err == nil {
+		s.CPU = cpuUsage(c.prevCPU, cpus)
+		c.prevCPU = cpus
+	} else {
+		errs = append(errs, err.Error())
+	}
+
+	if mem, err := sysstat.ReadMemory(); err == nil {
+		s.Memory = mem
+	} else {
+		errs = append(errs, err.Error())
+	}
+
+	if load, err := sysstat.ReadLoad(); err == nil {
+		s.Load = load
+	} else {
+		errs = append(errs, err.Error())
+	}
+
+	if devs, err := sysstat.ReadNetDev(); err == nil {
+		current := make(map[string]sysstat.NetDev, len(devs))
+		for _, dev := range devs {
+			current[dev.Name] = dev
+			usage := NetUsage{
+				Name:     dev.Name,
+				RxBytes:  dev.RxBytes,
+				TxBytes:  dev.TxBytes,
+				RxErrors: dev.RxErrors,
+				TxErrors: dev.TxErrors,
+			}
+			if prev, ok := c.prevNet[dev.Name]; ok {
+				usage.RxRate = rate(prev.RxBytes, dev.RxBytes, elapsed)
+				usage.TxRate = rate(prev.TxBytes, dev.TxBytes, elapsed)
+			}
+			s.Network = append(s.Network, usage)
+		}
+		c.prevNet = current
+	} else {
+		errs = append(errs, err.Error())
+	}
+
+	if disks, err := sysstat.ReadDiskStats(); err == nil {
+		current := make(map[string]sysstat.DiskStat, len(disks))
+		for _, disk := range disks {
+			current[disk.Name] = disk
+			usage := DiskUsage{
+				Name:       disk.Name,
+				ReadBytes:  disk.ReadBytes,
+				WriteBytes: disk.WriteBytes,
+			}
+			if prev, ok := c.prevDisk[disk.Name]; ok {
+				usage.ReadRate = rate(prev.ReadBytes, disk.ReadBytes, elapsed)
+				usage.WriteRate = rate(prev.WriteBytes, disk.WriteBytes, elapsed)
+				usage.ReadIOPS = rate(prev.Reads, disk.Reads, elapsed)
+				usage.WriteIOPS = rate(prev.Writes, disk.Writes, elapsed)
+				// IOTime은 밀리초 단위
+				usage.Utilization = sysstat.Round(min(rate(prev.IOTime, disk.IOTime, elapsed)/10, 100))
+			}
+			s.Disks = append(s.Disks, usage)
+		}
+		c.prevDisk = current
+	} else {
+		errs = append(errs, err.Error())
+	}
+
+	if filesystems, err := sysstat.ReadFilesystems(); err == nil {
+		s.Filesystems = filesystems
+	} else {
+		errs = append(errs, err.Error())
+	}
+
+	c.prevTime = now
+
+	// 같은 에러가 수집 주기마다 반복 기록되지 않도록 변경된 경우에만 기록
+	if errMsg := strings.Join(errs, ", "); errMsg != c.lastErr {
+		if errMsg != "" {
+			logger.Log.LogWarn("Failed to collect system metrics: %s", errMsg)
+		}
+		c.lastErr = errMsg
+	}
+
+	return s
+}
+
+// cpuUsage 두 시점의 CPU 누적 사용 시간으로 사용률 계산
+//
+// Parameters:
+//   - prev: 이전 누적 사용 시간 (nil일 경우 부팅 이후 평균 사용률)
+//   - cur: 현재 누적 사용 시간
+//
+// Returns:
+//   - *CPUUsage: CPU 사용률
+func cpuUsage(prev, cur []sysstat.CPUTimes) *CPUUsage {
+	delta := func(i int) sysstat.CPUTimes {
+		d := cur[i]
+		if i >= len(prev) || prev[i].Name != cur[i].Name || prev[i].Total() > cur[i].Total() {
+			return d
+		}
+		p := prev[i]
+		d.User -= min(p.User, d.User)
+		d.Nice -= min(p.Nice, d.Nice)
+		d.System -= min(p.System, d.System)
+		d.Idle -= min(p.Idle, d.Idle)
+		d.IOWait -= min(p.IOWait, d.IOWait)
+		d.IRQ -= min(p.IRQ, d.IRQ)
+		d.SoftIRQ -= min(p.SoftIRQ, d.SoftIRQ)
+		d.Steal -= min(p.Steal, d.Steal)
+		return d
+	}
+
+	total := delta(0)
+	sum := total.Total()
+	usage := &CPUUsage{
+		Percent: sysstat.Percent(sum-total.Idle-total.IOWait, sum),
+		User:    sysstat.Percent(total.User+total.Nice, sum),
+		System:  sysstat.Percent(total.System+total.IRQ+total.SoftIRQ, sum),
+		IOWait:  sysstat.Percent(total.IOWait, sum),
+		Steal:   sysstat.Percent(total.Steal, sum),
+		Cores:   make([]float64, 0, len(cur)-1),
+	}
+	for i := 1; i < len(cur); i++ {
+		core := delta(i)
+		coreSum := core.Total()
+		usage.Cores = append(usage.Cores, sysstat.Percent(coreSum-core.Idle-core.IOWait, coreSum))
+	}
+	return usage
+}
+
+// rate 누적 값의 초당 변화량 계산
+//
+// Parameters:
+//   - prev: 이전 누적 값
+//   - cur: 현재 누적 값
+//   - elapsed: 경과 시간(초)
+//
+// Returns:
+//   - float64: 초당 변화량 (카운터가 초기화된 경우 0)
+func rate(prev, cur uint64, elapsed float64) float64 {
+	if cur < prev || elapsed <= 0 {
+		return 0
+	}
+	return sysstat.Round(float64(cur-prev) / elapsed)
+}
@@ -0,0 +1,400 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package terminal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/creack/pty"
+	"github.com/gorilla/websocket"
+	"github.com/hoon-kr/weblin/internal/auth"
+	"github.com/hoon-kr/weblin/internal/logger"
+	"github.com/hoon-kr/weblin/internal/privsep"
+	"github.com/hoon-kr/weblin/internal/session"
+)
+
+const (
+	// 클라이언트 응답(pong) 대기 시간
+	pongWait = 60 * time.Second
+	// 클라이언트 연결 확인(ping) 주기
+	pingInterval = pongWait * 9 / 10
+	// 메시지 쓰기 타임아웃
+	writeWait = 10 * time.Second
+	// 셸 프로세스 종료 대기 시간 (초과 시 SIGKILL)
+	killWait = 3 * time.Second
+	// PTY 읽기 버퍼 크기
+	readBufferSize = 32 * 1024
+	// 클라이언트 메시지 최대 크기
+	maxMessageSize = 64 * 1024
+)
+
+// 클라이언트 <-> 서버 메시지 타입
+const (
+	msgTypeInput  = "input"
+	msgTypeResize = "resize"
+	msgTypeExit   = "exit"
+)
+
+// message 클라이언트 제어 메시지 구조체 (WebSocket 텍스트 프레임)
+//
+// 바이너리 프레임은 가공되지 않은 키 입력으로 취급한다.
+type message struct {
+	Type string `json:"type"`
+	Data string `json:"data,omitempty"`
+	Cols uint16 `json:"cols,omitempty"`
+	Rows uint16 `json:"rows,omitempty"`
+}
+
+// exitMessage 셸 종료 알림 메시지 구조체
+type exitMessage struct {
+	Type string `json:"type"`
+	Code int    `json:"code"`
+}
+
+// Session 개별 웹 터미널 세션 정보 구조체
+type Session struct {
+	id    string
+	login *session.Session
+	// 세션 정보(요청 ID, 로그인 계정, 원격 주소, 터미널 ID) 필드를 추가한 로거
+	log     logger.Logger
+	conn    *websocket.Conn
+	cmd     *exec.Cmd
+	pty     *os.File
+	writeMu sync.Mutex
+	once    sync.Once
+}
+
+// newSession PTY를 할당하고 로그인 계정 권한으로 셸을 가동하여 세션 생성
+//
+// Parameters:
+//   - id: 세션 ID
+//   - login: 터미널을 요청한 로그인 세션
+//   - log: 세션 정보 필드를 추가한 로거
+//   - conn: WebSocket 연결
+//   - cols: 터미널 가로 크기
+//   - rows: 터미널 세로 크기
+//
+// Returns:
+//   - *Session
+//   - error: 성공(nil), 실패(error)
+func newSession(id string, login *session.Session, log logger.Logger, conn *websocket.Conn, cols, rows uint16) (*Session, error) {
+	cmd, err := newShellCommand(login.User)
+	if err != nil {
+		return nil, err
+	}
+
+	ptmx, err := startShell(cmd, login.User, cols, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Session{
+		id:    id,
+		login: login,
+		log:   log,
+		conn:  conn,
+		cmd:   cmd,
+		pty:   ptmx,
+	}, nil
+}
+
+// newShellCommand 로그인 계정 권한으로 실행할 로그인 셸 정보 생성
+//
+// Parameters:
+//   - u: 로그인 계정 정보
+//
+// Returns:
+//   - *exec.Cmd
+//   - error: 성공(nil), 실패(error)
+func newShellCommand(u *auth.User) (*exec.Cmd, error) {
+	attr, err := privsep.SysProcAttr(u)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare shell (user:%s): %s", u.Username, err)
+	}
+
+	shell := privsep.Shell(u)
+
+	// argv[0]을 "-"로 시작하도록 하여 로그인 셸로 실행
+	cmd := exec.Command(shell)
+	cmd.Args = []string{"-" + filepath.Base(shell)}
+	cmd.Dir = privsep.HomeDir(u)
+	cmd.Env = append(privsep.Environ(u), "TERM=xterm-256color")
+	cmd.SysProcAttr = attr
+
+	return cmd, nil
+}
+
+// startShell PTY를 할당하고 셸 가동 (Setsid, Setctty 설정)
+//
+// PTY 슬레이브 장치의 소유자를 로그인 계정으로 변경하여 셸이 자신의 터미널을 제어할 수 있도록 한다.
+//
+// Parameters:
+//   - cmd: 셸 실행 정보
+//   - u: 로그인 계정 정보
+//   - cols: 터미널 가로 크기
+//   - rows: 터미널 세로 크기
+//
+// Returns:
+//   - *os.File: PTY 마스터
+//   - error: 성공(nil), 실패(error)
+func startShell(cmd *exec.Cmd, u *auth.User, cols, rows uint16) (*os.File, error) {
+	ptmx, tty, err := pty.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pty: %s", err)
+	}
+	defer tty.Close()
+
+	if err := pty.Setsize(ptmx, &pty.Winsize{Cols: cols, Rows: rows}); err != nil {
+		ptmx.Close()
+		return nil, fmt.Errorf("failed to set pty size: %s", err)
+	}
+
+	if cmd.SysProcAttr.Credential != nil {
+		// login(1)과 동일하게 tty 그룹 쓰기 권한만 허용 (write, wall 메시지 수신)
+		gid := int(u.Gid)
+		if g, err := user.LookupGroup("tty"); err == nil {
+			if id, err := strconv.Atoi(g.Gid); err == nil {
+				gid = id
+			}
+		}
+		if err := tty.Chown(int(u.Uid), gid); err != nil {
+			ptmx.Close()
+			return nil, fmt.Errorf("failed to change pty owner: %s", err)
+		}
+		tty.Chmod(0o620)
+	}
+
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = tty, tty, tty
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+
+	if err := cmd.Start(); err != nil {
+		ptmx.Close()
+		return nil, fmt.Errorf("failed to start shell: %s", err)
+	}
+
+	return ptmx, nil
+}
+
+// run 세션 입출력 중계 (셸 종료, 클라이언트 연결 종료, 로그인 세션 만료, 컨텍스트 취소 시 반환)
+//
+// Parameters:
+//   - ctx: 세션 종료 컨텍스트
+func (s *Session) run(ctx context.Context) {
+	outputDone := make(chan struct{})
+	inputDone := make(chan struct{})
+
+	// PTY -> WebSocket
+	go func() {
+		defer close(outputDone)
+		s.pumpOutput()
+	}()
+	// WebSocket -> PTY
+	go func() {
+		defer close(inputDone)
+		s.pumpInput()
+	}()
+
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	closeCode, closeText := websocket.CloseNormalClosure, ""
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			closeCode, closeText = websocket.CloseGoingAway, "server is shutting down"
+			break loop
+		case <-s.login.Done():
+			closeCode, closeText = websocket.ClosePolicyViolation, "login session expired"
+			break loop
+		case <-outputDone:
+			// 셸 종료
+			break loop
+		case <-inputDone:
+			// 클라이언트 연결 종료
+			break loop
+		case <-ticker.C:
+			err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait))
+			if err != nil {
+				break loop
+			}
+		}
+	}
+
+	// 셸 프로세스 종료 후 종료 코드 전달
+	exitCode := s.close()
+	s.writeJSON(exitMessage{Type: msgTypeExit, Code: exitCode})
+	closeWithMessage(s.conn, closeCode, closeText)
+
+	<-outputDone
+	<-inputDone
+}
+
+// pumpOutput PTY 출력을 WebSocket 바이너리 프레임으로 전송
+func (s *Session) pumpOutput() {
+	buf := make([]byte, readBufferSize)
+	for {
+		n, err := s.pty.Read(buf)
+		if n > 0 {
+			if err := s.write(websocket.BinaryMessage, buf[:n]); err != nil {
+				return
+			}
+		}
+		if err != nil {
+			// 셸 종료 시 EIO 반환
+			return
+		}
+	}
+}
+
+// pumpInput WebSocket 메시지를 해석하여 PTY에 입력하거나 터미널 크기 변경
+func (s *Session) pumpInput() {
+	s.conn.SetReadLimit(maxMessageSize)
+	s.conn.SetReadDeadline(time.Now().Add(pongWait))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		msgType, data, err := s.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		// 터미널 사용 중에는 로그인 세션이 유휴 만료되지 않도록 갱신
+		s.login.Touch()
+
+		switch msgType {
+		case websocket.BinaryMessage:
+			if _, err := s.pty.Write(data); err != nil {
+				return
+			}
+		case websocket.TextMessage:
+			var msg message
+			if err := json.Unmarshal(data, &msg); err != nil {
+				s.log.Debug("Invalid terminal message", logger.Err(err))
+				continue
+			}
+			if err := s.handleMessage(&msg); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// handleMessage 클라이언트 제어 메시지 처리
+//
+// Parameters:
+//   - msg: 제어 메시지
+//
+// Returns:
+//   - error: 성공(nil), PTY 입출력 실패(error)
+func (s *Session) handleMessage(msg *message) error {
+	switch msg.Type {
+	case msgTypeInput:
+		if _, err := s.pty.Write([]byte(msg.Data)); err != nil {
+			return err
+		}
+	case msgTypeResize:
+		if msg.Cols == 0 || msg.Rows == 0 {
+			return nil
+		}
+		err := pty.Setsize(s.pty, &pty.Winsize{Cols: msg.Cols, Rows: msg.Rows})
+		if err != nil {
+			s.log.Warn("Failed to resize terminal", logger.Err(err))
+		}
+	default:
+		s.log.Debug("Unknown terminal message type", logger.String("type", msg.Type))
+	}
+	return nil
+}
+
+// write WebSocket 데이터 프레임 전송 (동시 쓰기 방지)
+//
+// Parameters:
+//   - msgType: 메시지 타입
+//   - data: 전송 데이터
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func (s *Session) write(msgType int, data []byte) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return s.conn.WriteMessage(msgType, data)
+}
+
+// writeJSON WebSocket JSON 텍스트 프레임 전송
+//
+// Parameters:
+//   - v: 전송 데이터
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func (s *Session) writeJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.write(websocket.TextMessage, data)
+}
+
+// close PTY를 닫고 셸 프로세스 종료
+//
+// PTY를 닫으면 커널이 세션에 SIGHUP을 전달하며, 일정 시간 내에 종료되지 않을 경우
+// 프로세스 그룹 전체에 SIGKILL을 전송한다.
+//
+// Returns:
+//   - int: 셸 종료 코드 (시그널에 의한 종료 시 -1)
+func (s *Session) close() int {
+	exitCode := -1
+	s.once.Do(func() {
+		s.pty.Close()
+
+		waitDone := make(chan struct{})
+		go func() {
+			defer close(waitDone)
+			s.cmd.Wait()
+		}()
+
+		pid := s.cmd.Process.Pid
+		select {
+		case <-waitDone:
+		case <-time.After(killWait):
+			// 셸은 세션 리더이므로 PID가 곧 프로세스 그룹 ID
+			syscall.Kill(-pid, syscall.SIGKILL)
+			<-waitDone
+		}
+
+		if s.cmd.ProcessState != nil {
+			exitCode = s.cmd.ProcessState.ExitCode()
+		}
+	})
+	return exitCode
+}
@@ -0,0 +1,199 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+/*
+Package terminal 웹 터미널(PTY over WebSocket) 처리 패키지
+*/
+package terminal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/hoon-kr/weblin/internal/audit"
+	"github.com/hoon-kr/weblin/internal/logger"
+	"github.com/hoon-kr/weblin/internal/session"
+	"github.com/hoon-kr/weblin/internal/web"
+	"github.com/hoon-kr/weblin/pkg/utils/goroutine"
+)
+
+const (
+	// 세션 고루틴 종료 대기 타임아웃
+	taskStopTimeout = 5 * time.Second
+	// 기본 터미널 크기
+	defaultCols = 80
+	defaultRows = 24
+)
+
+// Manager 웹 터미널 세션 관리 정보 구조체
+type Manager struct {
+	mu       sync.Mutex
+	gm       *goroutine.GoroutineManager
+	upgrader websocket.Upgrader
+	sessions map[string]*Session
+}
+
+// NewManager 웹 터미널 세션 관리 구조체 생성
+//
+// Parameters:
+//   - gm: 세션 고루틴을 등록할 고루틴 관리 구조체
+//
+// Returns:
+//   - *Manager
+func NewManager(gm *goroutine.GoroutineManager) *Manager {
+	return &Manager{
+		gm: gm,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  4096,
+			WriteBufferSize: 4096,
+		},
+		sessions: make(map[string]*Session),
+	}
+}
+
+// ServeHTTP WebSocket 연결을 수립하고 터미널 세션 생성 (로그인 세션 필요)
+//
+// 쿼리 파라미터 cols, rows로 초기 터미널 크기를 지정할 수 있다.
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+func (m *Manager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	login, ok := session.FromContext(r.Context())
+	if !ok {
+		web.WriteError(w, http.StatusUnauthorized, "login required")
+		return
+	}
+
+	cols := parseSize(r.URL.Query().Get("cols"), defaultCols)
+	rows := parseSize(r.URL.Query().Get("rows"), defaultRows)
+
+	// 요청 ID, 로그인 계정, 원격 주소 필드가 포함된 로거
+	log := logger.FromContext(r.Context())
+
+	// WebSocket 연결 수립 (실패 시 Upgrade() 내부에서 에러 응답 전송)
+	conn, err := m.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Warn("Failed to upgrade websocket", logger.Err(err))
+		return
+	}
+
+	id, err := newSessionID()
+	if err != nil {
+		log.Error("Failed to generate terminal session id", logger.Err(err))
+		closeWithMessage(conn, websocket.CloseInternalServerErr, "failed to create session")
+		return
+	}
+	log = log.With(logger.String(logger.KeyTerminalID, id))
+
+	// PTY 및 셸 프로세스 생성
+	term, err := newSession(id, login, log, conn, cols, rows)
+	if err != nil {
+		log.Error("Failed to start terminal session", logger.Err(err))
+		audit.Log.Record(audit.NewEntry(r, login.User.Username, audit.ActionTerminalStart, id), err)
+		closeWithMessage(conn, websocket.CloseInternalServerErr, "failed to start shell")
+		return
+	}
+
+	m.mu.Lock()
+	m.sessions[id] = term
+	m.mu.Unlock()
+
+	// 세션을 고루틴 관리 작업으로 등록하여 서버 종료 시 함께 정리되도록 함
+	taskName := "terminal-" + id
+	m.gm.AddTask(taskName, func(ctx context.Context) {
+		defer func() {
+			m.mu.Lock()
+			delete(m.sessions, id)
+			m.mu.Unlock()
+
+			// 작업 종료 후 관리 목록에서 제거 (자기 자신을 대기하지 않도록 별도 고루틴에서 수행)
+			go m.gm.RemoveTask(taskName, taskStopTimeout)
+		}()
+
+		log.Info("Terminal session started", logger.Int("pid", term.cmd.Process.Pid))
+		audit.Record(r, login.User.Username, audit.ActionTerminalStart, id, fmt.Sprintf("pid:%d", term.cmd.Process.Pid), nil)
+
+		term.run(ctx)
+
+		log.Info("Terminal session closed")
+		audit.Log.Record(audit.NewEntry(r, login.User.Username, audit.ActionTerminalStop, id), nil)
+	})
+	if err := m.gm.Start(taskName); err != nil {
+		log.Error("Failed to start terminal session task", logger.Err(err))
+		term.close()
+		closeWithMessage(conn, websocket.CloseInternalServerErr, "failed to start session")
+	}
+}
+
+// Count 현재 열려 있는 터미널 세션 개수 반환
+//
+// Returns:
+//   - int: 세션 개수
+func (m *Manager) Count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.sessions)
+}
+
+// newSessionID 임의의 세션 ID 생성
+//
+// Returns:
+//   - string: 세션 ID
+//   - error: 성공(nil), 실패(error)
+func newSessionID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// parseSize 터미널 크기 문자열을 정수로 변환
+//
+// Parameters:
+//   - value: 크기 문자열
+//   - def: 변환 실패 시 기본값
+//
+// Returns:
+//   - uint16: 터미널 크기
+func parseSize(value string, def uint16) uint16 {
+	size, err := strconv.ParseUint(value, 10, 16)
+	if err != nil || size == 0 {
+		return def
+	}
+	return uint16(size)
+}
+
+// closeWithMessage WebSocket 종료 메시지를 전송한 후 연결 종료
+//
+// Parameters:
+//   - conn: WebSocket 연결
+//   - code: 종료 코드
+//   - text: 종료 사유
+func closeWithMessage(conn *websocket.Conn, code int, text string) {
+	msg := websocket.FormatCloseMessage(code, text)
+	conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+	conn.Close()
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package web
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+
+	"github.com/hoon-kr/weblin/internal/logger"
+)
+
+// RequestIDHeader 요청 ID 헤더 이름 (프록시가 전달한 값을 사용하고 응답에도 포함)
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey 요청 컨텍스트의 요청 ID 키 타입
+type requestIDKey struct{}
+
+// validRequestID 프록시가 전달한 요청 ID로 허용하는 형식 (로그 위변조 방지)
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// withRequestLogger 요청 ID와 원격 주소 필드를 추가한 로거를 요청 컨텍스트에 저장하는 미들웨어
+//
+// 핸들러는 logger.FromContext(r.Context())로 요청 정보가 포함된 로그를 기록할 수 있다.
+//
+// Parameters:
+//   - next: 요청 핸들러
+//
+// Returns:
+//   - http.Handler
+func withRequestLogger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		log := logger.Log.With(
+			logger.String(logger.KeyRequestID, requestID),
+			logger.String(logger.KeyRemote, r.RemoteAddr),
+		)
+		ctx := context.WithValue(r.Context(), requestIDKey{}, requestID)
+		next.ServeHTTP(w, r.WithContext(logger.NewContext(ctx, log)))
+	})
+}
+
+// RequestID 요청 ID 반환
+//
+// Parameters:
+//   - r: 요청 정보
+//
+// Returns:
+//   - string: 요청 ID (웹 서버를 거치지 않은 요청일 경우 빈 문자열)
+func RequestID(r *http.Request) string {
+	requestID, _ := r.Context().Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// newRequestID 요청 ID 생성
+//
+// Returns:
+//   - string: 요청 ID (16자리 16진수)
+func newRequestID() string {
+	buf := make([]byte, 8)
+	// 난수 생성에 실패하더라도 요청은 처리 (요청 ID만 구분되지 않음)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,170 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package web
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hoon-kr/weblin/internal/metrics"
+)
+
+// 등록되지 않은 경로의 route 레이블 값
+const unmatchedRoute = "unmatched"
+
+var (
+	// httpRequests 경로, 메서드, 상태 코드별 요청 수
+	httpRequests = metrics.NewCounterVec("weblin_http_requests_total",
+		"Total number of HTTP requests by route, method and status code.", "route", "method", "code")
+	// httpDuration 경로, 메서드별 요청 처리 시간 (WebSocket 연결 제외)
+	httpDuration = metrics.NewHistogramVec("weblin_http_request_duration_seconds",
+		"HTTP request latency in seconds by route and method (WebSocket connections excluded).",
+		metrics.DefBuckets, "route", "method")
+	// httpReceivedBytes 경로별 수신한 요청 본문 크기 (업로드)
+	httpReceivedBytes = metrics.NewCounterVec("weblin_http_received_bytes_total",
+		"Total bytes of HTTP request bodies received (uploads) by route.", "route")
+	// httpSentBytes 경로별 전송한 응답 본문 크기 (다운로드)
+	httpSentBytes = metrics.NewCounterVec("weblin_http_sent_bytes_total",
+		"Total bytes of HTTP response bodies sent (downloads) by route.", "route")
+)
+
+// instrument 요청 수, 처리 시간, 송수신 크기를 기록하는 핸들러 반환
+//
+// route 레이블은 요청 경로 대신 등록된 경로 패턴을 사용하여 레이블 값의 수를 제한한다.
+//
+// Parameters:
+//   - mux: 요청 경로별 핸들러
+//
+// Returns:
+//   - http.Handler
+func instrument(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		_, route := mux.Handler(r)
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		body := &countingReader{ReadCloser: r.Body}
+		if r.Body != nil && r.Body != http.NoBody {
+			r.Body = body
+		}
+		rec := &responseRecorder{ResponseWriter: w}
+
+		defer func() {
+			httpReceivedBytes.Add(float64(body.n), route)
+			httpSentBytes.Add(float64(rec.n), route)
+
+			code := rec.status
+			switch {
+			case rec.hijacked:
+				code = http.StatusSwitchingProtocols
+			case code == 0:
+				code = http.StatusOK
+			}
+			httpRequests.Inc(route, r.Method, strconv.Itoa(code))
+			if !rec.hijacked {
+				httpDuration.Observe(time.Since(start).Seconds(), route, r.Method)
+			}
+		}()
+
+		mux.ServeHTTP(rec, r)
+	})
+}
+
+// countingReader 읽은 크기를 기록하는 요청 본문 구조체
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+// Read io.Reader 구현
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// responseRecorder 상태 코드와 전송 크기를 기록하는 응답 writer 구조체
+//
+// Flush, Hijack, ReadFrom(sendfile)은 원본 writer로 전달하며,
+// http.ResponseController는 Unwrap으로 원본 writer에 접근한다.
+type responseRecorder struct {
+	http.ResponseWriter
+	status   int
+	n        int64
+	hijacked bool
+}
+
+// WriteHeader http.ResponseWriter 구현
+func (rec *responseRecorder) WriteHeader(code int) {
+	if rec.status == 0 && code >= http.StatusOK {
+		rec.status = code
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+// Write http.ResponseWriter 구현
+func (rec *responseRecorder) Write(p []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	n, err := rec.ResponseWriter.Write(p)
+	rec.n += int64(n)
+	return n, err
+}
+
+// ReadFrom io.ReaderFrom 구현 (원본 writer의 sendfile 최적화 유지)
+func (rec *responseRecorder) ReadFrom(src io.Reader) (int64, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	n, err := io.Copy(rec.ResponseWriter, src)
+	rec.n += n
+	return n, err
+}
+
+// Flush http.Flusher 구현
+func (rec *responseRecorder) Flush() {
+	if flusher, ok := rec.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack http.Hijacker 구현 (WebSocket)
+func (rec *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := rec.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err == nil {
+		rec.hijacked = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap 원본 응답 writer 반환 (http.ResponseController 지원)
+func (rec *responseRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// 요청 JSON 본문 최대 크기
+const maxJSONBodySize = 1 << 20
+
+// ErrorResponse 에러 응답 구조체
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// WriteJSON JSON 응답 전송
+//
+// Parameters:
+//   - w: 응답 writer
+//   - status: HTTP 상태 코드
+//   - v: 응답 데이터
+func WriteJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// WriteError JSON 에러 응답 전송
+//
+// Parameters:
+//   - w: 응답 writer
+//   - status: HTTP 상태 코드
+//   - format: 에러 메시지
+//   - args: 가변 인자
+func WriteError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	WriteJSON(w, status, ErrorResponse{Error: fmt.Sprintf(format, args...)})
+}
+
+// ReadJSON 요청 JSON 본문을 구조체로 변환
+//
+// Parameters:
+//   - r: 요청 정보
+//   - v: 변환 결과를 저장할 구조체 포인터
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func ReadJSON(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(io.LimitReader(r.Body, maxJSONBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %s", err)
+	}
+	return nil
+}
+
+// AllowMethods 허용된 HTTP 메서드인지 확인하고, 아닐 경우 405 응답 전송
+//
+// Parameters:
+//   - w: 응답 writer
+//   - r: 요청 정보
+//   - methods: 허용 메서드 목록
+//
+// Returns:
+//   - bool: 허용(true), 비허용(false)
+func AllowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, method := range methods {
+		if r.Method == method {
+			return true
+		}
+	}
+
+	for i, method := range methods {
+		if i == 0 {
+			w.Header().Set("Allow", method)
+		} else {
+			w.Header().Add("Allow", method)
+		}
+	}
+	WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+	return false
+}
@@ -0,0 +1,348 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+/*
+Package web HTTP/HTTPS 웹 서버 패키지
+*/
+package web
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hoon-kr/weblin/config"
+	"github.com/hoon-kr/weblin/internal/logger"
+)
+
+// Server 웹 서버 관리 정보 구조체
+type Server struct {
+	mu         sync.Mutex
+	mux        *http.ServeMux
+	httpServer *http.Server
+	listener   net.Listener
+	errChan    chan error
+	// 소켓 활성화 등으로 전달받은 리스너 (설정된 경우 수신 주소 설정 대신 사용)
+	inherited net.Listener
+	// 가동 시 HTTPS 사용 여부 (설정 다시 읽기로 변경되더라도 재가동 전까지 유지)
+	tls bool
+	// HTTPS 인증서 (설정 다시 읽기 시 교체)
+	cert atomic.Pointer[tls.Certificate]
+}
+
+// NewServer 웹 서버 구조체 생성
+//
+// Returns:
+//   - *Server
+func NewServer() *Server {
+	return &Server{
+		mux:     http.NewServeMux(),
+		errChan: make(chan error, 1),
+	}
+}
+
+// Handle 요청 경로에 핸들러 등록
+//
+// Parameters:
+//   - pattern: 요청 경로 패턴
+//   - handler: 요청 핸들러
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// HandleFunc 요청 경로에 핸들러 함수 등록
+//
+// Parameters:
+//   - pattern: 요청 경로 패턴
+//   - handler: 요청 핸들러 함수
+func (s *Server) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	s.mux.HandleFunc(pattern, handler)
+}
+
+// SetListener 웹 서버 가동 시 사용할 리스너 설정 (systemd 소켓 활성화)
+//
+// 설정한 리스너는 수신 주소 설정(ListenAddress, ListenPort) 대신 사용하며, 다음 가동 시 한 번만 사용한다.
+//
+// Parameters:
+//   - listener: 이미 수신 대기 중인 리스너
+func (s *Server) SetListener(listener net.Listener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.inherited = listener
+}
+
+// Start 설정 정보를 기반으로 리스너를 생성하고 웹 서버 가동
+//
+// 리스너 생성(bind)까지는 동기적으로 수행하며, 요청 처리는 별도의 고루틴에서 수행한다.
+// SetListener로 설정한 리스너가 있을 경우 리스너를 생성하지 않고 사용한다.
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func (s *Server) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.httpServer != nil {
+		return fmt.Errorf("web server is already running")
+	}
+
+	conf := config.Current()
+
+	// 리스너 생성 (전달받은 리스너가 있을 경우 사용)
+	listener := s.inherited
+	s.inherited = nil
+	if listener == nil {
+		addr := net.JoinHostPort(conf.ListenAddress, strconv.Itoa(conf.ListenPort))
+		var err error
+		listener, err = net.Listen("tcp", addr)
+		if err != nil {
+			return fmt.Errorf("failed to listen (%s): %s", addr, err)
+		}
+	}
+
+	// ReadTimeout, WriteTimeout은 설정 다시 읽기를 반영할 수 있도록 요청마다 적용 (applyTimeouts)
+	httpServer := &http.Server{
+		Handler:           applyTimeouts(withRequestLogger(instrument(s.mux))),
+		ReadHeaderTimeout: time.Duration(conf.ReadHeaderTimeout) * time.Second,
+		IdleTimeout:       time.Duration(conf.IdleTimeout) * time.Second,
+		ErrorLog:          log.New(errorLogWriter{}, "", 0),
+	}
+
+	// HTTPS 사용 시 인증서를 로드하여 TLS 리스너로 감싸줌
+	s.tls = conf.EnableTLS
+	if conf.EnableTLS {
+		if err := s.loadCertificate(conf); err != nil {
+			listener.Close()
+			return err
+		}
+		httpServer.TLSConfig = &tls.Config{
+			// 설정 다시 읽기로 교체된 인증서를 신규 연결부터 사용
+			GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+				return s.cert.Load(), nil
+			},
+			MinVersion: tls.VersionTLS12,
+			NextProtos: []string{"h2", "http/1.1"},
+		}
+		listener = tls.NewListener(listener, httpServer.TLSConfig)
+	}
+
+	s.httpServer = httpServer
+	s.listener = listener
+
+	go func() {
+		err := httpServer.Serve(listener)
+
+		// Shutdown() 호출에 의한 종료가 아닐 경우 에러 전달
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.errChan <- fmt.Errorf("web server stopped unexpectedly: %s", err)
+		}
+	}()
+
+	logger.Log.LogInfo("Web server listening on %s://%s", s.scheme(), listener.Addr())
+
+	return nil
+}
+
+// Addresses 웹 서버 수신 주소 목록 반환
+//
+// Returns:
+//   - []string: 수신 주소 목록 (예: http://[::]:8080, 가동 전일 경우 nil)
+func (s *Server) Addresses() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener == nil {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s://%s", s.scheme(), s.listener.Addr())}
+}
+
+// Shutdown 처리 중인 요청이 완료될 때까지 대기한 후 웹 서버 정지
+//
+// Parameters:
+//   - timeout: 처리 중인 요청 대기 타임아웃
+//
+// Returns:
+//   - error: 성공(nil), 타임아웃 발생 또는 실패(error)
+func (s *Server) Shutdown(timeout time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.httpServer == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// 신규 연결 수신을 중단하고 처리 중인 요청이 완료될 때까지 대기
+	err := s.httpServer.Shutdown(ctx)
+	if err != nil {
+		// 타임아웃 발생 시 남아있는 연결 강제 종료
+		s.httpServer.Close()
+		err = fmt.Errorf("failed to shutdown web server gracefully (timeout: %.2fsec): %s",
+			timeout.Seconds(), err)
+	}
+
+	s.httpServer = nil
+	s.listener = nil
+
+	return err
+}
+
+// ApplyConfig 설정 다시 읽기 시 변경된 설정 적용 (config.Subscribe 등록용)
+//
+// 요청 타임아웃은 다음 요청부터 적용되며, HTTPS 인증서는 파일을 다시 읽어 신규 연결부터 사용한다.
+// 수신 주소, HTTPS 사용 여부 등 리스너와 관련된 설정은 서버를 재가동해야 적용된다.
+//
+// Parameters:
+//   - old: 이전 설정 정보
+//   - new: 새 설정 정보
+func (s *Server) ApplyConfig(old, new *config.Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.httpServer == nil {
+		return
+	}
+
+	// 인증서 갱신(같은 경로의 파일 교체)도 반영하기 위해 항상 다시 읽음
+	if s.tls && new.EnableTLS {
+		if err := s.loadCertificate(new); err != nil {
+			logger.Log.LogError("Keep previous certificate: %s", err)
+		}
+	}
+
+	var restart []string
+	for _, name := range config.Diff(old, new) {
+		switch name {
+		case "ListenAddress", "ListenPort", "EnableTLS", "ReadHeaderTimeout", "IdleTimeout":
+			restart = append(restart, name)
+		}
+	}
+	if len(restart) > 0 {
+		logger.Log.LogWarn("Web server settings %v take effect after restart", restart)
+	}
+}
+
+// loadCertificate HTTPS 인증서와 개인키를 읽어 사용할 인증서로 설정
+//
+// Parameters:
+//   - conf: 설정 정보
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func (s *Server) loadCertificate(conf *config.Config) error {
+	cert, err := tls.LoadX509KeyPair(conf.TLSCertFile, conf.TLSKeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load certificate: %s", err)
+	}
+	s.cert.Store(&cert)
+	return nil
+}
+
+// applyTimeouts 현재 설정의 요청 읽기, 응답 쓰기 타임아웃을 요청마다 적용하는 핸들러 반환
+//
+// http.Server의 ReadTimeout, WriteTimeout은 가동 중 변경할 수 없으므로 연결 deadline을 직접 설정한다.
+// 장시간 유지되는 응답(SSE 등)은 핸들러에서 deadline을 다시 해제할 수 있다.
+//
+// Parameters:
+//   - next: 요청 핸들러
+//
+// Returns:
+//   - http.Handler
+func applyTimeouts(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conf := config.Current()
+		rc := http.NewResponseController(w)
+		rc.SetReadDeadline(deadline(conf.ReadTimeout))
+		rc.SetWriteDeadline(deadline(conf.WriteTimeout))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// deadline 타임아웃(초)을 현재 시각 기준 deadline으로 변환
+//
+// Parameters:
+//   - timeout: 타임아웃(초)
+//
+// Returns:
+//   - time.Time: deadline (타임아웃이 0일 경우 zero value(무제한))
+func deadline(timeout int) time.Time {
+	if timeout <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(time.Duration(timeout) * time.Second)
+}
+
+// Err 웹 서버가 비정상적으로 종료될 경우 에러를 전달하는 채널 반환
+//
+// Returns:
+//   - <-chan error: error channel
+func (s *Server) Err() <-chan error {
+	return s.errChan
+}
+
+// Addr 웹 서버 수신 주소 반환
+//
+// Returns:
+//   - string: 수신 주소 (미동작 시 빈 문자열)
+func (s *Server) Addr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// scheme 웹 서버 URL 스킴 반환
+//
+// Returns:
+//   - string: http 또는 https
+func (s *Server) scheme() string {
+	if s.tls {
+		return "https"
+	}
+	return "http"
+}
+
+// errorLogWriter net/http 내부 에러 로그를 weblin 로거로 전달하는 구조체
+type errorLogWriter struct{}
+
+// Write net/http 에러 로그 기록
+//
+// Parameters:
+//   - p: 로그 메시지
+//
+// Returns:
+//   - int: 기록한 바이트 수
+//   - error: 항상 nil
+func (errorLogWriter) Write(p []byte) (int, error) {
+	logger.Log.LogWarn("%s", strings.TrimRight(string(p), "\n"))
+	return len(p), nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+/*
+Package archive 압축 파일(zip, tar.gz) 생성 및 해제 범용 패키지
+*/
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Format 압축 파일 형식
+type Format string
+
+// 지원 압축 파일 형식
+const (
+	FormatZip   Format = "zip"
+	FormatTarGz Format = "tar.gz"
+)
+
+var (
+	// ErrUnsupportedFormat 지원하지 않는 압축 파일 형식
+	ErrUnsupportedFormat = errors.New("unsupported archive format")
+	// ErrUnsafePath 압축 해제 경로를 벗어나는 항목 (zip-slip)
+	ErrUnsafePath = errors.New("archive entry escapes destination directory")
+	// ErrTooLarge 압축 해제 크기 또는 항목 수 제한 초과
+	ErrTooLarge = errors.New("archive exceeds extraction limit")
+)
+
+// Progress 압축 생성 및 해제 진행 상황 구조체
+type Progress struct {
+	// 처리한 항목 수
+	Entries int `json:"entries"`
+	// 처리한 데이터 크기 (압축 해제 기준)
+	Bytes int64 `json:"bytes"`
+	// 현재 처리 중인 항목
+	Current string `json:"current"`
+}
+
+// ParseFormat 형식 문자열을 압축 파일 형식으로 변환
+//
+// Parameters:
+//   - s: 형식 문자열 (zip, tar.gz, tgz)
+//
+// Returns:
+//   - Format: 압축 파일 형식
+//   - error: 성공(nil), 실패(ErrUnsupportedFormat)
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "zip":
+		return FormatZip, nil
+	case "tar.gz", "tgz":
+		return FormatTarGz, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, s)
+	}
+}
+
+// DetectFormat 파일 이름의 확장자로 압축 파일 형식 판별
+//
+// Parameters:
+//   - name: 파일 이름
+//
+// Returns:
+//   - Format: 압축 파일 형식
+//   - error: 성공(nil), 실패(ErrUnsupportedFormat)
+func DetectFormat(name string) (Format, error) {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return FormatZip, nil
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return FormatTarGz, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, name)
+	}
+}
+
+// Ext 압축 파일 형식의 확장자 반환
+//
+// Returns:
+//   - string: 확장자 (예: ".zip")
+func (f Format) Ext() string {
+	return "." + string(f)
+}
+
+// ContentType 압축 파일 형식의 MIME 타입 반환
+//
+// Returns:
+//   - string: MIME 타입
+func (f Format) ContentType() string {
+	if f == FormatZip {
+		return "application/zip"
+	}
+	return "application/gzip"
+}
+
+// cleanEntryName 압축 항목 이름을 검증하여 상대 경로로 정규화
+//
+// Parameters:
+//   - name: 압축 항목 이름
+//
+// Returns:
+//   - string: 정규화된 상대 경로 ("/" 구분자)
+//   - error: 성공(nil), 실패(ErrUnsafePath)
+func cleanEntryName(name string) (string, error) {
+	// Windows에서 생성된 zip 파일의 구분자 처리
+	name = strings.ReplaceAll(name, "\\", "/")
+	if name == "" || strings.HasPrefix(name, "/") || strings.ContainsRune(name, 0) {
+		return "", fmt.Errorf("%w: %q", ErrUnsafePath, name)
+	}
+
+	cleaned := path.Clean(name)
+	if cleaned == "." {
+		return "", nil
+	}
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("%w: %q", ErrUnsafePath, name)
+	}
+
+	return cleaned, nil
+}
@@ -0,0 +1,199 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// entryWriter 압축 형식별 항목 기록 인터페이스
+type entryWriter interface {
+	// writeEntry 항목 하나를 기록 (일반 파일은 r로 데이터 전달)
+	writeEntry(name string, fi fs.FileInfo, link string, r io.Reader) error
+	// Close 압축 스트림 종료
+	Close() error
+}
+
+// Create 지정한 경로들을 압축하여 w로 스트리밍 (임시 파일 미사용)
+//
+// 각 경로는 자신의 이름을 최상위 항목으로 하여 하위 항목과 함께 기록된다. 심볼릭 링크는
+// 링크 자체로 기록되며 장치 파일 등 특수 파일은 건너뛴다.
+//
+// Parameters:
+//   - ctx: 취소용 context
+//   - w: 압축 데이터 출력 writer
+//   - format: 압축 파일 형식
+//   - paths: 압축할 절대 경로 목록
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func Create(ctx context.Context, w io.Writer, format Format, paths []string) error {
+	var ew entryWriter
+	switch format {
+	case FormatZip:
+		ew = &zipWriter{zw: zip.NewWriter(w)}
+	case FormatTarGz:
+		gw := gzip.NewWriter(w)
+		ew = &tarWriter{gw: gw, tw: tar.NewWriter(gw)}
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+
+	for _, root := range paths {
+		base := filepath.Dir(root)
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			name, err := filepath.Rel(base, path)
+			if err != nil {
+				return err
+			}
+			return writePath(ew, filepath.ToSlash(name), path, d)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return ew.Close()
+}
+
+// writePath 파일 시스템 항목 하나를 압축 스트림에 기록
+//
+// Parameters:
+//   - ew: 항목 기록 writer
+//   - name: 압축 항목 이름
+//   - path: 파일 시스템 경로
+//   - d: 디렉터리 항목 정보
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func writePath(ew entryWriter, name, path string, d fs.DirEntry) error {
+	fi, err := d.Info()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case fi.Mode().IsRegular():
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return ew.writeEntry(name, fi, "", f)
+	case fi.IsDir():
+		return ew.writeEntry(name, fi, "", nil)
+	case fi.Mode()&fs.ModeSymlink != 0:
+		link, err := os.Readlink(path)
+		if err != nil {
+			return err
+		}
+		return ew.writeEntry(name, fi, link, nil)
+	default:
+		// 장치 파일, 소켓, 파이프는 압축 대상에서 제외
+		return nil
+	}
+}
+
+// zipWriter zip 형식 항목 기록
+type zipWriter struct {
+	zw *zip.Writer
+}
+
+// writeEntry zip 항목 기록
+func (z *zipWriter) writeEntry(name string, fi fs.FileInfo, link string, r io.Reader) error {
+	hdr, err := zip.FileInfoHeader(fi)
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if fi.IsDir() {
+		hdr.Name += "/"
+		hdr.Method = zip.Store
+	} else if fi.Mode().IsRegular() {
+		hdr.Method = zip.Deflate
+	}
+
+	ew, err := z.zw.CreateHeader(hdr)
+	if err != nil {
+		return err
+	}
+	switch {
+	case link != "":
+		// zip 형식은 심볼릭 링크 대상을 항목 데이터로 저장
+		_, err = io.WriteString(ew, link)
+	case r != nil:
+		_, err = io.Copy(ew, r)
+	}
+	return err
+}
+
+// Close zip 스트림 종료
+func (z *zipWriter) Close() error {
+	return z.zw.Close()
+}
+
+// tarWriter tar.gz 형식 항목 기록
+type tarWriter struct {
+	gw *gzip.Writer
+	tw *tar.Writer
+}
+
+// writeEntry tar 항목 기록
+func (t *tarWriter) writeEntry(name string, fi fs.FileInfo, link string, r io.Reader) error {
+	hdr, err := tar.FileInfoHeader(fi, link)
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if fi.IsDir() {
+		hdr.Name += "/"
+	}
+
+	if err := t.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if r != nil {
+		// 압축 도중 파일이 늘어난 경우 헤더에 기록된 크기까지만 기록
+		if _, err := io.CopyN(t.tw, r, hdr.Size); err != nil {
+			return fmt.Errorf("failed to archive %s: %s", name, err)
+		}
+	}
+	return nil
+}
+
+// Close tar.gz 스트림 종료
+func (t *tarWriter) Close() error {
+	if err := t.tw.Close(); err != nil {
+		return err
+	}
+	return t.gw.Close()
+}
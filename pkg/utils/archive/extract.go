@@ -0,0 +1,585 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// maxLinkFollows 심볼릭 링크 대상 확인 시 따라갈 수 있는 최대 링크 수 (Linux MAXSYMLINKS)
+const maxLinkFollows = 40
+
+// ExtractOptions 압축 해제 옵션 구조체
+type ExtractOptions struct {
+	// 압축 해제 데이터 총 크기 제한 (0이면 제한 없음)
+	MaxSize int64
+	// 항목 수 제한 (0이면 제한 없음)
+	MaxEntries int
+	// 기존 파일 덮어쓰기 여부
+	Overwrite bool
+	// 항목 처리 시마다 호출되는 함수 (nil 가능)
+	Progress func(Progress)
+}
+
+// entry 압축 형식과 무관한 항목 정보
+type entry struct {
+	name    string
+	mode    fs.FileMode
+	modTime time.Time
+	size    int64
+	link    string
+	// 하드 링크 여부 (link가 압축 내부 경로)
+	hardLink bool
+	open     func() (io.ReadCloser, error)
+}
+
+// extractor 압축 해제 상태
+type extractor struct {
+	ctx  context.Context
+	dst  string
+	opts ExtractOptions
+	p    Progress
+	// 모든 파일 기록 후 권한을 적용할 디렉터리
+	dirs []entry
+	// 모든 파일 기록 후 생성할 링크 (링크를 경유한 경로 탈출 방지)
+	links []entry
+}
+
+// Extract 압축 파일을 대상 디렉터리에 해제
+//
+// 대상 디렉터리를 벗어나는 항목(절대 경로, "..", 심볼릭 링크 경유)은 ErrUnsafePath로
+// 거부하며, 실제 기록한 데이터 크기와 항목 수가 제한을 넘으면 ErrTooLarge로 중단한다.
+// 링크는 모든 파일을 기록한 뒤 생성하므로 압축 내부의 링크를 통해 다른 위치에 기록할 수 없다.
+// 권한은 일반 권한 비트(0777)만 적용하고 setuid/setgid 비트는 제거한다.
+//
+// Parameters:
+//   - ctx: 취소용 context
+//   - src: 압축 파일 경로
+//   - format: 압축 파일 형식
+//   - dst: 압축을 해제할 디렉터리 (존재해야 함)
+//   - opts: 압축 해제 옵션
+//
+// Returns:
+//   - Progress: 최종 처리 결과
+//   - error: 성공(nil), 실패(error)
+func Extract(ctx context.Context, src string, format Format, dst string, opts ExtractOptions) (Progress, error) {
+	fi, err := os.Stat(dst)
+	if err != nil {
+		return Progress{}, err
+	}
+	if !fi.IsDir() {
+		return Progress{}, &os.PathError{Op: "extract", Path: dst, Err: syscall.ENOTDIR}
+	}
+
+	x := &extractor{ctx: ctx, dst: filepath.Clean(dst), opts: opts}
+	switch format {
+	case FormatZip:
+		err = x.extractZip(src)
+	case FormatTarGz:
+		err = x.extractTarGz(src)
+	default:
+		err = fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+	if err == nil {
+		err = x.finish()
+	}
+	return x.p, err
+}
+
+// extractZip zip 형식 압축 해제
+func (x *extractor) extractZip(src string) error {
+	zr, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	for _, zf := range zr.File {
+		zf := zf
+		e := entry{
+			name:    zf.Name,
+			mode:    zf.Mode(),
+			modTime: zf.Modified,
+			size:    int64(zf.UncompressedSize64),
+			open:    zf.Open,
+		}
+		if e.mode&fs.ModeSymlink != 0 {
+			link, err := readZipLink(zf)
+			if err != nil {
+				return err
+			}
+			e.link = link
+		}
+		if err := x.handle(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readZipLink zip 항목 데이터에 저장된 심볼릭 링크 대상 조회
+func readZipLink(zf *zip.File) (string, error) {
+	rc, err := zf.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	b, err := io.ReadAll(io.LimitReader(rc, 4096))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// extractTarGz tar.gz 형식 압축 해제
+func (x *extractor) extractTarGz(src string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		e := entry{
+			name:    hdr.Name,
+			mode:    hdr.FileInfo().Mode(),
+			modTime: hdr.ModTime,
+			size:    hdr.Size,
+			link:    hdr.Linkname,
+			open:    func() (io.ReadCloser, error) { return io.NopCloser(tr), nil },
+		}
+		switch hdr.Typeflag {
+		case tar.TypeLink:
+			e.hardLink = true
+		case tar.TypeXGlobalHeader:
+			continue
+		}
+		if err := x.handle(e); err != nil {
+			return err
+		}
+	}
+}
+
+// handle 항목 하나를 검증하고 해제
+func (x *extractor) handle(e entry) error {
+	if err := x.ctx.Err(); err != nil {
+		return err
+	}
+
+	name, err := cleanEntryName(e.name)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		return nil
+	}
+	e.name = name
+
+	x.p.Entries++
+	x.p.Current = name
+	if x.opts.MaxEntries > 0 && x.p.Entries > x.opts.MaxEntries {
+		return fmt.Errorf("%w: more than %d entries", ErrTooLarge, x.opts.MaxEntries)
+	}
+
+	target := filepath.Join(x.dst, filepath.FromSlash(name))
+	if err := x.checkParents(target); err != nil {
+		return err
+	}
+
+	switch {
+	case e.hardLink || e.mode&fs.ModeSymlink != 0:
+		x.links = append(x.links, e)
+	case e.mode.IsDir():
+		if err := x.makeDir(target); err != nil {
+			return err
+		}
+		x.dirs = append(x.dirs, e)
+	case e.mode.IsRegular():
+		if err := x.writeFile(target, e); err != nil {
+			return err
+		}
+	default:
+		// 장치 파일, 파이프 등은 해제하지 않음
+	}
+
+	if x.opts.Progress != nil {
+		x.opts.Progress(x.p)
+	}
+	return nil
+}
+
+// checkParents 대상 경로의 상위 디렉터리 중 심볼릭 링크가 있는지 검사하고 없으면 생성
+//
+// Parameters:
+//   - target: 해제할 경로
+//
+// Returns:
+//   - error: 성공(nil), 실패(ErrUnsafePath 등)
+func (x *extractor) checkParents(target string) error {
+	rel, err := filepath.Rel(x.dst, filepath.Dir(target))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		return fmt.Errorf("%w: %s", ErrUnsafePath, target)
+	}
+	if rel == "." {
+		return nil
+	}
+
+	cur := x.dst
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		cur = filepath.Join(cur, part)
+		fi, err := os.Lstat(cur)
+		if errors.Is(err, fs.ErrNotExist) {
+			if err := os.Mkdir(cur, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() {
+			// 심볼릭 링크 또는 파일을 경유하는 경로는 대상 디렉터리를 벗어날 수 있음
+			return fmt.Errorf("%w: %s", ErrUnsafePath, target)
+		}
+	}
+	return nil
+}
+
+// makeDir 디렉터리 항목 생성 (이미 존재하는 디렉터리는 그대로 사용)
+//
+// 하위 항목을 기록할 수 있도록 소유자 권한을 보장하여 생성하고 마지막에 원래 권한을 적용한다.
+// 기존 항목이 심볼릭 링크일 경우 링크 대상의 권한이 변경될 수 있으므로 거부한다.
+//
+// Parameters:
+//   - target: 디렉터리 경로
+//
+// Returns:
+//   - error: 성공(nil), 실패(ErrUnsafePath 등)
+func (x *extractor) makeDir(target string) error {
+	fi, err := os.Lstat(target)
+	if errors.Is(err, fs.ErrNotExist) {
+		return os.Mkdir(target, 0700)
+	}
+	if err != nil {
+		return err
+	}
+	if fi.Mode()&fs.ModeSymlink != 0 {
+		return fmt.Errorf("%w: %s", ErrUnsafePath, target)
+	}
+	if !fi.IsDir() {
+		return &os.PathError{Op: "extract", Path: target, Err: syscall.ENOTDIR}
+	}
+	return nil
+}
+
+// writeFile 일반 파일 해제 (크기 제한 적용)
+func (x *extractor) writeFile(target string, e entry) error {
+	if err := x.prepareTarget(target); err != nil {
+		return err
+	}
+	if x.opts.MaxSize > 0 && x.p.Bytes+e.size > x.opts.MaxSize {
+		return fmt.Errorf("%w: more than %d bytes", ErrTooLarge, x.opts.MaxSize)
+	}
+
+	rc, err := e.open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, e.mode.Perm())
+	if err != nil {
+		return err
+	}
+
+	// 헤더에 기록된 크기를 신뢰하지 않고 실제 기록 크기로 제한 검사
+	var r io.Reader = rc
+	if x.opts.MaxSize > 0 {
+		r = io.LimitReader(rc, x.opts.MaxSize-x.p.Bytes+1)
+	}
+	n, err := io.Copy(f, &contextReader{ctx: x.ctx, r: r})
+	x.p.Bytes += n
+	if err == nil && x.opts.MaxSize > 0 && x.p.Bytes > x.opts.MaxSize {
+		err = fmt.Errorf("%w: more than %d bytes", ErrTooLarge, x.opts.MaxSize)
+	}
+	// umask 영향을 받지 않도록 권한을 명시적으로 적용 (경로가 바뀌지 않도록 열린 파일에 적용)
+	if err == nil {
+		err = setModeTime(f, e)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(target)
+		return err
+	}
+	return nil
+}
+
+// setModeTime 열린 파일 또는 디렉터리에 항목의 권한과 수정 시각 적용
+//
+// Parameters:
+//   - f: 열린 파일
+//   - e: 압축 항목
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func setModeTime(f *os.File, e entry) error {
+	if err := f.Chmod(e.mode.Perm()); err != nil {
+		return err
+	}
+	tv := syscall.NsecToTimeval(e.modTime.UnixNano())
+	if err := syscall.Futimes(int(f.Fd()), []syscall.Timeval{tv, tv}); err != nil {
+		return &os.PathError{Op: "utimes", Path: f.Name(), Err: err}
+	}
+	return nil
+}
+
+// prepareTarget 기존 항목 처리 (덮어쓰기 허용 시 디렉터리가 아닌 항목 삭제)
+func (x *extractor) prepareTarget(target string) error {
+	fi, err := os.Lstat(target)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !x.opts.Overwrite || fi.IsDir() {
+		return &os.PathError{Op: "extract", Path: target, Err: fs.ErrExist}
+	}
+	return os.Remove(target)
+}
+
+// finish 지연된 링크 생성 및 디렉터리 권한 적용
+func (x *extractor) finish() error {
+	for _, e := range x.links {
+		if err := x.ctx.Err(); err != nil {
+			return err
+		}
+
+		target := filepath.Join(x.dst, filepath.FromSlash(e.name))
+		if err := x.checkParents(target); err != nil {
+			return err
+		}
+		if err := x.prepareTarget(target); err != nil {
+			return err
+		}
+
+		if e.hardLink {
+			// 하드 링크 대상은 압축 내부에 해제된 파일이어야 함
+			name, err := cleanEntryName(e.link)
+			if err != nil || name == "" {
+				return fmt.Errorf("%w: %q", ErrUnsafePath, e.link)
+			}
+			src := filepath.Join(x.dst, filepath.FromSlash(name))
+			if !x.isInside(src) {
+				return fmt.Errorf("%w: %q", ErrUnsafePath, e.link)
+			}
+			if fi, err := os.Lstat(src); err != nil || !fi.Mode().IsRegular() {
+				return fmt.Errorf("%w: %q", ErrUnsafePath, e.link)
+			}
+			if err := os.Link(src, target); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// 대상 디렉터리를 벗어나는 심볼릭 링크는 생성하지 않음
+		if !x.isLocalLink(e.name, e.link) {
+			return fmt.Errorf("%w: %s -> %s", ErrUnsafePath, e.name, e.link)
+		}
+		if err := os.Symlink(e.link, target); err != nil {
+			return err
+		}
+	}
+
+	// 나중에 생성한 링크를 경유하면 앞서 확인한 링크가 벗어날 수 있으므로 모든 링크 생성 후 다시 확인
+	for _, e := range x.links {
+		if e.hardLink || x.isLocalLink(e.name, e.link) {
+			continue
+		}
+		os.Remove(filepath.Join(x.dst, filepath.FromSlash(e.name)))
+		return fmt.Errorf("%w: %s -> %s", ErrUnsafePath, e.name, e.link)
+	}
+
+	// 하위 디렉터리부터 권한을 적용해야 상위 디렉터리 권한에 막히지 않음
+	for i := len(x.dirs) - 1; i >= 0; i-- {
+		if err := x.applyDir(x.dirs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyDir 디렉터리 항목의 권한과 수정 시각 적용
+//
+// 생성 이후 심볼릭 링크로 바뀐 경우 링크 대상에 적용되지 않도록 링크를 따라가지 않고 연다.
+//
+// Parameters:
+//   - e: 디렉터리 항목
+//
+// Returns:
+//   - error: 성공(nil), 실패(ErrUnsafePath 등)
+func (x *extractor) applyDir(e entry) error {
+	target := filepath.Join(x.dst, filepath.FromSlash(e.name))
+	f, err := os.OpenFile(target, os.O_RDONLY|syscall.O_DIRECTORY|syscall.O_NOFOLLOW, 0)
+	if errors.Is(err, syscall.ELOOP) || errors.Is(err, syscall.ENOTDIR) {
+		return fmt.Errorf("%w: %s", ErrUnsafePath, target)
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return setModeTime(f, e)
+}
+
+// isInside 이미 생성된 심볼릭 링크를 따라간 실제 경로가 압축 해제 디렉터리 내부인지 확인
+//
+// Parameters:
+//   - target: 확인할 경로
+//
+// Returns:
+//   - bool: 내부(true), 외부 또는 확인 실패(false)
+func (x *extractor) isInside(target string) bool {
+	root, err := filepath.EvalSymlinks(x.dst)
+	if err != nil {
+		return false
+	}
+	resolved, err := filepath.EvalSymlinks(target)
+	if err != nil {
+		return false
+	}
+	return resolved != root && strings.HasPrefix(resolved, root+string(filepath.Separator))
+}
+
+// isLocalLink 심볼릭 링크 대상이 압축 해제 디렉터리 내부를 가리키는지 확인
+//
+// 경로의 ".."는 앞서 생성된 심볼릭 링크를 따라간 실제 경로 기준으로 처리되므로, 경로 중간의
+// 심볼릭 링크를 직접 따라가며 한 단계씩 확인한다. 존재하지 않는 경로 항목은 이름 그대로 처리한다.
+//
+// Parameters:
+//   - name: 링크 항목 이름
+//   - link: 링크 대상 (상대 경로)
+//
+// Returns:
+//   - bool: 내부(true), 외부 또는 확인 실패(false)
+func (x *extractor) isLocalLink(name, link string) bool {
+	if path.IsAbs(link) {
+		return false
+	}
+
+	var cur []string
+	if dir := path.Dir(name); dir != "." {
+		cur = strings.Split(dir, "/")
+	}
+	// 처리할 경로 항목 (마지막 항목부터 꺼냄)
+	pending := reverse(strings.Split(link, "/"))
+	follows := 0
+	for len(pending) > 0 {
+		part := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			if len(cur) == 0 {
+				return false
+			}
+			cur = cur[:len(cur)-1]
+			continue
+		}
+
+		cur = append(cur, part)
+		linkPath := filepath.Join(x.dst, filepath.FromSlash(strings.Join(cur, "/")))
+		fi, err := os.Lstat(linkPath)
+		if err != nil || fi.Mode()&fs.ModeSymlink == 0 {
+			continue
+		}
+
+		// 심볼릭 링크는 대상 경로로 치환 (순환 링크 방지를 위해 커널과 같이 횟수 제한)
+		follows++
+		if follows > maxLinkFollows {
+			return false
+		}
+		target, err := os.Readlink(linkPath)
+		if err != nil || path.IsAbs(target) {
+			return false
+		}
+		cur = cur[:len(cur)-1]
+		pending = append(pending, reverse(strings.Split(target, "/"))...)
+	}
+	return true
+}
+
+// reverse 문자열 목록을 역순으로 반환
+//
+// Parameters:
+//   - parts: 문자열 목록
+//
+// Returns:
+//   - []string: 역순 목록
+func reverse(parts []string) []string {
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return parts
+}
+
+// contextReader 읽기마다 context 취소 여부를 확인하는 reader
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// Read context가 취소되지 않은 경우에만 데이터 읽기
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
@@ -0,0 +1,322 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testEntry 테스트용 압축 항목
+type testEntry struct {
+	name string
+	// tar 항목 종류 (0이면 일반 파일, 이름이 "/"로 끝나면 디렉터리)
+	typ  byte
+	body string
+	link string
+	mode int64
+}
+
+// writeTarGz 테스트용 tar.gz 파일 생성
+//
+// Parameters:
+//   - t: 테스트 정보
+//   - entries: 압축 항목 목록
+//
+// Returns:
+//   - string: 압축 파일 경로
+func writeTarGz(t *testing.T, entries []testEntry) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "test.tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Typeflag: e.typ, Linkname: e.link, Mode: e.mode, ModTime: time.Unix(1700000000, 0)}
+		switch {
+		case e.typ == 0 && strings.HasSuffix(e.name, "/"):
+			hdr.Typeflag = tar.TypeDir
+		case e.typ == 0:
+			hdr.Typeflag = tar.TypeReg
+			hdr.Size = int64(len(e.body))
+		}
+		if hdr.Mode == 0 {
+			hdr.Mode = 0o644
+			if hdr.Typeflag == tar.TypeDir {
+				hdr.Mode = 0o755
+			}
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			if _, err := tw.Write([]byte(e.body)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// extractDirs 압축 해제 대상 디렉터리와 그 바깥 디렉터리 생성
+//
+// Parameters:
+//   - t: 테스트 정보
+//
+// Returns:
+//   - string: 압축 해제 대상 디렉터리
+//   - string: 대상 디렉터리 바깥의 디렉터리 (침범 여부 확인용, 내용: secret 파일)
+func extractDirs(t *testing.T) (string, string) {
+	t.Helper()
+
+	root := t.TempDir()
+	dst := filepath.Join(root, "dst")
+	outside := filepath.Join(root, "outside")
+	for _, dir := range []string{dst, outside} {
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return dst, outside
+}
+
+// assertOutsideIntact 대상 디렉터리 바깥이 변경되지 않았는지 확인
+//
+// Parameters:
+//   - t: 테스트 정보
+//   - outside: 바깥 디렉터리
+func assertOutsideIntact(t *testing.T, outside string) {
+	t.Helper()
+
+	fi, err := os.Stat(outside)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0o755 {
+		t.Errorf("outside directory mode = %o, want 755", fi.Mode().Perm())
+	}
+	entries, err := os.ReadDir(outside)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "secret" {
+		t.Errorf("outside directory was modified: %v", entries)
+	}
+	fi, err = os.Stat(filepath.Join(outside, "secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0o600 {
+		t.Errorf("outside file mode = %o, want 600", fi.Mode().Perm())
+	}
+	data, err := os.ReadFile(filepath.Join(outside, "secret"))
+	if err != nil || string(data) != "secret" {
+		t.Errorf("outside file = %q, %v", data, err)
+	}
+}
+
+func TestExtractUnsafe(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []testEntry
+		// 압축 해제 전에 대상 디렉터리에 만들, 바깥 디렉터리를 가리키는 심볼릭 링크 이름
+		preLink string
+		wantErr error
+	}{
+		{"parent traversal", []testEntry{{name: "../evil", body: "x"}}, "", ErrUnsafePath},
+		{"nested parent traversal", []testEntry{{name: "a/../../evil", body: "x"}}, "", ErrUnsafePath},
+		{"absolute name", []testEntry{{name: "/tmp/evil", body: "x"}}, "", ErrUnsafePath},
+		{"absolute symlink", []testEntry{{name: "link", typ: tar.TypeSymlink, link: "/etc"}}, "", ErrUnsafePath},
+		{"escaping symlink", []testEntry{{name: "a/link", typ: tar.TypeSymlink, link: "../../outside"}}, "", ErrUnsafePath},
+		{"file through archive symlink", []testEntry{
+			{name: "link", typ: tar.TypeSymlink, link: "sub"},
+			{name: "sub/", typ: 0},
+			{name: "link/../../outside/evil", body: "x"},
+		}, "", ErrUnsafePath},
+		{"file through existing symlink", []testEntry{{name: "foo/evil", body: "x"}}, "foo", ErrUnsafePath},
+		{"directory through existing symlink", []testEntry{{name: "foo/", mode: 0o777}}, "foo", ErrUnsafePath},
+		{"subdirectory through existing symlink", []testEntry{{name: "foo/sub/", mode: 0o777}}, "foo", ErrUnsafePath},
+		{"symlink chain escaping through later link", []testEntry{
+			// 생성 시점에는 l2가 없어 l2/..는 대상 디렉터리이지만, l2 -> . 생성 후에는 상위 디렉터리
+			{name: "l1", typ: tar.TypeSymlink, link: "l2/.."},
+			{name: "l2", typ: tar.TypeSymlink, link: "."},
+		}, "", ErrUnsafePath},
+		{"hardlink outside", []testEntry{{name: "hard", typ: tar.TypeLink, link: "../outside/secret"}}, "", ErrUnsafePath},
+		{"hardlink absolute", []testEntry{{name: "hard", typ: tar.TypeLink, link: "/etc/passwd"}}, "", ErrUnsafePath},
+		{"hardlink through existing symlink", []testEntry{{name: "hard", typ: tar.TypeLink, link: "foo/secret"}}, "foo", ErrUnsafePath},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst, outside := extractDirs(t)
+			if tt.preLink != "" {
+				if err := os.Symlink(outside, filepath.Join(dst, tt.preLink)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			src := writeTarGz(t, tt.entries)
+			_, err := Extract(context.Background(), src, FormatTarGz, dst, ExtractOptions{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Extract() error = %v, want %v", err, tt.wantErr)
+			}
+			assertOutsideIntact(t, outside)
+		})
+	}
+}
+
+func TestExtractLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []testEntry
+		opts    ExtractOptions
+		wantErr error
+	}{
+		{"size within limit", []testEntry{{name: "a", body: "0123456789"}}, ExtractOptions{MaxSize: 10}, nil},
+		{"size over limit", []testEntry{{name: "a", body: "0123456789"}}, ExtractOptions{MaxSize: 9}, ErrTooLarge},
+		{"total size over limit", []testEntry{{name: "a", body: "01234"}, {name: "b", body: "56789"}}, ExtractOptions{MaxSize: 8}, ErrTooLarge},
+		{"entries within limit", []testEntry{{name: "d/"}, {name: "d/a", body: "a"}}, ExtractOptions{MaxEntries: 2}, nil},
+		{"entries over limit", []testEntry{{name: "d/"}, {name: "d/a", body: "a"}, {name: "d/b", body: "b"}}, ExtractOptions{MaxEntries: 2}, ErrTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst, _ := extractDirs(t)
+			src := writeTarGz(t, tt.entries)
+			_, err := Extract(context.Background(), src, FormatTarGz, dst, tt.opts)
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("Extract() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExtractTarGz(t *testing.T) {
+	dst, _ := extractDirs(t)
+	src := writeTarGz(t, []testEntry{
+		{name: "dir/", mode: 0o750},
+		{name: "dir/file", body: "hello", mode: 0o4755},
+		{name: "dir/link", typ: tar.TypeSymlink, link: "file"},
+		{name: "dir/sub/up", typ: tar.TypeSymlink, link: "../file"},
+		{name: "hard", typ: tar.TypeLink, link: "dir/file"},
+	})
+
+	p, err := Extract(context.Background(), src, FormatTarGz, dst, ExtractOptions{})
+	if err != nil {
+		t.Fatalf("Extract() error = %s", err)
+	}
+	if p.Entries != 5 || p.Bytes != 5 {
+		t.Errorf("Extract() progress = %+v, want 5 entries, 5 bytes", p)
+	}
+
+	for _, name := range []string{"dir/file", "dir/link", "dir/sub/up", "hard"} {
+		data, err := os.ReadFile(filepath.Join(dst, name))
+		if err != nil || string(data) != "hello" {
+			t.Errorf("%s = %q, %v", name, data, err)
+		}
+	}
+	// setuid 비트는 제거하고 권한과 수정 시각 적용
+	fi, err := os.Stat(filepath.Join(dst, "dir", "file"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode() != 0o755 || !fi.ModTime().Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("file mode = %s, mtime = %s", fi.Mode(), fi.ModTime())
+	}
+	fi, err = os.Stat(filepath.Join(dst, "dir"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0o750 || !fi.ModTime().Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("directory mode = %s, mtime = %s", fi.Mode(), fi.ModTime())
+	}
+}
+
+func TestExtractOverwrite(t *testing.T) {
+	dst, _ := extractDirs(t)
+	if err := os.WriteFile(filepath.Join(dst, "a"), []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	src := writeTarGz(t, []testEntry{{name: "a", body: "new"}})
+
+	if _, err := Extract(context.Background(), src, FormatTarGz, dst, ExtractOptions{}); !errors.Is(err, os.ErrExist) {
+		t.Fatalf("Extract() error = %v, want ErrExist", err)
+	}
+	if _, err := Extract(context.Background(), src, FormatTarGz, dst, ExtractOptions{Overwrite: true}); err != nil {
+		t.Fatalf("Extract(overwrite) error = %s", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dst, "a")); string(data) != "new" {
+		t.Errorf("a = %q, want new", data)
+	}
+}
+
+func TestExtractZip(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		wantErr error
+	}{
+		{"normal", "dir/a.txt", nil},
+		{"windows separator traversal", "..\\evil", ErrUnsafePath},
+		{"absolute name", "/evil", ErrUnsafePath},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst, outside := extractDirs(t)
+			src := filepath.Join(t.TempDir(), "test.zip")
+			f, err := os.Create(src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			zw := zip.NewWriter(f)
+			w, err := zw.Create(tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w.Write([]byte("data"))
+			zw.Close()
+			f.Close()
+
+			_, err = Extract(context.Background(), src, FormatZip, dst, ExtractOptions{})
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("Extract() error = %v, want %v", err, tt.wantErr)
+			}
+			assertOutsideIntact(t, outside)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+/*
+Package crypt crypt(3) 형식 비밀번호 해시 처리 범용 패키지
+
+지원 형식: MD5($1$), SHA-256($5$), SHA-512($6$), yescrypt($y$)
+*/
+package crypt
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strings"
+)
+
+// itoa64 crypt(3) 전용 base64 문자 집합
+const itoa64 = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+var (
+	// ErrUnsupportedHash 지원하지 않는 해시 형식
+	ErrUnsupportedHash = errors.New("unsupported hash format")
+	// ErrInvalidHash 잘못된 해시 문자열
+	ErrInvalidHash = errors.New("invalid hash format")
+)
+
+// Crypt 설정 문자열(해시 접두어 및 솔트)을 이용하여 비밀번호 해시 생성
+//
+// Parameters:
+//   - password: 평문 비밀번호
+//   - setting: 설정 문자열 (ex: $6$salt, $6$rounds=5000$salt$hash)
+//
+// Returns:
+//   - string: crypt(3) 형식 해시
+//   - error: 성공(nil), 실패(error)
+func Crypt(password, setting string) (string, error) {
+	switch {
+	case strings.HasPrefix(setting, md5Prefix):
+		return md5Crypt([]byte(password), setting)
+	case strings.HasPrefix(setting, sha256Prefix):
+		return shaCrypt(sha256Spec, []byte(password), setting)
+	case strings.HasPrefix(setting, sha512Prefix):
+		return shaCrypt(sha512Spec, []byte(password), setting)
+	case strings.HasPrefix(setting, yescryptPrefix):
+		return yescryptCrypt([]byte(password), setting)
+	}
+	return "", ErrUnsupportedHash
+}
+
+// Verify 평문 비밀번호가 crypt(3) 형식 해시와 일치하는지 확인
+//
+// Parameters:
+//   - password: 평문 비밀번호
+//   - hash: crypt(3) 형식 해시
+//
+// Returns:
+//   - bool: 일치(true), 불일치(false)
+//   - error: 성공(nil), 해시 형식 오류(error)
+func Verify(password, hash string) (bool, error) {
+	computed, err := Crypt(password, hash)
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(hash)) == 1, nil
+}
+
+// b64From24Bit 3바이트 값을 crypt(3) base64 문자로 변환 (MD5, SHA 계열)
+//
+// Parameters:
+//   - dst: 결과를 추가할 버퍼
+//   - b2, b1, b0: 상위 바이트부터 순서대로 입력할 바이트
+//   - n: 출력할 문자 개수
+//
+// Returns:
+//   - []byte: 결과가 추가된 버퍼
+func b64From24Bit(dst []byte, b2, b1, b0 byte, n int) []byte {
+	w := uint32(b2)<<16 | uint32(b1)<<8 | uint32(b0)
+	for ; n > 0; n-- {
+		dst = append(dst, itoa64[w&0x3f])
+		w >>= 6
+	}
+	return dst
+}
+
+// atoi64 crypt(3) base64 문자를 6비트 값으로 변환
+//
+// Parameters:
+//   - c: base64 문자
+//
+// Returns:
+//   - uint32: 6비트 값 (잘못된 문자일 경우 64 이상)
+func atoi64(c byte) uint32 {
+	if idx := strings.IndexByte(itoa64, c); idx >= 0 {
+		return uint32(idx)
+	}
+	return 64
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package crypt
+
+import (
+	"errors"
+	"testing"
+)
+
+// cryptTests 형식별 crypt(3) 테스트 벡터 (SHA-crypt 명세, yescrypt, glibc/libxcrypt 결과)
+var cryptTests = []struct {
+	name     string
+	password string
+	setting  string
+	want     string
+}{
+	// MD5 ($1$)
+	{"md5", "password", "$1$saltsalt",
+		"$1$saltsalt$qjXMvbEw8oaL.CzflDtaK/"},
+	{"md5 empty", "", "$1$",
+		"$1$$qRPK7m23GJusamGpoGLby/"},
+	{"md5 long password", "abcdefghijklmnopqrstuvwxyz0123456789", "$1$12345678",
+		"$1$12345678$c3ChqfAKLznFK.x2DUf4f1"},
+
+	// SHA-256 ($5$)
+	{"sha256", "Hello world!", "$5$saltstring",
+		"$5$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5"},
+	{"sha256 rounds", "Hello world!", "$5$rounds=10000$saltstringsaltstring",
+		"$5$rounds=10000$saltstringsaltst$3xv.VbSHBb41AL9AvLeujZkZRBAwqFMz2.opqey6IcA"},
+	{"sha256 long salt", "This is just a test", "$5$rounds=5000$toolongsaltstring",
+		"$5$rounds=5000$toolongsaltstrin$Un/5jzAHMgOGZ5.mWJpuVolil07guHPvOW8mGRcvxa5"},
+	{"sha256 long password", "a very much longer text to encrypt.  This one even stretches over morethan one line.",
+		"$5$rounds=1400$anotherlongsaltstring",
+		"$5$rounds=1400$anotherlongsalts$Rx.j8H.h8HjEDGomFU8bDkXm3XIUnzyxf12oP84Bnq1"},
+	{"sha256 short salt", "we have a short salt string but not a short password", "$5$rounds=77777$short",
+		"$5$rounds=77777$short$JiO1O3ZpDAxGJeaDIuqCoEFysAe1mZNJRs3pw0KQRd/"},
+	{"sha256 16 char salt", "a short string", "$5$rounds=123456$asaltof16chars..",
+		"$5$rounds=123456$asaltof16chars..$gP3VQ/6X7UUEW3HkBn2w1/Ptq2jxPyzV/cZKmF/wJvD"},
+	{"sha256 min rounds", "the minimum number is still observed", "$5$rounds=10$roundstoolow",
+		"$5$rounds=1000$roundstoolow$yfvwcWrQ8l/K0DAWyuPMDNHpIVlTQebY9l/gL972bIC"},
+
+	// SHA-512 ($6$)
+	{"sha512", "Hello world!", "$6$saltstring",
+		"$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1"},
+	{"sha512 rounds", "Hello world!", "$6$rounds=10000$saltstringsaltstring",
+		"$6$rounds=10000$saltstringsaltst$OW1/O6BYHV6BcXZu8QVeXbDWra3Oeqh0sbHbbMCVNSnCM/UrjmM0Dp8vOuZeHBy/YTBmSK6H9qs/y3RnOaw5v."},
+	{"sha512 long salt", "This is just a test", "$6$rounds=5000$toolongsaltstring",
+		"$6$rounds=5000$toolongsaltstrin$lQ8jolhgVRVhY4b5pZKaysCLi0QBxGoNeKQzQ3glMhwllF7oGDZxUhx1yxdYcz/e1JSbq3y6JMxxl8audkUEm0"},
+	{"sha512 long password", "a very much longer text to encrypt.  This one even stretches over morethan one line.",
+		"$6$rounds=1400$anotherlongsaltstring",
+		"$6$rounds=1400$anotherlongsalts$POfYwTEok97VWcjxIiSOjiykti.o/pQs.wPvMxQ6Fm7I6IoYN3CmLs66x9t0oSwbtEW7o7UmJEiDwGqd8p4ur1"},
+	{"sha512 short salt", "we have a short salt string but not a short password", "$6$rounds=77777$short",
+		"$6$rounds=77777$short$WuQyW2YR.hBNpjjRhpYD/ifIw05xdfeEyQoMxIXbkvr0gge1a1x3yRULJ5CCaUeOxFmtlcGZelFl5CxtgfiAc0"},
+	{"sha512 16 char salt", "a short string", "$6$rounds=123456$asaltof16chars..",
+		"$6$rounds=123456$asaltof16chars..$BtCwjqMJGx5hrJhZywWvt0RLE8uZ4oPwcelCjmw2kSYu.Ec6ycULevoBK25fs2xXgMNrCzIMVcgEJAstJeonj1"},
+	{"sha512 min rounds", "the minimum number is still observed", "$6$rounds=10$roundstoolow",
+		"$6$rounds=1000$roundstoolow$kUMsbe306n21p9R.FRkW3IGn.S9NPN0x50YhH1xhLsPuWGsUSklZt58jaTfF4ZEQpyUNGc0dqbpBYYBaHHrsX."},
+
+	// yescrypt ($y$)
+	{"yescrypt", "pleaseletmein", "$y$jD5.7$LdJMENpBABJJ3hIHjB1Bi.",
+		"$y$jD5.7$LdJMENpBABJJ3hIHjB1Bi.$HboGM6qPrsK.StKYGt6KErmUYtioHreJd98oIugoNB6"},
+	{"yescrypt default cost", "password", "$y$j9T$F5Jx5fExrKuPp53xLKQ..1",
+		"$y$j9T$F5Jx5fExrKuPp53xLKQ..1$tnSYvahCwPBHKZUspmcxMfb0.WiB9W.zEaKlOBL35rC"},
+	{"yescrypt empty salt", "", "$y$j9T$",
+		"$y$j9T$$EBiO75E.nlsNSY6EaCJLzant.b1uUsOgoDdj78FKfO7"},
+	{"yescrypt higher cost", "Hello world!", "$y$jCT$abcdefghijklmnop",
+		"$y$jCT$abcdefghijklmnop$7/fh862AS9jTYpLwBWByC/FhLK76Dk.8hAVExgtYYPB"},
+}
+
+func TestCrypt(t *testing.T) {
+	for _, tt := range cryptTests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Crypt(tt.password, tt.setting)
+			if err != nil {
+				t.Fatalf("Crypt() error = %s", err)
+			}
+			if got != tt.want {
+				t.Errorf("Crypt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	for _, tt := range cryptTests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := Verify(tt.password, tt.want)
+			if err != nil || !ok {
+				t.Errorf("Verify(correct) = %v, %v, want true, nil", ok, err)
+			}
+			ok, err = Verify(tt.password+"x", tt.want)
+			if err != nil || ok {
+				t.Errorf("Verify(wrong) = %v, %v, want false, nil", ok, err)
+			}
+		})
+	}
+}
+
+func TestCryptInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		setting string
+		wantErr error
+	}{
+		{"des", "abJnggxhB/yWI", ErrUnsupportedHash},
+		{"bcrypt", "$2b$10$abcdefghijklmnopqrstuu", ErrUnsupportedHash},
+		{"empty", "", ErrUnsupportedHash},
+		{"locked", "!$6$saltstring", ErrUnsupportedHash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Crypt("password", tt.setting); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Crypt() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// 잘못된 yescrypt 매개변수
+	for _, setting := range []string{"$y$", "$y$!!!$salt", "$y$j$salt"} {
+		if _, err := Crypt("password", setting); err == nil {
+			t.Errorf("Crypt(%q) error = nil, want error", setting)
+		}
+	}
+}
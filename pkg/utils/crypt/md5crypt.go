@@ -0,0 +1,104 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package crypt
+
+import (
+	"crypto/md5"
+	"strings"
+)
+
+const (
+	md5Prefix     = "$1$"
+	md5MaxSaltLen = 8
+)
+
+// md5Crypt MD5 기반 crypt 해시 생성 (Poul-Henning Kamp 알고리즘)
+//
+// Parameters:
+//   - password: 평문 비밀번호
+//   - setting: 설정 문자열 ($1$salt)
+//
+// Returns:
+//   - string: crypt(3) 형식 해시
+//   - error: 성공(nil), 실패(error)
+func md5Crypt(password []byte, setting string) (string, error) {
+	salt := strings.TrimPrefix(setting, md5Prefix)
+	if idx := strings.IndexByte(salt, '$'); idx >= 0 {
+		salt = salt[:idx]
+	}
+	if len(salt) > md5MaxSaltLen {
+		salt = salt[:md5MaxSaltLen]
+	}
+
+	// final <- MD5(password + salt + password)
+	alt := md5.New()
+	alt.Write(password)
+	alt.Write([]byte(salt))
+	alt.Write(password)
+	final := alt.Sum(nil)
+
+	ctx := md5.New()
+	ctx.Write(password)
+	ctx.Write([]byte(md5Prefix))
+	ctx.Write([]byte(salt))
+	for pl := len(password); pl > 0; pl -= md5.Size {
+		ctx.Write(final[:min(pl, md5.Size)])
+	}
+	for i := len(password); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			ctx.Write([]byte{0})
+		} else {
+			ctx.Write(password[:1])
+		}
+	}
+	final = ctx.Sum(nil)
+
+	// 1000회 반복
+	for i := 0; i < 1000; i++ {
+		round := md5.New()
+		if i&1 != 0 {
+			round.Write(password)
+		} else {
+			round.Write(final)
+		}
+		if i%3 != 0 {
+			round.Write([]byte(salt))
+		}
+		if i%7 != 0 {
+			round.Write(password)
+		}
+		if i&1 != 0 {
+			round.Write(final)
+		} else {
+			round.Write(password)
+		}
+		final = round.Sum(nil)
+	}
+
+	out := make([]byte, 0, len(md5Prefix)+len(salt)+1+22)
+	out = append(out, md5Prefix...)
+	out = append(out, salt...)
+	out = append(out, '$')
+	out = b64From24Bit(out, final[0], final[6], final[12], 4)
+	out = b64From24Bit(out, final[1], final[7], final[13], 4)
+	out = b64From24Bit(out, final[2], final[8], final[14], 4)
+	out = b64From24Bit(out, final[3], final[9], final[15], 4)
+	out = b64From24Bit(out, final[4], final[10], final[5], 4)
+	out = b64From24Bit(out, 0, 0, final[11], 2)
+
+	return string(out), nil
+}
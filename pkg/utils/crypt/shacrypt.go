@@ -0,0 +1,211 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package crypt
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"strconv"
+	"strings"
+)
+
+const (
+	sha256Prefix     = "$5$"
+	sha512Prefix     = "$6$"
+	shaRoundsPrefix  = "rounds="
+	shaMaxSaltLen    = 16
+	shaDefaultRounds = 5000
+	shaMinRounds     = 1000
+	shaMaxRounds     = 999999999
+)
+
+// shaCryptSpec SHA 계열 crypt 알고리즘별 정보 구조체
+type shaCryptSpec struct {
+	prefix  string
+	newHash func() hash.Hash
+	// 결과 인코딩 시 3바이트 단위 출력 순서 (상위 바이트부터)
+	order [][3]int
+	// 마지막 3바이트 출력 시 사용할 바이트 인덱스 (-1: 0으로 채움) 및 출력 문자 개수
+	tail    []int
+	tailLen int
+}
+
+var sha256Spec = &shaCryptSpec{
+	prefix:  sha256Prefix,
+	newHash: sha256.New,
+	order: [][3]int{
+		{0, 10, 20}, {21, 1, 11}, {12, 22, 2}, {3, 13, 23}, {24, 4, 14},
+		{15, 25, 5}, {6, 16, 26}, {27, 7, 17}, {18, 28, 8}, {9, 19, 29},
+	},
+	tail:    []int{-1, 31, 30},
+	tailLen: 3,
+}
+
+var sha512Spec = &shaCryptSpec{
+	prefix:  sha512Prefix,
+	newHash: sha512.New,
+	order: [][3]int{
+		{0, 21, 42}, {22, 43, 1}, {44, 2, 23}, {3, 24, 45}, {25, 46, 4},
+		{47, 5, 26}, {6, 27, 48}, {28, 49, 7}, {50, 8, 29}, {9, 30, 51},
+		{31, 52, 10}, {53, 11, 32}, {12, 33, 54}, {34, 55, 13}, {56, 14, 35},
+		{15, 36, 57}, {37, 58, 16}, {59, 17, 38}, {18, 39, 60}, {40, 61, 19},
+		{62, 20, 41},
+	},
+	tail:    []int{-1, -1, 63},
+	tailLen: 2,
+}
+
+// shaCrypt SHA-256/SHA-512 기반 crypt 해시 생성 (Ulrich Drepper 알고리즘)
+//
+// Parameters:
+//   - spec: 알고리즘 정보
+//   - password: 평문 비밀번호
+//   - setting: 설정 문자열 ($5$[rounds=N$]salt, $6$[rounds=N$]salt)
+//
+// Returns:
+//   - string: crypt(3) 형식 해시
+//   - error: 성공(nil), 실패(error)
+func shaCrypt(spec *shaCryptSpec, password []byte, setting string) (string, error) {
+	rest := strings.TrimPrefix(setting, spec.prefix)
+
+	// 반복 횟수 파싱 (명시된 경우 결과 문자열에도 포함)
+	rounds := shaDefaultRounds
+	customRounds := false
+	if strings.HasPrefix(rest, shaRoundsPrefix) {
+		idx := strings.IndexByte(rest, '$')
+		if idx < 0 {
+			return "", ErrInvalidHash
+		}
+		value, err := strconv.ParseUint(rest[len(shaRoundsPrefix):idx], 10, 64)
+		if err != nil {
+			return "", ErrInvalidHash
+		}
+		rounds = int(max(min(value, shaMaxRounds), shaMinRounds))
+		customRounds = true
+		rest = rest[idx+1:]
+	}
+
+	salt := rest
+	if idx := strings.IndexByte(salt, '$'); idx >= 0 {
+		salt = salt[:idx]
+	}
+	if len(salt) > shaMaxSaltLen {
+		salt = salt[:shaMaxSaltLen]
+	}
+	saltBytes := []byte(salt)
+
+	// B <- H(password + salt + password)
+	h := spec.newHash()
+	h.Write(password)
+	h.Write(saltBytes)
+	h.Write(password)
+	altResult := h.Sum(nil)
+	size := len(altResult)
+
+	// A <- H(password + salt + B(비밀번호 길이만큼) + 비밀번호 길이의 비트별 B 또는 password)
+	h = spec.newHash()
+	h.Write(password)
+	h.Write(saltBytes)
+	cnt := len(password)
+	for ; cnt > size; cnt -= size {
+		h.Write(altResult)
+	}
+	h.Write(altResult[:cnt])
+	for cnt = len(password); cnt > 0; cnt >>= 1 {
+		if cnt&1 != 0 {
+			h.Write(altResult)
+		} else {
+			h.Write(password)
+		}
+	}
+	altResult = h.Sum(nil)
+
+	// P 바이트열 생성
+	h = spec.newHash()
+	for i := 0; i < len(password); i++ {
+		h.Write(password)
+	}
+	pBytes := repeatToLen(h.Sum(nil), len(password))
+
+	// S 바이트열 생성
+	h = spec.newHash()
+	for i := 0; i < 16+int(altResult[0]); i++ {
+		h.Write(saltBytes)
+	}
+	sBytes := repeatToLen(h.Sum(nil), len(saltBytes))
+
+	// 반복 연산
+	for i := 0; i < rounds; i++ {
+		h = spec.newHash()
+		if i&1 != 0 {
+			h.Write(pBytes)
+		} else {
+			h.Write(altResult)
+		}
+		if i%3 != 0 {
+			h.Write(sBytes)
+		}
+		if i%7 != 0 {
+			h.Write(pBytes)
+		}
+		if i&1 != 0 {
+			h.Write(altResult)
+		} else {
+			h.Write(pBytes)
+		}
+		altResult = h.Sum(altResult[:0])
+	}
+
+	out := make([]byte, 0, 128)
+	out = append(out, spec.prefix...)
+	if customRounds {
+		out = append(out, shaRoundsPrefix...)
+		out = strconv.AppendInt(out, int64(rounds), 10)
+		out = append(out, '$')
+	}
+	out = append(out, salt...)
+	out = append(out, '$')
+	for _, o := range spec.order {
+		out = b64From24Bit(out, altResult[o[0]], altResult[o[1]], altResult[o[2]], 4)
+	}
+	tail := [3]byte{}
+	for i, idx := range spec.tail {
+		if idx >= 0 {
+			tail[i] = altResult[idx]
+		}
+	}
+	out = b64From24Bit(out, tail[0], tail[1], tail[2], spec.tailLen)
+
+	return string(out), nil
+}
+
+// repeatToLen 바이트열을 반복하여 지정한 길이의 바이트열 생성
+//
+// Parameters:
+//   - src: 원본 바이트열
+//   - n: 생성할 길이
+//
+// Returns:
+//   - []byte: 생성된 바이트열
+func repeatToLen(src []byte, n int) []byte {
+	dst := make([]byte, n)
+	for i := 0; i < n; i += len(src) {
+		copy(dst[i:], src)
+	}
+	return dst
+}
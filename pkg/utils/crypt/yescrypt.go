@@ -0,0 +1,681 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package crypt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"math/bits"
+	"strings"
+)
+
+const yescryptPrefix = "$y$"
+
+// yescrypt 플래그 (libxcrypt와 동일한 값)
+const (
+	yescryptRW          = 0x002
+	yescryptRounds6     = 0x004
+	yescryptGather4     = 0x010
+	yescryptSimple2     = 0x020
+	yescryptSbox12K     = 0x080
+	yescryptFlavorMask  = 0x3fc
+	yescryptPrehash     = 0x10000000
+	yescryptDefaultFlag = yescryptRW | yescryptRounds6 | yescryptGather4 |
+		yescryptSimple2 | yescryptSbox12K
+)
+
+// pwxform 파라미터 (yescryptDefaultFlag에 대응)
+const (
+	pwxSimple = 2
+	pwxGather = 4
+	pwxRounds = 6
+	sWidth    = 8
+	pwxBytes  = pwxGather * pwxSimple * 8
+	pwxWords  = pwxBytes / 4
+	sBytes    = 3 * (1 << sWidth) * pwxSimple * 8
+	sWords    = sBytes / 4
+	sMask     = ((1 << sWidth) - 1) * pwxSimple * 8
+	// S-box 하나의 크기 (64비트 단위)
+	sboxLanes = (1 << sWidth) * pwxSimple
+)
+
+// yescryptParams yescrypt 연산 파라미터 구조체
+type yescryptParams struct {
+	flags uint32
+	n     uint64
+	r     uint32
+	p     uint32
+	t     uint32
+}
+
+// pwxformCtx pwxform 연산 상태 구조체 (S-box는 64비트 lane 단위로 인덱싱)
+type pwxformCtx struct {
+	s0, s1, s2 []uint32
+	w          int
+}
+
+// yescryptCrypt yescrypt 기반 crypt 해시 생성
+//
+// libxcrypt 기본 설정(YESCRYPT_DEFAULTS) 형식만 지원하며, ROM 및 해시 업그레이드(g)는 지원하지 않는다.
+//
+// Parameters:
+//   - password: 평문 비밀번호
+//   - setting: 설정 문자열 ($y$params$salt)
+//
+// Returns:
+//   - string: crypt(3) 형식 해시
+//   - error: 성공(nil), 실패(error)
+func yescryptCrypt(password []byte, setting string) (string, error) {
+	params, prefixLen, err := decodeYescryptParams(setting)
+	if err != nil {
+		return "", err
+	}
+
+	// 솔트 문자열 추출 및 디코딩
+	saltStr := setting[prefixLen:]
+	if idx := strings.LastIndexByte(saltStr, '$'); idx >= 0 {
+		saltStr = saltStr[:idx]
+	}
+	salt, ok := decode64([]byte(saltStr))
+	if !ok {
+		return "", ErrInvalidHash
+	}
+
+	hash, err := yescryptKDF(password, salt, params)
+	if err != nil {
+		return "", err
+	}
+
+	out := make([]byte, 0, prefixLen+len(saltStr)+1+43)
+	out = append(out, setting[:prefixLen]...)
+	out = append(out, saltStr...)
+	out = append(out, '$')
+	out = encode64(out, hash)
+
+	return string(out), nil
+}
+
+// decodeYescryptParams yescrypt 설정 문자열에서 파라미터 디코딩
+//
+// Parameters:
+//   - setting: 설정 문자열
+//
+// Returns:
+//   - *yescryptParams: 연산 파라미터
+//   - int: 솔트 시작 위치
+//   - error: 성공(nil), 실패(error)
+func decodeYescryptParams(setting string) (*yescryptParams, int, error) {
+	src := []byte(setting[len(yescryptPrefix):])
+	params := &yescryptParams{p: 1}
+
+	flavor, src, ok := decode64Uint32(src, 0)
+	if !ok {
+		return nil, 0, ErrInvalidHash
+	}
+	switch {
+	case flavor < yescryptRW:
+		params.flags = flavor
+	case flavor <= yescryptRW+(yescryptFlavorMask>>2):
+		params.flags = yescryptRW + ((flavor - yescryptRW) << 2)
+	default:
+		return nil, 0, ErrInvalidHash
+	}
+
+	nLog2, src, ok := decode64Uint32(src, 1)
+	if !ok || nLog2 > 63 {
+		return nil, 0, ErrInvalidHash
+	}
+	params.n = uint64(1) << nLog2
+
+	if params.r, src, ok = decode64Uint32(src, 1); !ok {
+		return nil, 0, ErrInvalidHash
+	}
+
+	if len(src) > 0 && src[0] != '$' {
+		var have uint32
+		if have, src, ok = decode64Uint32(src, 1); !ok {
+			return nil, 0, ErrInvalidHash
+		}
+		if have&1 != 0 {
+			if params.p, src, ok = decode64Uint32(src, 2); !ok {
+				return nil, 0, ErrInvalidHash
+			}
+		}
+		if have&2 != 0 {
+			if params.t, src, ok = decode64Uint32(src, 1); !ok {
+				return nil, 0, ErrInvalidHash
+			}
+		}
+		// 해시 업그레이드(g) 및 ROM 사용 형식은 미지원
+		if have&^3 != 0 {
+			return nil, 0, ErrUnsupportedHash
+		}
+	}
+
+	if len(src) == 0 || src[0] != '$' {
+		return nil, 0, ErrInvalidHash
+	}
+
+	return params, len(setting) - len(src) + 1, nil
+}
+
+// yescryptKDF yescrypt 키 유도 함수
+//
+// Parameters:
+//   - password: 평문 비밀번호
+//   - salt: 솔트
+//   - params: 연산 파라미터
+//
+// Returns:
+//   - []byte: 32바이트 해시
+//   - error: 성공(nil), 실패(error)
+func yescryptKDF(password, salt []byte, params *yescryptParams) ([]byte, error) {
+	if params.flags != yescryptDefaultFlag {
+		return nil, ErrUnsupportedHash
+	}
+	n, r, p := params.n, uint64(params.r), uint64(params.p)
+	if n < 2 || n&(n-1) != 0 || r == 0 || p == 0 || n > 1<<32 ||
+		r*p >= 1<<30 || n*r > 1<<32 {
+		return nil, ErrInvalidHash
+	}
+
+	// 메모리 사용량이 큰 경우 축소된 N으로 비밀번호를 사전 해시
+	if n/p >= 0x100 && n/p*r >= 0x20000 {
+		prehashed := yescryptKDFBody(password, salt, params.flags|yescryptPrehash,
+			n>>6, params.r, params.p, 0)
+		password = prehashed
+	}
+
+	return yescryptKDFBody(password, salt, params.flags, n, params.r, params.p, params.t), nil
+}
+
+// yescryptKDFBody yescrypt 키 유도 함수 본체
+//
+// Parameters:
+//   - password: 평문 비밀번호
+//   - salt: 솔트
+//   - flags: yescrypt 플래그
+//   - n, r, p, t: 연산 파라미터
+//
+// Returns:
+//   - []byte: 32바이트 해시
+func yescryptKDFBody(password, salt []byte, flags uint32, n uint64, r, p, t uint32) []byte {
+	key := "yescrypt"
+	if flags&yescryptPrehash != 0 {
+		key = "yescrypt-prehash"
+	}
+	passwd := hmacSHA256([]byte(key), password)
+
+	b := pbkdf2SHA256(passwd, salt, 128*int(r)*int(p))
+	copy(passwd, b[:32])
+
+	smix(b, int(r), n, p, t, flags, passwd)
+
+	dk := pbkdf2SHA256(passwd, b, 32)
+
+	if flags&yescryptPrehash == 0 {
+		// ClientKey, StoredKey 계산 (SCRAM 호환)
+		clientKey := hmacSHA256(dk, []byte("Client Key"))
+		storedKey := sha256.Sum256(clientKey)
+		dk = storedKey[:]
+	}
+
+	return dk
+}
+
+// smix yescrypt SMix 연산
+//
+// Parameters:
+//   - b: 입력 및 출력 블록 (128*r*p 바이트)
+//   - r, n, p, t: 연산 파라미터
+//   - flags: yescrypt 플래그
+//   - passwd: 중간 비밀번호 (HMAC 결과로 갱신됨)
+func smix(b []byte, r int, n uint64, p, t uint32, flags uint32, passwd []byte) {
+	s := 32 * r
+	nChunk := n / uint64(p)
+
+	nloopAll := nChunk
+	if t <= 1 {
+		if t != 0 {
+			nloopAll *= 2
+		}
+		nloopAll = (nloopAll + 2) / 3
+	} else {
+		nloopAll *= uint64(t - 1)
+	}
+	nloopRW := nloopAll / uint64(p)
+
+	nChunk &^= 1
+	nloopAll = (nloopAll + 1) &^ 1
+	nloopRW = (nloopRW + 1) &^ 1
+
+	v := make([]uint32, int(n)*s)
+	xy := make([]uint32, 2*s)
+	ctxs := make([]*pwxformCtx, p)
+
+	for i := uint32(0); i < p; i++ {
+		vchunk := uint64(i) * nChunk
+		np := nChunk
+		if i == p-1 {
+			np = n - vchunk
+		}
+		bp := b[128*r*int(i) : 128*r*int(i+1)]
+		vp := v[int(vchunk)*s:]
+
+		// S-box 초기화
+		sbox := make([]uint32, sWords)
+		smix1(bp, 1, sBytes/128, 0, sbox, xy, nil)
+		ctx := &pwxformCtx{
+			s2: sbox[:2*sboxLanes],
+			s1: sbox[2*sboxLanes : 4*sboxLanes],
+			s0: sbox[4*sboxLanes:],
+		}
+		ctxs[i] = ctx
+
+		if i == 0 {
+			copy(passwd, hmacSHA256(bp[128*r-64:], passwd))
+		}
+
+		smix1(bp, r, np, flags, vp, xy, ctx)
+		smix2(bp, r, p2floor(np), nloopRW, flags, vp, xy, ctx)
+	}
+
+	for i := uint32(0); i < p; i++ {
+		bp := b[128*r*int(i) : 128*r*int(i+1)]
+		smix2(bp, r, n, nloopAll-nloopRW, flags&^yescryptRW, v, xy, ctxs[i])
+	}
+}
+
+// smix1 yescrypt SMix1 연산
+//
+// Parameters:
+//   - b: 입력 및 출력 블록
+//   - r: 블록 크기 파라미터
+//   - n: 반복 횟수
+//   - flags: yescrypt 플래그
+//   - v: 작업 메모리
+//   - xy: 임시 메모리
+//   - ctx: pwxform 상태 (nil일 경우 Salsa20/8 사용)
+func smix1(b []byte, r int, n uint64, flags uint32, v, xy []uint32, ctx *pwxformCtx) {
+	s := 32 * r
+	x := xy[:s]
+
+	loadBlock(x, b, r)
+
+	for i := uint64(0); i < n; i++ {
+		copy(v[int(i)*s:], x)
+
+		if flags&yescryptRW != 0 && i > 1 {
+			j := wrap(integerify(x, r), i)
+			xorBlock(x, v[int(j)*s:int(j)*s+s])
+		}
+
+		if ctx != nil {
+			blockmixPwxform(x, ctx, r)
+		} else {
+			blockmixSalsa8(x, xy[s:2*s], r)
+		}
+	}
+
+	storeBlock(b, x, r)
+}
+
+// smix2 yescrypt SMix2 연산
+//
+// Parameters:
+//   - b: 입력 및 출력 블록
+//   - r: 블록 크기 파라미터
+//   - n: 작업 메모리 블록 개수 (2의 거듭제곱)
+//   - nloop: 반복 횟수
+//   - flags: yescrypt 플래그
+//   - v: 작업 메모리
+//   - xy: 임시 메모리
+//   - ctx: pwxform 상태 (nil일 경우 Salsa20/8 사용)
+func smix2(b []byte, r int, n, nloop uint64, flags uint32, v, xy []uint32, ctx *pwxformCtx) {
+	if nloop == 0 {
+		return
+	}
+
+	s := 32 * r
+	x := xy[:s]
+
+	loadBlock(x, b, r)
+
+	for i := uint64(0); i < nloop; i++ {
+		j := int(integerify(x, r) & (n - 1))
+		vj := v[j*s : j*s+s]
+		xorBlock(x, vj)
+		if flags&yescryptRW != 0 {
+			copy(vj, x)
+		}
+
+		if ctx != nil {
+			blockmixPwxform(x, ctx, r)
+		} else {
+			blockmixSalsa8(x, xy[s:2*s], r)
+		}
+	}
+
+	storeBlock(b, x, r)
+}
+
+// blockmixSalsa8 Salsa20/8 기반 BlockMix 연산
+//
+// Parameters:
+//   - b: 입력 및 출력 블록
+//   - y: 임시 메모리
+//   - r: 블록 크기 파라미터
+func blockmixSalsa8(b, y []uint32, r int) {
+	var x [16]uint32
+	copy(x[:], b[(2*r-1)*16:])
+
+	for i := 0; i < 2*r; i++ {
+		xorBlock(x[:], b[i*16:i*16+16])
+		salsa20(&x, 8)
+		copy(y[i*16:], x[:])
+	}
+
+	for i := 0; i < r; i++ {
+		copy(b[i*16:], y[(i*2)*16:(i*2)*16+16])
+	}
+	for i := 0; i < r; i++ {
+		copy(b[(i+r)*16:], y[(i*2+1)*16:(i*2+1)*16+16])
+	}
+}
+
+// blockmixPwxform pwxform 기반 BlockMix 연산
+//
+// Parameters:
+//   - b: 입력 및 출력 블록
+//   - ctx: pwxform 상태
+//   - r: 블록 크기 파라미터
+func blockmixPwxform(b []uint32, ctx *pwxformCtx, r int) {
+	var x [pwxWords]uint32
+	r1 := 128 * r / pwxBytes
+
+	copy(x[:], b[(r1-1)*pwxWords:])
+
+	for i := 0; i < r1; i++ {
+		if r1 > 1 {
+			xorBlock(x[:], b[i*pwxWords:i*pwxWords+pwxWords])
+		}
+		pwxform(&x, ctx)
+		copy(b[i*pwxWords:], x[:])
+	}
+
+	i := (r1 - 1) * pwxBytes / 64
+	var blk [16]uint32
+	copy(blk[:], b[i*16:])
+	salsa20(&blk, 2)
+	copy(b[i*16:], blk[:])
+
+	for i++; i < 2*r; i++ {
+		xorBlock(b[i*16:i*16+16], b[(i-1)*16:(i-1)*16+16])
+		copy(blk[:], b[i*16:])
+		salsa20(&blk, 2)
+		copy(b[i*16:], blk[:])
+	}
+}
+
+// pwxform yescrypt pwxform 연산
+//
+// Parameters:
+//   - x: 입력 및 출력 블록 (PWXbytes)
+//   - ctx: pwxform 상태
+func pwxform(x *[pwxWords]uint32, ctx *pwxformCtx) {
+	s0, s1, s2 := ctx.s0, ctx.s1, ctx.s2
+	w := ctx.w
+
+	for i := 0; i < pwxRounds; i++ {
+		for j := 0; j < pwxGather; j++ {
+			base := j * pwxSimple * 2
+			p0 := int(x[base]&sMask) / 8
+			p1 := int(x[base+1]&sMask) / 8
+
+			for k := 0; k < pwxSimple; k++ {
+				lo, hi := x[base+2*k], x[base+2*k+1]
+				sv0 := uint64(s0[2*(p0+k)+1])<<32 | uint64(s0[2*(p0+k)])
+				sv1 := uint64(s1[2*(p1+k)+1])<<32 | uint64(s1[2*(p1+k)])
+
+				v := uint64(hi) * uint64(lo)
+				v += sv0
+				v ^= sv1
+
+				x[base+2*k] = uint32(v)
+				x[base+2*k+1] = uint32(v >> 32)
+
+				if i != 0 && i != pwxRounds-1 {
+					s2[2*w] = uint32(v)
+					s2[2*w+1] = uint32(v >> 32)
+					w++
+				}
+			}
+		}
+	}
+
+	ctx.s0, ctx.s1, ctx.s2 = s2, s0, s1
+	ctx.w = w & (sboxLanes - 1)
+}
+
+// salsa20 Salsa20 코어 연산 (SIMD 셔플 형식의 블록 입력)
+//
+// Parameters:
+//   - b: 입력 및 출력 블록
+//   - rounds: 라운드 수
+func salsa20(b *[16]uint32, rounds int) {
+	var x [16]uint32
+	for i := 0; i < 16; i++ {
+		x[i*5%16] = b[i]
+	}
+
+	for i := 0; i < rounds; i += 2 {
+		x[4] ^= bits.RotateLeft32(x[0]+x[12], 7)
+		x[8] ^= bits.RotateLeft32(x[4]+x[0], 9)
+		x[12] ^= bits.RotateLeft32(x[8]+x[4], 13)
+		x[0] ^= bits.RotateLeft32(x[12]+x[8], 18)
+
+		x[9] ^= bits.RotateLeft32(x[5]+x[1], 7)
+		x[13] ^= bits.RotateLeft32(x[9]+x[5], 9)
+		x[1] ^= bits.RotateLeft32(x[13]+x[9], 13)
+		x[5] ^= bits.RotateLeft32(x[1]+x[13], 18)
+
+		x[14] ^= bits.RotateLeft32(x[10]+x[6], 7)
+		x[2] ^= bits.RotateLeft32(x[14]+x[10], 9)
+		x[6] ^= bits.RotateLeft32(x[2]+x[14], 13)
+		x[10] ^= bits.RotateLeft32(x[6]+x[2], 18)
+
+		x[3] ^= bits.RotateLeft32(x[15]+x[11], 7)
+		x[7] ^= bits.RotateLeft32(x[3]+x[15], 9)
+		x[11] ^= bits.RotateLeft32(x[7]+x[3], 13)
+		x[15] ^= bits.RotateLeft32(x[11]+x[7], 18)
+
+		x[1] ^= bits.RotateLeft32(x[0]+x[3], 7)
+		x[2] ^= bits.RotateLeft32(x[1]+x[0], 9)
+		x[3] ^= bits.RotateLeft32(x[2]+x[1], 13)
+		x[0] ^= bits.RotateLeft32(x[3]+x[2], 18)
+
+		x[6] ^= bits.RotateLeft32(x[5]+x[4], 7)
+		x[7] ^= bits.RotateLeft32(x[6]+x[5], 9)
+		x[4] ^= bits.RotateLeft32(x[7]+x[6], 13)
+		x[5] ^= bits.RotateLeft32(x[4]+x[7], 18)
+
+		x[11] ^= bits.RotateLeft32(x[10]+x[9], 7)
+		x[8] ^= bits.RotateLeft32(x[11]+x[10], 9)
+		x[9] ^= bits.RotateLeft32(x[8]+x[11], 13)
+		x[10] ^= bits.RotateLeft32(x[9]+x[8], 18)
+
+		x[12] ^= bits.RotateLeft32(x[15]+x[14], 7)
+		x[13] ^= bits.RotateLeft32(x[12]+x[15], 9)
+		x[14] ^= bits.RotateLeft32(x[13]+x[12], 13)
+		x[15] ^= bits.RotateLeft32(x[14]+x[13], 18)
+	}
+
+	for i := 0; i < 16; i++ {
+		b[i] += x[i*5%16]
+	}
+}
+
+// loadBlock 바이트 블록을 SIMD 셔플 형식의 32비트 워드 블록으로 변환
+func loadBlock(x []uint32, b []byte, r int) {
+	for k := 0; k < 2*r; k++ {
+		for i := 0; i < 16; i++ {
+			x[k*16+i] = binary.LittleEndian.Uint32(b[(k*16+(i*5%16))*4:])
+		}
+	}
+}
+
+// storeBlock SIMD 셔플 형식의 32비트 워드 블록을 바이트 블록으로 변환
+func storeBlock(b []byte, x []uint32, r int) {
+	for k := 0; k < 2*r; k++ {
+		for i := 0; i < 16; i++ {
+			binary.LittleEndian.PutUint32(b[(k*16+(i*5%16))*4:], x[k*16+i])
+		}
+	}
+}
+
+// xorBlock dst ^= src
+func xorBlock(dst, src []uint32) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
+
+// integerify 블록의 마지막 64바이트 중 첫 64비트 값 반환
+func integerify(x []uint32, r int) uint64 {
+	last := x[(2*r-1)*16:]
+	return uint64(last[13])<<32 | uint64(last[0])
+}
+
+// p2floor x 이하의 가장 큰 2의 거듭제곱 반환
+func p2floor(x uint64) uint64 {
+	for y := x & (x - 1); y != 0; y = x & (x - 1) {
+		x = y
+	}
+	return x
+}
+
+// wrap yescrypt Wrap 연산
+func wrap(x, i uint64) uint64 {
+	n := p2floor(i)
+	return (x & (n - 1)) + (i - n)
+}
+
+// hmacSHA256 HMAC-SHA256 계산
+func hmacSHA256(key, msg []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(msg)
+	return mac.Sum(nil)
+}
+
+// pbkdf2SHA256 반복 횟수 1회의 PBKDF2-HMAC-SHA256 계산
+func pbkdf2SHA256(password, salt []byte, keyLen int) []byte {
+	mac := hmac.New(sha256.New, password)
+	dk := make([]byte, 0, keyLen+sha256.Size)
+	var counter [4]byte
+	for block := uint32(1); len(dk) < keyLen; block++ {
+		mac.Reset()
+		mac.Write(salt)
+		binary.BigEndian.PutUint32(counter[:], block)
+		mac.Write(counter[:])
+		dk = mac.Sum(dk)
+	}
+	return dk[:keyLen]
+}
+
+// encode64 yescrypt 형식(리틀 엔디언) base64 인코딩
+func encode64(dst, src []byte) []byte {
+	for i := 0; i < len(src); {
+		var value, nbits uint32
+		for nbits < 24 && i < len(src) {
+			value |= uint32(src[i]) << nbits
+			nbits += 8
+			i++
+		}
+		for b := uint32(0); b < nbits; b += 6 {
+			dst = append(dst, itoa64[value&0x3f])
+			value >>= 6
+		}
+	}
+	return dst
+}
+
+// decode64 yescrypt 형식(리틀 엔디언) base64 디코딩
+func decode64(src []byte) ([]byte, bool) {
+	dst := make([]byte, 0, len(src)*3/4)
+	for len(src) > 0 {
+		var value, nbits uint32
+		for len(src) > 0 && nbits < 24 {
+			c := atoi64(src[0])
+			if c > 63 {
+				return nil, false
+			}
+			value |= c << nbits
+			nbits += 6
+			src = src[1:]
+		}
+		if nbits < 12 {
+			return nil, false
+		}
+		for ; nbits >= 8; nbits -= 8 {
+			dst = append(dst, byte(value))
+			value >>= 8
+		}
+		if value != 0 {
+			return nil, false
+		}
+	}
+	return dst, true
+}
+
+// decode64Uint32 yescrypt 파라미터용 가변 길이 정수 디코딩
+func decode64Uint32(src []byte, min uint32) (uint32, []byte, bool) {
+	if len(src) == 0 {
+		return 0, src, false
+	}
+	c := atoi64(src[0])
+	if c > 63 {
+		return 0, src, false
+	}
+	src = src[1:]
+
+	var start, end, chars, nbits uint32 = 0, 47, 1, 0
+	dst := min
+	for c > end {
+		dst += (end + 1 - start) << nbits
+		start = end + 1
+		end = start + (62-end)/2
+		chars++
+		nbits += 6
+	}
+	dst += (c - start) << nbits
+
+	for ; chars > 1; chars-- {
+		if len(src) == 0 {
+			return 0, src, false
+		}
+		c = atoi64(src[0])
+		if c > 63 {
+			return 0, src, false
+		}
+		src = src[1:]
+		nbits -= 6
+		dst += c << nbits
+	}
+
+	return dst, src, true
+}
@@ -0,0 +1,230 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package file
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// 파일 종류
+const (
+	TypeFile    = "file"
+	TypeDir     = "dir"
+	TypeSymlink = "symlink"
+	TypeOther   = "other"
+)
+
+// Info 파일 정보 구조체
+type Info struct {
+	Name       string    `json:"name"`
+	Path       string    `json:"path"`
+	Type       string    `json:"type"`
+	Size       int64     `json:"size"`
+	Mode       string    `json:"mode"`
+	Perm       string    `json:"perm"`
+	Uid        uint32    `json:"uid"`
+	Gid        uint32    `json:"gid"`
+	Owner      string    `json:"owner"`
+	Group      string    `json:"group"`
+	ModTime    time.Time `json:"modTime"`
+	LinkTarget string    `json:"linkTarget,omitempty"`
+	// 심볼릭 링크가 가리키는 대상의 종류 (대상이 없을 경우 빈 문자열)
+	TargetType string `json:"targetType,omitempty"`
+}
+
+// idNameCache uid/gid -> 이름 변환 결과 캐시
+type idNameCache struct {
+	mu     sync.Mutex
+	users  map[uint32]string
+	groups map[uint32]string
+}
+
+// names 계정/그룹 이름 캐시 (목록 조회 시 반복 조회 방지)
+var names = &idNameCache{
+	users:  make(map[uint32]string),
+	groups: make(map[uint32]string),
+}
+
+// Stat 파일 정보 조회 (심볼릭 링크는 링크 자체의 정보와 대상 경로 반환)
+//
+// Parameters:
+//   - path: 파일 경로
+//
+// Returns:
+//   - *Info: 파일 정보
+//   - error: 성공(nil), 실패(ErrNotFound, ErrPermission 등)
+func Stat(path string) (*Info, error) {
+	fi, err := os.Lstat(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewInfo(path, fi), nil
+}
+
+// ReadDir 디렉터리 항목 목록 조회 (이름순 정렬)
+//
+// Parameters:
+//   - dirPath: 디렉터리 경로
+//
+// Returns:
+//   - []Info: 항목 목록
+//   - error: 성공(nil), 실패(ErrNotFound, ErrPermission, ErrNotDir 등)
+func ReadDir(dirPath string) ([]Info, error) {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Info, 0, len(entries))
+	for _, entry := range entries {
+		fi, err := entry.Info()
+		if err != nil {
+			// 목록 조회 도중 삭제된 항목
+			continue
+		}
+		list = append(list, *NewInfo(filepath.Join(dirPath, entry.Name()), fi))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	return list, nil
+}
+
+// NewInfo 파일 정보 생성 (열린 파일의 Stat 결과 등 이미 조회한 정보 사용)
+//
+// Parameters:
+//   - path: 파일 경로
+//   - fi: 파일 정보 (os.Lstat 결과 등)
+//
+// Returns:
+//   - *Info: 파일 정보
+func NewInfo(path string, fi fs.FileInfo) *Info {
+	info := &Info{
+		Name:    fi.Name(),
+		Path:    path,
+		Type:    fileType(fi.Mode()),
+		Size:    fi.Size(),
+		Mode:    fi.Mode().String(),
+		Perm:    FormatPerm(fi.Mode()),
+		ModTime: fi.ModTime(),
+	}
+
+	if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+		info.Uid = st.Uid
+		info.Gid = st.Gid
+		info.Owner = names.user(st.Uid)
+		info.Group = names.group(st.Gid)
+	}
+
+	if info.Type == TypeSymlink {
+		info.LinkTarget, _ = os.Readlink(path)
+		if target, err := os.Stat(path); err == nil {
+			info.TargetType = fileType(target.Mode())
+		}
+	}
+
+	return info
+}
+
+// fileType 파일 모드로부터 파일 종류 판별
+//
+// Parameters:
+//   - mode: 파일 모드
+//
+// Returns:
+//   - string: 파일 종류
+func fileType(mode fs.FileMode) string {
+	switch {
+	case mode.IsRegular():
+		return TypeFile
+	case mode.IsDir():
+		return TypeDir
+	case mode&fs.ModeSymlink != 0:
+		return TypeSymlink
+	default:
+		return TypeOther
+	}
+}
+
+// FormatPerm 파일 모드를 8진수 권한 문자열로 변환 (예: 0755, 4755)
+//
+// Parameters:
+//   - mode: 파일 모드
+//
+// Returns:
+//   - string: 8진수 권한
+func FormatPerm(mode fs.FileMode) string {
+	return fmt.Sprintf("%04o", unixPerm(mode))
+}
+
+// user uid에 해당하는 계정 이름 반환 (없을 경우 uid 문자열)
+//
+// Parameters:
+//   - uid: 계정 ID
+//
+// Returns:
+//   - string: 계정 이름
+func (c *idNameCache) user(uid uint32) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if name, exists := c.users[uid]; exists {
+		return name
+	}
+
+	name := strconv.FormatUint(uint64(uid), 10)
+	if u, err := user.LookupId(name); err == nil {
+		name = u.Username
+	}
+	c.users[uid] = name
+
+	return name
+}
+
+// group gid에 해당하는 그룹 이름 반환 (없을 경우 gid 문자열)
+//
+// Parameters:
+//   - gid: 그룹 ID
+//
+// Returns:
+//   - string: 그룹 이름
+func (c *idNameCache) group(gid uint32) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if name, exists := c.groups[gid]; exists {
+		return name
+	}
+
+	name := strconv.FormatUint(uint64(gid), 10)
+	if g, err := user.LookupGroupId(name); err == nil {
+		name = g.Name
+	}
+	c.groups[gid] = name
+
+	return name
+}
@@ -0,0 +1,437 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package file
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// 파일 작업 에러 (errors.Is로 확인)
+//
+// 모든 파일 작업은 errno를 포함한 에러를 반환하므로, 프로세스 경계를 넘어 errno만 전달되더라도
+// 동일하게 확인할 수 있다.
+var (
+	// ErrNotFound 파일 또는 디렉터리가 존재하지 않음
+	ErrNotFound = fs.ErrNotExist
+	// ErrPermission 권한 없음
+	ErrPermission = fs.ErrPermission
+	// ErrExists 파일 또는 디렉터리가 이미 존재함
+	ErrExists = fs.ErrExist
+	// ErrInvalid 잘못된 경로 또는 인자 (errno가 fs.ErrInvalid로 매핑되지 않으므로 EINVAL 사용)
+	ErrInvalid error = syscall.EINVAL
+)
+
+// rename 이름 변경 함수 (테스트에서 이동 실패를 재현하기 위해 교체)
+var rename = os.Rename
+
+// MakeDir 디렉터리 생성
+//
+// Parameters:
+//   - dirPath: 디렉터리 경로
+//   - perm: 디렉터리 권한
+//   - parents: 상위 디렉터리가 없을 경우 함께 생성 (이미 존재할 경우 에러 없음)
+//
+// Returns:
+//   - error: 성공(nil), 실패(ErrExists, ErrNotFound, ErrPermission 등)
+func MakeDir(dirPath string, perm fs.FileMode, parents bool) error {
+	if parents {
+		return os.MkdirAll(dirPath, perm)
+	}
+	return os.Mkdir(dirPath, perm)
+}
+
+// CreateFile 빈 파일 생성
+//
+// Parameters:
+//   - filePath: 파일 경로
+//   - perm: 파일 권한
+//
+// Returns:
+//   - error: 성공(nil), 실패(ErrExists, ErrNotFound, ErrPermission 등)
+func CreateFile(filePath string, perm fs.FileMode) error {
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// Rename 파일 또는 디렉터리 이름 변경 및 이동
+//
+// 다른 파일 시스템으로 이동하는 경우 복사 후 원본을 삭제한다.
+// 대상 파일은 원자적으로 교체하며, 대상이나 원본이 디렉터리일 경우 기존 대상을 임시 경로로 옮긴 후
+// 이동하고 이동에 성공한 경우에만 삭제한다. 이동에 실패하면 기존 대상은 유지된다.
+//
+// Parameters:
+//   - ctx: 작업 취소 컨텍스트 (복사 이동 시)
+//   - src: 원본 경로
+//   - dst: 대상 경로
+//   - overwrite: 대상이 존재할 경우 덮어쓰기
+//
+// Returns:
+//   - error: 성공(nil), 실패(ErrExists, ErrNotFound, ErrPermission, ErrInvalid 등)
+func Rename(ctx context.Context, src, dst string, overwrite bool) error {
+	fi, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	dstInfo, err := checkTarget("rename", src, dst, fi, overwrite)
+	if err != nil {
+		return err
+	}
+	// 디렉터리는 파일로, 파일은 디렉터리로 교체할 수 없으므로 기존 대상을 옮긴 후 교체
+	if dstInfo != nil && (fi.IsDir() || dstInfo.IsDir()) {
+		err = swapEntry(src, dst)
+	} else {
+		err = rename(src, dst)
+	}
+	if err == nil || !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+
+	// 파일 시스템이 다를 경우 복사 후 원본 삭제 (복사는 임시 경로에서 진행하므로 실패해도 기존 대상 유지)
+	if err := Copy(ctx, src, dst, overwrite); err != nil {
+		return err
+	}
+	return os.RemoveAll(src)
+}
+
+// Copy 파일 또는 디렉터리 복사 (디렉터리는 하위 항목까지 복사)
+//
+// 권한 비트와 수정 시각을 유지하며, 심볼릭 링크는 링크 자체를 복사한다.
+// 대상 디렉터리의 임시 경로에 복사한 후 이동하므로 복사에 실패하더라도 기존 대상은 유지된다.
+//
+// Parameters:
+//   - ctx: 작업 취소 컨텍스트
+//   - src: 원본 경로
+//   - dst: 대상 경로
+//   - overwrite: 대상이 존재할 경우 덮어쓰기
+//
+// Returns:
+//   - error: 성공(nil), 실패(ErrExists, ErrNotFound, ErrPermission, ErrInvalid 등)
+func Copy(ctx context.Context, src, dst string, overwrite bool) error {
+	fi, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	dstInfo, err := checkTarget("copy", src, dst, fi, overwrite)
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".weblin-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tmpPath := filepath.Join(tmpDir, filepath.Base(dst))
+	if err := copyEntry(ctx, src, tmpPath, fi); err != nil {
+		return err
+	}
+
+	// 디렉터리는 원자적으로 교체할 수 없으므로 복사를 마친 후 기존 대상과 교체
+	if dstInfo != nil && (fi.IsDir() || dstInfo.IsDir()) {
+		return swapEntry(tmpPath, dst)
+	}
+	return ReplaceFile(tmpPath, dst, overwrite)
+}
+
+// swapEntry 기존 대상을 같은 디렉터리의 임시 경로로 옮긴 후 원본을 대상 경로로 이동
+//
+// 이동에 성공한 경우에만 기존 대상을 삭제하며, 실패한 경우 기존 대상을 원래 경로로 되돌린다.
+//
+// Parameters:
+//   - src: 원본 경로
+//   - dst: 대상 경로 (존재해야 함)
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func swapEntry(src, dst string) error {
+	backupDir, err := os.MkdirTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".weblin-old-*")
+	if err != nil {
+		return err
+	}
+	backupPath := filepath.Join(backupDir, filepath.Base(dst))
+	if err := rename(dst, backupPath); err != nil {
+		os.Remove(backupDir)
+		return err
+	}
+
+	if err := rename(src, dst); err != nil {
+		// 되돌리지 못한 경우 기존 대상을 임시 경로에 남겨 둠
+		if rename(backupPath, dst) == nil {
+			os.Remove(backupDir)
+		}
+		return err
+	}
+
+	return os.RemoveAll(backupDir)
+}
+
+// Remove 파일 또는 디렉터리 삭제
+//
+// Parameters:
+//   - path: 삭제할 경로
+//   - recursive: 디렉터리 하위 항목까지 삭제 (false일 경우 빈 디렉터리만 삭제 가능)
+//
+// Returns:
+//   - error: 성공(nil), 실패(ErrNotFound, ErrPermission 등)
+func Remove(path string, recursive bool) error {
+	if filepath.Clean(path) == "/" {
+		return &os.PathError{Op: "remove", Path: path, Err: syscall.EINVAL}
+	}
+
+	fi, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() && recursive {
+		return os.RemoveAll(path)
+	}
+	return os.Remove(path)
+}
+
+// ReplaceFile 같은 파일 시스템의 임시 파일을 대상 경로로 원자적 이동
+//
+// 덮어쓰기를 허용하지 않을 경우 하드 링크를 이용하여 대상 확인과 이동 사이의 경쟁 상태를 방지한다.
+//
+// Parameters:
+//   - tmpPath: 임시 파일 경로
+//   - dstPath: 대상 파일 경로
+//   - overwrite: 대상이 존재할 경우 덮어쓰기
+//
+// Returns:
+//   - error: 성공(nil), 실패(ErrExists, ErrPermission 등)
+func ReplaceFile(tmpPath, dstPath string, overwrite bool) error {
+	if overwrite {
+		return os.Rename(tmpPath, dstPath)
+	}
+
+	err := os.Link(tmpPath, dstPath)
+	if err == nil {
+		return os.Remove(tmpPath)
+	}
+	if !errors.Is(err, syscall.EPERM) && !errors.Is(err, syscall.EOPNOTSUPP) {
+		return err
+	}
+
+	// 하드 링크를 지원하지 않는 파일 시스템
+	if _, err := os.Lstat(dstPath); err == nil {
+		return &os.PathError{Op: "rename", Path: dstPath, Err: syscall.EEXIST}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return os.Rename(tmpPath, dstPath)
+}
+
+// checkTarget 이동 또는 복사 대상 경로 확인
+//
+// 원본과 대상이 같은 항목이거나 한쪽이 다른 쪽의 상위 경로일 경우, 대상을 덮어쓰면
+// 원본이 삭제되므로 거부한다. 경로 중간의 심볼릭 링크는 실제 경로로 변환하여 비교한다.
+//
+// Parameters:
+//   - op: 작업 이름
+//   - src: 원본 경로
+//   - dst: 대상 경로
+//   - srcInfo: 원본 파일 정보
+//   - overwrite: 덮어쓰기 여부
+//
+// Returns:
+//   - fs.FileInfo: 기존 대상 정보 (대상이 없을 경우 nil)
+//   - error: 성공(nil), 실패(ErrExists, ErrInvalid 등)
+func checkTarget(op, src, dst string, srcInfo fs.FileInfo, overwrite bool) (fs.FileInfo, error) {
+	realSrc, err := resolveParent(src)
+	if err != nil {
+		return nil, err
+	}
+	realDst, err := resolveParent(dst)
+	if err != nil {
+		return nil, err
+	}
+	if isSubPath(realSrc, realDst) || isSubPath(realDst, realSrc) {
+		return nil, &os.LinkError{Op: op, Old: src, New: dst, Err: syscall.EINVAL}
+	}
+
+	dstInfo, err := os.Lstat(dst)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	// 하드 링크 등 다른 경로의 같은 항목
+	if os.SameFile(srcInfo, dstInfo) {
+		return nil, &os.LinkError{Op: op, Old: src, New: dst, Err: syscall.EINVAL}
+	}
+	if !overwrite {
+		return nil, &os.PathError{Op: op, Path: dst, Err: syscall.EEXIST}
+	}
+	return dstInfo, nil
+}
+
+// resolveParent 마지막 항목을 제외한 경로의 심볼릭 링크를 실제 경로로 변환
+//
+// 이름 변경, 삭제는 마지막 항목(심볼릭 링크 자체)에 적용되므로 마지막 항목은 변환하지 않는다.
+//
+// Parameters:
+//   - path: 경로
+//
+// Returns:
+//   - string: 변환한 경로
+//   - error: 성공(nil), 실패(error)
+func resolveParent(path string) (string, error) {
+	dir, err := filepath.EvalSymlinks(filepath.Dir(filepath.Clean(path)))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, filepath.Base(path)), nil
+}
+
+// copyEntry 파일 종류에 따라 항목 복사
+//
+// Parameters:
+//   - ctx: 작업 취소 컨텍스트
+//   - src: 원본 경로
+//   - dst: 대상 경로
+//   - fi: 원본 파일 정보
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func copyEntry(ctx context.Context, src, dst string, fi fs.FileInfo) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	switch {
+	case fi.Mode().IsRegular():
+		return copyFile(src, dst, fi)
+	case fi.IsDir():
+		return copyDir(ctx, src, dst, fi)
+	case fi.Mode()&fs.ModeSymlink != 0:
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(target, dst)
+	default:
+		// 장치 파일, 파이프, 소켓은 복사하지 않음
+		return &os.PathError{Op: "copy", Path: src, Err: syscall.EINVAL}
+	}
+}
+
+// copyDir 디렉터리와 하위 항목 복사
+//
+// Parameters:
+//   - ctx: 작업 취소 컨텍스트
+//   - src: 원본 디렉터리 경로
+//   - dst: 대상 디렉터리 경로
+//   - fi: 원본 디렉터리 정보
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func copyDir(ctx context.Context, src, dst string, fi fs.FileInfo) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+
+	// 하위 항목을 복사할 수 있도록 쓰기 권한을 부여하여 생성한 후 원본 권한 적용
+	if err := os.Mkdir(dst, fi.Mode().Perm()|0o700); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		childInfo, err := entry.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return err
+		}
+		err = copyEntry(ctx, filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name()), childInfo)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := os.Chmod(dst, fi.Mode()&(fs.ModePerm|fs.ModeSetgid|fs.ModeSticky)); err != nil {
+		return err
+	}
+	return os.Chtimes(dst, fi.ModTime(), fi.ModTime())
+}
+
+// copyFile 일반 파일 복사
+//
+// Parameters:
+//   - src: 원본 파일 경로
+//   - dst: 대상 파일 경로
+//   - fi: 원본 파일 정보
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func copyFile(src, dst string, fi fs.FileInfo) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, fi.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+
+	return os.Chtimes(dst, fi.ModTime(), fi.ModTime())
+}
+
+// isSubPath child가 parent와 같거나 parent의 하위 경로인지 확인
+//
+// Parameters:
+//   - parent: 상위 경로
+//   - child: 확인할 경로
+//
+// Returns:
+//   - bool: 하위 경로(true), 아님(false)
+func isSubPath(parent, child string) bool {
+	parent = filepath.Clean(parent)
+	child = filepath.Clean(child)
+	if parent == child {
+		return true
+	}
+	if parent == "/" {
+		return true
+	}
+	return strings.HasPrefix(child, parent+"/")
+}
@@ -0,0 +1,231 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package file
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+// writeTree 테스트용 파일 생성 (디렉터리는 자동 생성)
+//
+// Parameters:
+//   - t: 테스트 정보
+//   - files: 경로별 파일 내용
+func writeTree(t *testing.T, files map[string]string) {
+	t.Helper()
+
+	for path, data := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// assertFile 파일 내용 확인
+//
+// Parameters:
+//   - t: 테스트 정보
+//   - path: 파일 경로
+//   - want: 기대하는 파일 내용
+func assertFile(t *testing.T, path, want string) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %s", path, err)
+	}
+	if string(data) != want {
+		t.Fatalf("%s = %q, want %q", path, data, want)
+	}
+}
+
+// assertNoTemp 작업 후 남은 임시 경로가 없는지 확인
+//
+// Parameters:
+//   - t: 테스트 정보
+//   - dir: 확인할 디렉터리
+//   - want: 존재해야 하는 항목 수
+func assertNoTemp(t *testing.T, dir string, want int) {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != want {
+		names := make([]string, 0, len(entries))
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Fatalf("entries in %s = %v, want %d entries", dir, names, want)
+	}
+}
+
+// failRename 원본이 failSrc인 이름 변경만 실패하도록 rename 교체
+//
+// Parameters:
+//   - t: 테스트 정보
+//   - failSrc: 실패시킬 원본 경로
+//   - errno: 반환할 에러 번호
+func failRename(t *testing.T, failSrc string, errno syscall.Errno) {
+	t.Helper()
+
+	orig := rename
+	rename = func(oldpath, newpath string) error {
+		if oldpath == failSrc {
+			return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: errno}
+		}
+		return orig(oldpath, newpath)
+	}
+	t.Cleanup(func() { rename = orig })
+}
+
+func TestRenameKeepsDestinationOnFailure(t *testing.T) {
+	tests := []struct {
+		name   string
+		srcDir bool
+		dstDir bool
+	}{
+		{"dir over dir", true, true},
+		{"file over dir", false, true},
+		{"dir over file", true, false},
+	}
+	for _, tt := range tests {
+		for _, errno := range []syscall.Errno{syscall.EACCES, syscall.EXDEV} {
+			t.Run(tt.name+"/"+errno.Error(), func(t *testing.T) {
+				dir := t.TempDir()
+				src := filepath.Join(dir, "src")
+				dst := filepath.Join(dir, "dst")
+				srcFile, dstFile := src, dst
+				if tt.srcDir {
+					srcFile = filepath.Join(src, "a")
+				}
+				if tt.dstDir {
+					dstFile = filepath.Join(dst, "b")
+				}
+				writeTree(t, map[string]string{srcFile: "new", dstFile: "old"})
+
+				// 같은 파일 시스템의 이동 실패, 또는 다른 파일 시스템으로 판단 후 복사 취소
+				failRename(t, src, errno)
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				if err := Rename(ctx, src, dst, true); err == nil {
+					t.Fatalf("Rename() error = nil, want error")
+				}
+				assertFile(t, dstFile, "old")
+				assertFile(t, srcFile, "new")
+				assertNoTemp(t, dir, 2)
+			})
+		}
+	}
+}
+
+func TestRenameReplacesDirectory(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	dst := filepath.Join(dir, "dst")
+	writeTree(t, map[string]string{
+		filepath.Join(src, "a"): "new",
+		filepath.Join(dst, "b"): "old",
+	})
+
+	if err := Rename(context.Background(), src, dst, true); err != nil {
+		t.Fatalf("Rename() error = %s", err)
+	}
+	assertFile(t, filepath.Join(dst, "a"), "new")
+	if _, err := os.Lstat(filepath.Join(dst, "b")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("old entry remains: %v", err)
+	}
+	assertNoTemp(t, dir, 1)
+}
+
+func TestRenameNoOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	dst := filepath.Join(dir, "dst")
+	writeTree(t, map[string]string{src: "new", dst: "old"})
+
+	if err := Rename(context.Background(), src, dst, false); !errors.Is(err, ErrExists) {
+		t.Fatalf("Rename() error = %v, want ErrExists", err)
+	}
+	assertFile(t, dst, "old")
+}
+
+func TestRenameIntoItself(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	writeTree(t, map[string]string{filepath.Join(src, "a"): "data"})
+
+	if err := Rename(context.Background(), src, filepath.Join(src, "sub"), true); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("Rename() error = %v, want ErrInvalid", err)
+	}
+}
+
+func TestCopyKeepsDestinationOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	dst := filepath.Join(dir, "dst")
+	writeTree(t, map[string]string{
+		filepath.Join(src, "a"): "new",
+		filepath.Join(dst, "b"): "old",
+	})
+
+	// 임시 경로에 복사한 결과를 대상 경로로 옮기는 단계에서 실패 (기존 대상 복원은 허용)
+	orig := rename
+	rename = func(oldpath, newpath string) error {
+		if newpath == dst && !strings.Contains(oldpath, ".weblin-old-") {
+			return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: syscall.ENOSPC}
+		}
+		return orig(oldpath, newpath)
+	}
+	t.Cleanup(func() { rename = orig })
+
+	if err := Copy(context.Background(), src, dst, true); err == nil {
+		t.Fatalf("Copy() error = nil, want error")
+	}
+	assertFile(t, filepath.Join(dst, "b"), "old")
+	assertFile(t, filepath.Join(src, "a"), "new")
+	assertNoTemp(t, dir, 2)
+}
+
+func TestCopyCancelled(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	dst := filepath.Join(dir, "dst")
+	writeTree(t, map[string]string{
+		filepath.Join(src, "a"): "new",
+		filepath.Join(dst, "b"): "old",
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Copy(ctx, src, dst, true); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Copy() error = %v, want context.Canceled", err)
+	}
+	assertFile(t, filepath.Join(dst, "b"), "old")
+	assertNoTemp(t, dir, 2)
+}
@@ -0,0 +1,549 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package file
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// 권한 변경 결과 목록 최대 개수 (초과분은 개수만 집계)
+const maxPermResults = 1000
+
+// syscall 패키지에 정의되지 않은 리눅스 상수
+const (
+	// oPath 내용 접근 없이 경로 위치만 여는 파일 디스크립터 (O_PATH)
+	oPath = 0x200000
+	// atEmptyPath 경로 대신 파일 디스크립터 자체를 대상으로 지정 (AT_EMPTY_PATH)
+	atEmptyPath = 0x1000
+)
+
+// 권한 비트 대상별 마스크 (sticky 비트는 chmod와 같이 o, a 대상에 포함)
+const (
+	whoUser  = 0o4700
+	whoGroup = 0o2070
+	whoOther = 0o1007
+	whoAll   = 0o7777
+)
+
+// ModeSpec chmod 형식의 권한 변경 명세 (숫자 또는 기호 형식)
+type ModeSpec struct {
+	text    string
+	numeric bool
+	bits    uint32
+	clauses []modeClause
+}
+
+// modeClause 기호 형식 권한 변경 항목 (예: "u+x"의 "+x")
+type modeClause struct {
+	who  uint32
+	op   byte
+	perm uint32
+	// X: 디렉터리이거나 실행 권한이 하나라도 있을 때만 실행 권한
+	condExec bool
+	// u, g, o: 현재 해당 대상의 권한을 복사
+	copyFrom byte
+}
+
+// ParseMode chmod 형식의 권한 문자열 해석
+//
+// 숫자 형식(예: 755, 0644, 2775)과 기호 형식(예: u+x,go-w, a=rX, u=rwx,g=rx,o=, +t)을 지원한다.
+// 기호 형식에서 대상을 생략하면 umask를 적용하지 않고 모든 대상(a)으로 처리한다.
+// GNU chmod와 같이 디렉터리의 setuid/setgid 비트는 명시적으로 제거(u-s, g-s)하지 않는 한 유지한다.
+//
+// Parameters:
+//   - text: 권한 문자열
+//
+// Returns:
+//   - *ModeSpec: 권한 변경 명세
+//   - error: 성공(nil), 실패(ErrInvalid)
+func ParseMode(text string) (*ModeSpec, error) {
+	spec := &ModeSpec{text: text}
+	if text == "" {
+		return nil, fmt.Errorf("empty mode: %w", ErrInvalid)
+	}
+
+	if text[0] >= '0' && text[0] <= '7' {
+		value, err := strconv.ParseUint(text, 8, 32)
+		if err != nil || len(text) > 4 {
+			return nil, fmt.Errorf("invalid numeric mode %q: %w", text, ErrInvalid)
+		}
+		spec.numeric = true
+		spec.bits = uint32(value)
+		return spec, nil
+	}
+
+	for _, clause := range strings.Split(text, ",") {
+		clauses, err := parseClause(clause)
+		if err != nil {
+			return nil, fmt.Errorf("invalid symbolic mode %q: %w", text, err)
+		}
+		spec.clauses = append(spec.clauses, clauses...)
+	}
+	return spec, nil
+}
+
+// parseClause 기호 형식 권한 항목 하나 해석 (예: "go-w", "u=rw+x")
+//
+// Parameters:
+//   - text: 권한 항목 문자열
+//
+// Returns:
+//   - []modeClause: 연산자별 권한 변경 항목
+//   - error: 성공(nil), 실패(ErrInvalid)
+func parseClause(text string) ([]modeClause, error) {
+	var who uint32
+	i := 0
+	for ; i < len(text) && strings.IndexByte("ugoa", text[i]) >= 0; i++ {
+		switch text[i] {
+		case 'u':
+			who |= whoUser
+		case 'g':
+			who |= whoGroup
+		case 'o':
+			who |= whoOther
+		case 'a':
+			who |= whoAll
+		}
+	}
+	if who == 0 {
+		who = whoAll
+	}
+	if i == len(text) {
+		return nil, ErrInvalid
+	}
+
+	var clauses []modeClause
+	for i < len(text) {
+		c := modeClause{who: who, op: text[i]}
+		if c.op != '+' && c.op != '-' && c.op != '=' {
+			return nil, ErrInvalid
+		}
+		i++
+
+		// 다른 대상의 권한 복사 (예: g=u)
+		if i < len(text) && strings.IndexByte("ugo", text[i]) >= 0 {
+			c.copyFrom = text[i]
+			clauses = append(clauses, c)
+			i++
+			continue
+		}
+
+		for ; i < len(text) && strings.IndexByte("+-=", text[i]) < 0; i++ {
+			switch text[i] {
+			case 'r':
+				c.perm |= 0o444
+			case 'w':
+				c.perm |= 0o222
+			case 'x':
+				c.perm |= 0o111
+			case 'X':
+				c.condExec = true
+			case 's':
+				c.perm |= syscall.S_ISUID | syscall.S_ISGID
+			case 't':
+				c.perm |= syscall.S_ISVTX
+			default:
+				return nil, ErrInvalid
+			}
+		}
+		clauses = append(clauses, c)
+	}
+	return clauses, nil
+}
+
+// String 권한 문자열 반환
+func (s *ModeSpec) String() string {
+	return s.text
+}
+
+// Apply 현재 권한에 권한 변경 명세를 적용한 결과 반환
+//
+// Parameters:
+//   - mode: 현재 파일 모드
+//   - isDir: 디렉터리 여부 (X 처리용)
+//
+// Returns:
+//   - fs.FileMode: 변경 후 권한 (권한 및 특수 비트만 포함)
+func (s *ModeSpec) Apply(mode fs.FileMode, isDir bool) fs.FileMode {
+	cur := unixPerm(mode)
+	// 디렉터리의 setuid/setgid 비트는 명시적으로 지정하지 않으면 유지
+	keep := uint32(0)
+	if isDir {
+		keep = cur & (syscall.S_ISUID | syscall.S_ISGID)
+	}
+
+	if s.numeric {
+		return toFileMode(s.bits | keep)
+	}
+
+	for _, c := range s.clauses {
+		perm := c.perm
+		if c.condExec && (isDir || cur&0o111 != 0) {
+			perm |= 0o111
+		}
+		switch c.copyFrom {
+		case 'u':
+			perm |= ((cur >> 6) & 7) * 0o111
+		case 'g':
+			perm |= ((cur >> 3) & 7) * 0o111
+		case 'o':
+			perm |= (cur & 7) * 0o111
+		}
+		perm &= c.who
+
+		switch c.op {
+		case '+':
+			cur |= perm
+		case '-':
+			cur &^= perm
+		case '=':
+			cur = cur&^c.who | perm | keep&c.who&^perm
+		}
+	}
+	return toFileMode(cur)
+}
+
+// unixPerm 파일 모드를 유닉스 권한 비트로 변환 (setuid, setgid, sticky 포함)
+//
+// Parameters:
+//   - mode: 파일 모드
+//
+// Returns:
+//   - uint32: 권한 비트
+func unixPerm(mode fs.FileMode) uint32 {
+	perm := uint32(mode.Perm())
+	if mode&fs.ModeSetuid != 0 {
+		perm |= syscall.S_ISUID
+	}
+	if mode&fs.ModeSetgid != 0 {
+		perm |= syscall.S_ISGID
+	}
+	if mode&fs.ModeSticky != 0 {
+		perm |= syscall.S_ISVTX
+	}
+	return perm
+}
+
+// toFileMode 유닉스 권한 비트를 파일 모드로 변환
+//
+// Parameters:
+//   - perm: 권한 비트
+//
+// Returns:
+//   - fs.FileMode: 파일 모드
+func toFileMode(perm uint32) fs.FileMode {
+	mode := fs.FileMode(perm & 0o777)
+	if perm&syscall.S_ISUID != 0 {
+		mode |= fs.ModeSetuid
+	}
+	if perm&syscall.S_ISGID != 0 {
+		mode |= fs.ModeSetgid
+	}
+	if perm&syscall.S_ISVTX != 0 {
+		mode |= fs.ModeSticky
+	}
+	return mode
+}
+
+// PermChange 권한 및 소유자 변경 요청 구조체
+type PermChange struct {
+	// 파일과 디렉터리 공통 권한 (nil이면 변경하지 않음)
+	Mode *ModeSpec
+	// 파일에만 적용할 권한 (지정 시 Mode 대신 적용)
+	FileMode *ModeSpec
+	// 디렉터리에만 적용할 권한 (지정 시 Mode 대신 적용)
+	DirMode *ModeSpec
+	// 변경할 소유자, 그룹 ID (-1이면 변경하지 않음)
+	Uid int
+	Gid int
+	// 하위 항목 포함 여부 (심볼릭 링크는 따라가지 않음)
+	Recursive bool
+}
+
+// PermResult 경로별 권한 변경 결과 구조체
+type PermResult struct {
+	Path  string `json:"path"`
+	Perm  string `json:"perm,omitempty"`
+	Owner string `json:"owner,omitempty"`
+	Group string `json:"group,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// PermSummary 권한 변경 결과 요약 구조체
+//
+// 결과 목록은 최대 개수까지만 포함하며, 실패 항목은 성공 항목보다 우선하여 포함한다.
+type PermSummary struct {
+	Changed   int          `json:"changed"`
+	Failed    int          `json:"failed"`
+	Truncated bool         `json:"truncated,omitempty"`
+	Results   []PermResult `json:"results"`
+}
+
+// ChangePerm 권한 및 소유자 변경 (일부 경로가 실패하더라도 나머지 경로는 계속 처리)
+//
+// 재귀 변경 시 소유자의 읽기/실행 권한을 제거하는 디렉터리는 하위 항목을 먼저 처리한 후
+// 권한을 적용하여 하위 항목에 접근하지 못하는 문제를 방지한다.
+// 지정한 경로는 chmod와 같이 심볼릭 링크를 따라가지만, 하위 항목은 상위 디렉터리의 파일
+// 디스크립터 기준으로 심볼릭 링크를 따라가지 않고 열어 열린 항목에만 변경을 적용한다.
+// 따라서 처리 중 하위 항목이 심볼릭 링크로 바뀌더라도 링크 대상은 변경되지 않는다.
+//
+// Parameters:
+//   - ctx: 작업 취소 컨텍스트
+//   - paths: 대상 경로 목록
+//   - change: 변경 내용
+//
+// Returns:
+//   - *PermSummary: 경로별 결과
+//   - error: 성공(nil), 취소(context 에러)
+func ChangePerm(ctx context.Context, paths []string, change *PermChange) (*PermSummary, error) {
+	summary := &PermSummary{Results: []PermResult{}}
+	for _, path := range paths {
+		fd, err := syscall.Open(path, oPath|syscall.O_CLOEXEC, 0)
+		if err != nil {
+			summary.add(path, &os.PathError{Op: "open", Path: path, Err: err})
+			continue
+		}
+		err = changeEntry(ctx, summary, path, fd, change)
+		syscall.Close(fd)
+		if err != nil {
+			return summary, err
+		}
+	}
+	return summary, nil
+}
+
+// changeEntry 항목 하나(재귀 시 하위 항목 포함)의 권한 및 소유자 변경
+//
+// 파일 종류와 현재 권한은 열린 파일 디스크립터에서 조회하므로 경로가 바뀌더라도
+// 조회한 항목과 변경하는 항목은 항상 같다.
+//
+// Parameters:
+//   - ctx: 작업 취소 컨텍스트
+//   - summary: 결과 요약
+//   - path: 경로 (결과 표시용)
+//   - fd: 항목의 파일 디스크립터 (O_PATH)
+//   - change: 변경 내용
+//
+// Returns:
+//   - error: 성공(nil), 취소(context 에러)
+func changeEntry(ctx context.Context, summary *PermSummary, path string, fd int, change *PermChange) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var st syscall.Stat_t
+	if err := syscall.Fstat(fd, &st); err != nil {
+		summary.add(path, &os.PathError{Op: "stat", Path: path, Err: err})
+		return nil
+	}
+	mode := statMode(&st)
+	isDir := mode.IsDir()
+
+	if mode&fs.ModeSymlink != 0 {
+		// 하위 항목의 심볼릭 링크는 권한이 없으므로 링크 자체의 소유자만 변경
+		if change.Uid >= 0 || change.Gid >= 0 {
+			summary.add(path, fchown(path, fd, change.Uid, change.Gid))
+		}
+		return nil
+	}
+
+	spec := change.Mode
+	if isDir && change.DirMode != nil {
+		spec = change.DirMode
+	} else if !isDir && change.FileMode != nil {
+		spec = change.FileMode
+	}
+
+	if !isDir || !change.Recursive {
+		summary.add(path, applyPerm(path, fd, mode, spec, change))
+		return nil
+	}
+
+	// 소유자 읽기/실행 권한을 제거하는 경우 하위 항목 처리 후 적용
+	// (디렉터리 자체를 변경하지 못해도 하위 항목은 계속 처리)
+	var dirErr error
+	deferred := spec != nil && unixPerm(spec.Apply(mode, true))&0o500 != 0o500
+	if !deferred {
+		dirErr = applyPerm(path, fd, mode, spec, change)
+	}
+
+	names, err := readDirNames(path, fd)
+	for _, name := range names {
+		childPath := filepath.Join(path, name)
+		childFd, err := syscall.Openat(fd, name, oPath|syscall.O_NOFOLLOW|syscall.O_CLOEXEC, 0)
+		if err != nil {
+			summary.add(childPath, &os.PathError{Op: "open", Path: childPath, Err: err})
+			continue
+		}
+		err = changeEntry(ctx, summary, childPath, childFd, change)
+		syscall.Close(childFd)
+		if err != nil {
+			return err
+		}
+	}
+
+	if deferred {
+		dirErr = applyPerm(path, fd, mode, spec, change)
+	}
+	if dirErr == nil {
+		dirErr = err
+	}
+	summary.add(path, dirErr)
+	return nil
+}
+
+// readDirNames 디렉터리 파일 디스크립터 기준으로 하위 항목 이름 목록 조회 (이름순)
+//
+// Parameters:
+//   - path: 디렉터리 경로 (에러 표시용)
+//   - fd: 디렉터리의 파일 디스크립터 (O_PATH)
+//
+// Returns:
+//   - []string: 하위 항목 이름 목록
+//   - error: 성공(nil), 실패(error)
+func readDirNames(path string, fd int) ([]string, error) {
+	dirFd, err := syscall.Openat(fd, ".", syscall.O_RDONLY|syscall.O_DIRECTORY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: path, Err: err}
+	}
+	dir := os.NewFile(uintptr(dirFd), path)
+	defer dir.Close()
+
+	names, err := dir.Readdirnames(-1)
+	sort.Strings(names)
+	return names, err
+}
+
+// applyPerm 파일 디스크립터가 가리키는 항목의 소유자 및 권한 변경
+//
+// 소유자 변경 시 커널이 setuid/setgid 비트를 해제하므로 소유자를 먼저 변경한다.
+//
+// Parameters:
+//   - path: 경로 (에러 표시용)
+//   - fd: 항목의 파일 디스크립터 (O_PATH)
+//   - mode: 현재 파일 모드
+//   - spec: 적용할 권한 (nil이면 변경하지 않음)
+//   - change: 변경 내용
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func applyPerm(path string, fd int, mode fs.FileMode, spec *ModeSpec, change *PermChange) error {
+	if change.Uid >= 0 || change.Gid >= 0 {
+		if err := fchown(path, fd, change.Uid, change.Gid); err != nil {
+			return err
+		}
+	}
+	if spec == nil {
+		return nil
+	}
+
+	// O_PATH 파일 디스크립터는 fchmod를 지원하지 않으므로 /proc/self/fd 경로로 변경
+	perm := unixPerm(spec.Apply(mode, mode.IsDir()))
+	if err := syscall.Chmod("/proc/self/fd/"+strconv.Itoa(fd), perm); err != nil {
+		return &os.PathError{Op: "chmod", Path: path, Err: err}
+	}
+	return nil
+}
+
+// fchown 파일 디스크립터가 가리키는 항목의 소유자 변경 (심볼릭 링크는 링크 자체)
+//
+// Parameters:
+//   - path: 경로 (에러 표시용)
+//   - fd: 항목의 파일 디스크립터 (O_PATH)
+//   - uid: 소유자 ID (-1이면 변경하지 않음)
+//   - gid: 그룹 ID (-1이면 변경하지 않음)
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func fchown(path string, fd, uid, gid int) error {
+	if err := syscall.Fchownat(fd, "", uid, gid, atEmptyPath); err != nil {
+		return &os.PathError{Op: "chown", Path: path, Err: err}
+	}
+	return nil
+}
+
+// statMode 파일 상태 정보의 모드를 파일 모드로 변환
+//
+// Parameters:
+//   - st: 파일 상태 정보
+//
+// Returns:
+//   - fs.FileMode: 파일 모드
+func statMode(st *syscall.Stat_t) fs.FileMode {
+	mode := toFileMode(st.Mode & 0o7777)
+	switch st.Mode & syscall.S_IFMT {
+	case syscall.S_IFDIR:
+		mode |= fs.ModeDir
+	case syscall.S_IFLNK:
+		mode |= fs.ModeSymlink
+	case syscall.S_IFIFO:
+		mode |= fs.ModeNamedPipe
+	case syscall.S_IFSOCK:
+		mode |= fs.ModeSocket
+	case syscall.S_IFCHR:
+		mode |= fs.ModeDevice | fs.ModeCharDevice
+	case syscall.S_IFBLK:
+		mode |= fs.ModeDevice
+	}
+	return mode
+}
+
+// add 경로별 결과 추가 (성공 시 변경 후 정보 조회)
+//
+// Parameters:
+//   - path: 경로
+//   - err: 변경 에러
+func (s *PermSummary) add(path string, err error) {
+	result := PermResult{Path: path}
+	if err != nil {
+		s.Failed++
+		result.Error = err.Error()
+	} else {
+		s.Changed++
+		if fi, lerr := os.Lstat(path); lerr == nil {
+			result.Perm = FormatPerm(fi.Mode())
+			if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+				result.Owner = names.user(st.Uid)
+				result.Group = names.group(st.Gid)
+			}
+		}
+	}
+
+	if len(s.Results) < maxPermResults {
+		s.Results = append(s.Results, result)
+		return
+	}
+	s.Truncated = true
+	if err == nil {
+		return
+	}
+	// 목록이 가득 찬 경우 실패 항목이 보이도록 성공 항목을 대체
+	for i := len(s.Results) - 1; i >= 0; i-- {
+		if s.Results[i].Error == "" {
+			s.Results[i] = result
+			return
+		}
+	}
+}
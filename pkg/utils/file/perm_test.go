@@ -0,0 +1,222 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package file
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// mustParseMode 권한 문자열 해석 (실패 시 테스트 중단)
+//
+// Parameters:
+//   - t: 테스트 정보
+//   - text: 권한 문자열
+//
+// Returns:
+//   - *ModeSpec: 권한 변경 명세
+func mustParseMode(t *testing.T, text string) *ModeSpec {
+	t.Helper()
+
+	spec, err := ParseMode(text)
+	if err != nil {
+		t.Fatalf("ParseMode(%q) error = %s", text, err)
+	}
+	return spec
+}
+
+// statOwner 항목(심볼릭 링크는 링크 자체)의 권한과 소유자 조회
+//
+// Parameters:
+//   - t: 테스트 정보
+//   - path: 경로
+//
+// Returns:
+//   - fs.FileMode: 권한
+//   - uint32: 소유자 ID
+func statOwner(t *testing.T, path string) (fs.FileMode, uint32) {
+	t.Helper()
+
+	fi, err := os.Lstat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fi.Mode().Perm(), fi.Sys().(*syscall.Stat_t).Uid
+}
+
+func TestModeSpecApply(t *testing.T) {
+	// GNU chmod(1) 실행 결과 (umask 0)
+	tests := []struct {
+		mode  string
+		isDir bool
+		cur   uint32
+		want  uint32
+	}{
+		{"755", false, 0o644, 0o755},
+		{"0644", false, 0o644, 0o644},
+		{"2775", false, 0o644, 0o2775},
+		{"u+x", false, 0o644, 0o744},
+		{"go-w", false, 0o644, 0o644},
+		{"a=rX", false, 0o644, 0o444},
+		{"u=rwx,g=rx,o=", false, 0o644, 0o750},
+		{"+t", false, 0o644, 0o1644},
+		{"u+s", false, 0o644, 0o4644},
+		{"g+s", false, 0o644, 0o2644},
+		{"o=u", false, 0o644, 0o646},
+		{"go+u", false, 0o644, 0o666},
+		{"u=r+w", false, 0o644, 0o644},
+		{"+X", false, 0o644, 0o644},
+		{"0", false, 0o644, 0},
+		{"a=rX", false, 0o755, 0o555},
+		{"g=o", false, 0o755, 0o755},
+		{"a-x", false, 0o755, 0o644},
+		{"+X", false, 0o755, 0o755},
+		{"755", false, 0o4755, 0o755},
+		{"u+x", false, 0o4755, 0o4755},
+		{"go-w", false, 0o4755, 0o4755},
+		{"u-s", false, 0o4755, 0o755},
+		{"a=rX", false, 0o600, 0o444},
+		{"g=o", false, 0o600, 0o600},
+		{"go+u", false, 0o600, 0o666},
+		{"0644", true, 0o755, 0o644},
+		{"2775", true, 0o755, 0o2775},
+		{"a=rX", true, 0o755, 0o555},
+		{"u=rwx,g=rx,o=", true, 0o755, 0o750},
+		{"+X", true, 0o755, 0o755},
+		{"755", true, 0o2775, 0o2755},
+		{"0644", true, 0o2775, 0o2644},
+		{"go-w", true, 0o2775, 0o2755},
+		{"a=rX", true, 0o2775, 0o2555},
+		{"u=rwx,g=rx,o=", true, 0o2775, 0o2750},
+		{"g-s", true, 0o2775, 0o775},
+		{"4755", true, 0o2775, 0o6755},
+		{"0", true, 0o2775, 0o2000},
+		{"a=rX", true, 0o700, 0o555},
+		{"go+u", true, 0o700, 0o777},
+		{"+X", true, 0o700, 0o711},
+		{"755", true, 0o1777, 0o755},
+		{"go-w", true, 0o1777, 0o1755},
+		{"u-s", true, 0o1777, 0o1777},
+		{"o=u", true, 0o1777, 0o777},
+		{"a-x", true, 0o1777, 0o1666},
+	}
+	for _, tt := range tests {
+		spec := mustParseMode(t, tt.mode)
+		got := unixPerm(spec.Apply(toFileMode(tt.cur), tt.isDir))
+		if got != tt.want {
+			t.Errorf("chmod %s (dir:%v, %04o) = %04o, want %04o", tt.mode, tt.isDir, tt.cur, got, tt.want)
+		}
+	}
+}
+
+func TestParseModeInvalid(t *testing.T) {
+	for _, text := range []string{"", "8", "778", "77777", "0o755", "rwx", "u", "u+z", "u+x,", ",u+x", "ug", "a+rwq", "u*x"} {
+		if _, err := ParseMode(text); !errors.Is(err, ErrInvalid) {
+			t.Errorf("ParseMode(%q) error = %v, want ErrInvalid", text, err)
+		}
+	}
+}
+
+func TestChangePermSymlinkChild(t *testing.T) {
+	dir := t.TempDir()
+	tree := filepath.Join(dir, "tree")
+	outside := filepath.Join(dir, "outside")
+	writeTree(t, map[string]string{
+		filepath.Join(tree, "a"):        "a",
+		filepath.Join(tree, "sub", "b"): "b",
+		outside:                         "secret",
+	})
+	if err := os.Chmod(outside, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(tree, "link")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(dir, filepath.Join(tree, "sub", "dirlink")); err != nil {
+		t.Fatal(err)
+	}
+
+	change := &PermChange{Mode: mustParseMode(t, "a+rwx"), Uid: -1, Gid: -1, Recursive: true}
+	// root로 실행하는 경우 소유자 변경도 확인
+	uid := uint32(os.Getuid())
+	if uid == 0 {
+		change.Uid = 4321
+		uid = 4321
+	}
+
+	summary, err := ChangePerm(context.Background(), []string{tree}, change)
+	if err != nil {
+		t.Fatalf("ChangePerm() error = %s", err)
+	}
+	if summary.Failed != 0 {
+		t.Fatalf("ChangePerm() failed = %d, results = %+v", summary.Failed, summary.Results)
+	}
+
+	for _, path := range []string{tree, filepath.Join(tree, "a"), filepath.Join(tree, "sub"), filepath.Join(tree, "sub", "b")} {
+		if perm, owner := statOwner(t, path); perm != 0o777 || owner != uid {
+			t.Errorf("%s = %o (uid:%d), want 777 (uid:%d)", path, perm, owner, uid)
+		}
+	}
+
+	// 링크 대상은 변경되지 않음
+	for _, path := range []string{outside, dir} {
+		perm, owner := statOwner(t, path)
+		if path == outside && perm != 0o600 {
+			t.Errorf("symlink target %s mode = %o, want 600", path, perm)
+		}
+		if owner != uint32(os.Getuid()) {
+			t.Errorf("symlink target %s uid = %d, want %d", path, owner, os.Getuid())
+		}
+	}
+	// 링크 자체의 소유자만 변경
+	if _, owner := statOwner(t, filepath.Join(tree, "link")); owner != uid {
+		t.Errorf("symlink uid = %d, want %d", owner, uid)
+	}
+}
+
+func TestChangePermDeferredDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, map[string]string{filepath.Join(dir, "sub", "a"): "a"})
+	t.Cleanup(func() { os.Chmod(filepath.Join(dir, "sub"), 0o755) })
+
+	// 디렉터리의 읽기/실행 권한을 제거하더라도 하위 항목을 먼저 변경
+	change := &PermChange{DirMode: mustParseMode(t, "000"), FileMode: mustParseMode(t, "640"), Uid: -1, Gid: -1, Recursive: true}
+	summary, err := ChangePerm(context.Background(), []string{filepath.Join(dir, "sub")}, change)
+	if err != nil || summary.Failed != 0 {
+		t.Fatalf("ChangePerm() = %+v, %v", summary, err)
+	}
+	if perm, _ := statOwner(t, filepath.Join(dir, "sub")); perm != 0 {
+		t.Errorf("directory mode = %o, want 0", perm)
+	}
+	os.Chmod(filepath.Join(dir, "sub"), 0o755)
+	if perm, _ := statOwner(t, filepath.Join(dir, "sub", "a")); perm != 0o640 {
+		t.Errorf("file mode = %o, want 640", perm)
+	}
+}
+
+func TestChangePermNotFound(t *testing.T) {
+	change := &PermChange{Mode: mustParseMode(t, "644"), Uid: -1, Gid: -1}
+	summary, err := ChangePerm(context.Background(), []string{filepath.Join(t.TempDir(), "missing")}, change)
+	if err != nil || summary.Failed != 1 || summary.Results[0].Error == "" {
+		t.Fatalf("ChangePerm() = %+v, %v, want one failed result", summary, err)
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package file
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"unicode/utf8"
+)
+
+// 텍스트 인코딩 판별 결과
+const (
+	EncodingUTF8    = "utf-8"
+	EncodingUTF8BOM = "utf-8-bom"
+	EncodingUTF16LE = "utf-16le"
+	EncodingUTF16BE = "utf-16be"
+	EncodingBinary  = "binary"
+	EncodingUnknown = "unknown"
+)
+
+// BOM 바이트 순서 표식
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// 바이너리 판별 시 검사할 앞부분 크기
+const binarySniffSize = 8000
+
+// DetectEncoding 파일 내용의 인코딩 판별
+//
+// BOM이 있으면 BOM으로 판별하고, 앞부분에 NUL 문자가 있으면 바이너리, 유효한 UTF-8이면
+// UTF-8, 그 외(EUC-KR, Latin-1 등)는 unknown으로 판별한다.
+//
+// Parameters:
+//   - data: 파일 내용
+//
+// Returns:
+//   - string: 인코딩 (Encoding* 상수)
+func DetectEncoding(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, bomUTF8):
+		if utf8.Valid(data[len(bomUTF8):]) {
+			return EncodingUTF8BOM
+		}
+		return EncodingUnknown
+	case bytes.HasPrefix(data, bomUTF16LE):
+		return EncodingUTF16LE
+	case bytes.HasPrefix(data, bomUTF16BE):
+		return EncodingUTF16BE
+	}
+
+	if bytes.IndexByte(data[:min(len(data), binarySniffSize)], 0) >= 0 {
+		return EncodingBinary
+	}
+	if utf8.Valid(data) {
+		return EncodingUTF8
+	}
+	return EncodingUnknown
+}
+
+// IsEditableEncoding 웹 편집기에서 편집할 수 있는 인코딩인지 확인
+//
+// Parameters:
+//   - encoding: 인코딩
+//
+// Returns:
+//   - bool: 편집 가능(true), 불가(false)
+func IsEditableEncoding(encoding string) bool {
+	return encoding == EncodingUTF8 || encoding == EncodingUTF8BOM
+}
+
+// DecodeText 편집 가능한 인코딩의 파일 내용에서 BOM을 제거한 텍스트 반환
+//
+// Parameters:
+//   - data: 파일 내용
+//   - encoding: 인코딩
+//
+// Returns:
+//   - string: 텍스트
+func DecodeText(data []byte, encoding string) string {
+	if encoding == EncodingUTF8BOM {
+		data = data[len(bomUTF8):]
+	}
+	return string(data)
+}
+
+// EncodeText 텍스트를 지정한 인코딩의 파일 내용으로 변환
+//
+// Parameters:
+//   - text: UTF-8 텍스트
+//   - encoding: 인코딩 (utf-8, utf-8-bom)
+//
+// Returns:
+//   - []byte: 파일 내용
+//   - error: 성공(nil), 실패(ErrInvalid)
+func EncodeText(text []byte, encoding string) ([]byte, error) {
+	if !utf8.Valid(text) {
+		return nil, fmt.Errorf("text is not valid utf-8: %w", ErrInvalid)
+	}
+	switch encoding {
+	case "", EncodingUTF8:
+		return text, nil
+	case EncodingUTF8BOM:
+		if bytes.HasPrefix(text, bomUTF8) {
+			return text, nil
+		}
+		return append(append([]byte{}, bomUTF8...), text...), nil
+	default:
+		return nil, fmt.Errorf("unsupported text encoding %q: %w", encoding, ErrInvalid)
+	}
+}
+
+// ContentETag 파일 수정 시각, 크기, 내용 해시로 ETag 생성
+//
+// 수정 시각과 크기가 같더라도 내용이 바뀌었으면 다른 값이 되므로 편집 충돌 검사에 사용한다.
+//
+// Parameters:
+//   - fi: 파일 정보
+//   - data: 파일 내용
+//
+// Returns:
+//   - string: ETag
+func ContentETag(fi fs.FileInfo, data []byte) string {
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf(`"%x-%x-%x"`, fi.ModTime().UnixNano(), fi.Size(), sum[:12])
+}
+
+// WriteFileAtomic 파일 내용을 원자적으로 교체 (권한, 소유자 유지)
+//
+// 같은 디렉터리에 임시 파일을 작성한 후 rename하므로 읽는 쪽에서 작성 중인 내용이 보이지 않는다.
+// 심볼릭 링크는 링크 대상 파일을 교체한다. 디렉터리에 쓰기 권한이 없거나, 소유자를 유지할 수
+// 없거나, 하드 링크가 있는 파일은 원자성 대신 기존 파일에 직접 기록하여 소유자와 링크를 유지한다.
+//
+// Parameters:
+//   - path: 파일 경로
+//   - data: 파일 내용
+//   - perm: 새로 생성하는 경우의 권한
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func WriteFileAtomic(path string, data []byte, perm fs.FileMode) error {
+	target, err := filepath.EvalSymlinks(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		// 새 파일 (끊어진 심볼릭 링크는 링크 자체를 덮어쓰지 않도록 거부)
+		if _, lerr := os.Lstat(path); lerr == nil {
+			return &os.PathError{Op: "write", Path: path, Err: syscall.EEXIST}
+		}
+		return writeNewFile(path, data, perm)
+	}
+	if err != nil {
+		return err
+	}
+
+	fi, err := os.Stat(target)
+	if err != nil {
+		return err
+	}
+	if !fi.Mode().IsRegular() {
+		return &os.PathError{Op: "write", Path: path, Err: syscall.EINVAL}
+	}
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok || st.Nlink > 1 {
+		return writeInPlace(target, data)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".weblin-*")
+	if err != nil {
+		if errors.Is(err, fs.ErrPermission) || errors.Is(err, syscall.EROFS) {
+			return writeInPlace(target, data)
+		}
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	// 소유자 변경 시 setuid/setgid 비트가 해제되므로 소유자를 먼저 적용
+	if err := tmp.Chown(int(st.Uid), int(st.Gid)); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		if errors.Is(err, fs.ErrPermission) {
+			return writeInPlace(target, data)
+		}
+		return err
+	}
+
+	err = writeAndSync(tmp, data)
+	if err == nil {
+		err = os.Chmod(tmpPath, fi.Mode()&(fs.ModePerm|fs.ModeSetuid|fs.ModeSetgid|fs.ModeSticky))
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, target)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// writeNewFile 새 파일을 임시 파일 작성 후 생성 (이미 존재하면 ErrExists)
+//
+// Parameters:
+//   - path: 파일 경로
+//   - data: 파일 내용
+//   - perm: 파일 권한
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func writeNewFile(path string, data []byte, perm fs.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".weblin-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	err = writeAndSync(tmp, data)
+	if err == nil {
+		err = os.Chmod(tmpPath, perm)
+	}
+	if err == nil {
+		err = ReplaceFile(tmpPath, path, false)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// writeInPlace 기존 파일에 직접 기록
+//
+// Parameters:
+//   - path: 파일 경로
+//   - data: 파일 내용
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func writeInPlace(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0)
+	if err != nil {
+		return err
+	}
+	return writeAndSync(f, data)
+}
+
+// writeAndSync 파일에 기록 후 디스크 동기화 및 닫기
+//
+// Parameters:
+//   - f: 파일
+//   - data: 파일 내용
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func writeAndSync(f *os.File, data []byte) error {
+	_, err := f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -22,7 +22,9 @@ package goroutine
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -33,6 +35,8 @@ type GoroutineManager struct {
 	parentCtx    context.Context
 	parentCancel context.CancelFunc
 	tasks        map[string]*taskWrapper
+	// 가동 중인 고루틴 수
+	running atomic.Int64
 }
 
 // taskWrapper 개별 고루틴 관리 정보 구조체
@@ -41,6 +45,17 @@ type taskWrapper struct {
 	childCtx    context.Context
 	childCancel context.CancelFunc
 	task        func(ctx context.Context)
+	// 가동 여부 및 마지막 가동 시각 (startedAt은 GoroutineManager.mu로 보호)
+	running   atomic.Bool
+	startedAt time.Time
+}
+
+// TaskStatus 작업 상태 정보 구조체
+type TaskStatus struct {
+	Name    string `json:"name"`
+	Running bool   `json:"running"`
+	// 마지막 가동 시각 (가동한 적 없을 경우 nil)
+	StartedAt *time.Time `json:"startedAt,omitempty"`
 }
 
 // NewGoroutineManager 고루틴 관리 구조체 생성
@@ -106,22 +121,33 @@ func (gm *GoroutineManager) StartAll() {
 	defer gm.mu.Unlock()
 
 	for _, t := range gm.tasks {
-		gm.parentWG.Add(1)
-		t.childWG.Add(1)
-		// 포인터를 인자 값으로 넘겨주지만, 해당 주소값은 캡쳐되어
-		// go 익명 함수 내부에서 바뀌지 않음
-		go func(tw *taskWrapper) {
-			defer func() {
-				tw.childWG.Done()
-				gm.parentWG.Done()
-			}()
-
-			// 작업 가동
-			tw.task(tw.childCtx)
-		}(t)
+		gm.launch(t)
 	}
 }
 
+// launch 고루틴 가동 (gm.mu를 획득한 상태에서 호출)
+//
+// Parameters:
+//   - t: 작업 정보
+func (gm *GoroutineManager) launch(t *taskWrapper) {
+	gm.parentWG.Add(1)
+	t.childWG.Add(1)
+	gm.running.Add(1)
+	t.running.Store(true)
+	t.startedAt = time.Now()
+	go func() {
+		defer func() {
+			t.running.Store(false)
+			gm.running.Add(-1)
+			t.childWG.Done()
+			gm.parentWG.Done()
+		}()
+
+		// 작업 가동
+		t.task(t.childCtx)
+	}()
+}
+
 // StopAll 작업에 등록된 모든 고루틴 가동 정지
 //
 // Parameters:
@@ -158,17 +184,7 @@ func (gm *GoroutineManager) Start(name string) error {
 		return fmt.Errorf("task does not exist (%s)", name)
 	}
 
-	gm.parentWG.Add(1)
-	t.childWG.Add(1)
-	go func() {
-		defer func() {
-			t.childWG.Done()
-			gm.parentWG.Done()
-		}()
-
-		// 작업 가동
-		t.task(t.childCtx)
-	}()
+	gm.launch(t)
 
 	return nil
 }
@@ -194,3 +210,37 @@ func (gm *GoroutineManager) Stop(name string, timeout time.Duration) error {
 	}
 	return nil
 }
+
+// Count 등록된 작업 수와 가동 중인 고루틴 수 반환
+//
+// Returns:
+//   - int: 등록된 작업 수
+//   - int: 가동 중인 고루틴 수
+func (gm *GoroutineManager) Count() (int, int) {
+	gm.mu.Lock()
+	registered := len(gm.tasks)
+	gm.mu.Unlock()
+
+	return registered, int(gm.running.Load())
+}
+
+// Tasks 등록된 작업별 상태 반환 (작업명 순)
+//
+// Returns:
+//   - []TaskStatus: 작업별 상태
+func (gm *GoroutineManager) Tasks() []TaskStatus {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	tasks := make([]TaskStatus, 0, len(gm.tasks))
+	for name, t := range gm.tasks {
+		status := TaskStatus{Name: name, Running: t.running.Load()}
+		if !t.startedAt.IsZero() {
+			startedAt := t.startedAt
+			status.StartedAt = &startedAt
+		}
+		tasks = append(tasks, status)
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].Name < tasks[j].Name })
+	return tasks
+}
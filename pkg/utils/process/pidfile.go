@@ -0,0 +1,206 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package process
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// ErrPidFileLocked 다른 프로세스가 PID 파일 잠금을 보유 중
+var ErrPidFileLocked = errors.New("pid file is locked by another process")
+
+// PidFile 잠금을 보유한 PID 파일 정보 구조체
+//
+// 프로세스가 종료되면(비정상 종료 포함) 커널이 잠금을 해제하므로,
+// 잠금 여부로 PID 파일을 기록한 프로세스가 동작 중인지 판단할 수 있다.
+type PidFile struct {
+	path string
+	file *os.File
+}
+
+// LockPidFile PID 파일을 생성하여 배타적 잠금(flock)을 획득하고 PID 기록
+//
+// 잠금은 Release 호출 또는 프로세스 종료 전까지 유지된다.
+//
+// Parameters:
+//   - path: PID 파일 경로
+//   - pid: 기록할 PID
+//
+// Returns:
+//   - *PidFile: PID 파일 정보
+//   - error: 성공(nil), 다른 프로세스가 잠금 보유(ErrPidFileLocked), 실패(error)
+func LockPidFile(path string, pid int) (*PidFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, fmt.Errorf("failed to make directory: %s", err)
+	}
+
+	for {
+		file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open pid file: %s", err)
+		}
+
+		if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+			file.Close()
+			if errors.Is(err, syscall.EWOULDBLOCK) {
+				return nil, ErrPidFileLocked
+			}
+			return nil, fmt.Errorf("failed to lock pid file: %s", err)
+		}
+
+		// 잠금 획득 전에 다른 프로세스가 오래된 PID 파일을 삭제한 경우
+		// 삭제된 파일에 기록하지 않도록 경로의 파일과 같은지 확인 후 다시 시도
+		if !sameFile(file, path) {
+			file.Close()
+			continue
+		}
+
+		if err := file.Truncate(0); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to truncate pid file: %s", err)
+		}
+		if _, err := file.WriteAt([]byte(strconv.Itoa(pid)), 0); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to write pid file: %s", err)
+		}
+
+		return &PidFile{path: path, file: file}, nil
+	}
+}
+
+// Release PID 파일 삭제 및 잠금 해제
+//
+// 다른 프로세스가 새로 기록한 PID 파일을 삭제하지 않도록 잠금을 보유한 상태에서 삭제한다.
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func (p *PidFile) Release() error {
+	if p == nil || p.file == nil {
+		return nil
+	}
+
+	err := os.Remove(p.path)
+	p.file.Close()
+	p.file = nil
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove pid file: %s", err)
+	}
+	return nil
+}
+
+// ReadPidFile PID 파일을 기록한 프로세스가 동작 중일 경우 PID 반환
+//
+// 잠금을 보유한 프로세스가 없는 PID 파일(비정상 종료 등으로 남은 파일)은 삭제한다.
+//
+// Parameters:
+//   - path: PID 파일 경로
+//
+// Returns:
+//   - int: 동작 중인 프로세스의 PID (동작 중이지 않을 경우 0)
+//   - error: 성공(nil), 실패(error)
+func ReadPidFile(path string) (int, error) {
+	// flock은 파일 열기 모드와 무관하므로 읽기 권한만으로 잠금 여부 확인
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to open pid file: %s", err)
+	}
+	defer file.Close()
+
+	// 잠금을 획득할 수 있으면 기록한 프로세스가 종료된 것이므로 오래된 PID 파일 삭제
+	err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if err == nil {
+		if sameFile(file, path) {
+			os.Remove(path)
+		}
+		return 0, nil
+	}
+	if !errors.Is(err, syscall.EWOULDBLOCK) {
+		return 0, fmt.Errorf("failed to check pid file lock: %s", err)
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read pid file: %s", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("invalid pid file content: %q", data)
+	}
+
+	return pid, nil
+}
+
+// IsSameExecutable 프로세스가 현재 프로세스와 같은 실행 파일로 실행되었는지 확인
+//
+// /proc/<pid>/exe를 우선 확인하며, 권한이 없어 읽을 수 없을 경우 /proc/<pid>/cmdline의
+// 실행 파일 이름으로 확인한다. 실행 중 교체(업그레이드)된 실행 파일도 같은 이름이면 같은 것으로 본다.
+//
+// Parameters:
+//   - pid
+//
+// Returns:
+//   - bool: 같은 실행 파일(true), 다른 실행 파일 또는 확인 불가(false)
+func IsSameExecutable(pid int) bool {
+	self, err := os.Executable()
+	if err != nil {
+		return false
+	}
+	name := filepath.Base(self)
+
+	procPath := fmt.Sprintf("/proc/%d", pid)
+	if exe, err := os.Readlink(procPath + "/exe"); err == nil {
+		return filepath.Base(strings.TrimSuffix(exe, " (deleted)")) == name
+	}
+
+	cmdline, err := os.ReadFile(procPath + "/cmdline")
+	if err != nil || len(cmdline) == 0 {
+		return false
+	}
+	argv0, _, _ := bytes.Cut(cmdline, []byte{0})
+	return filepath.Base(string(argv0)) == name
+}
+
+// sameFile 열린 파일이 경로의 파일과 같은지 확인 (삭제 또는 교체 여부)
+//
+// Parameters:
+//   - file: 열린 파일
+//   - path: 파일 경로
+//
+// Returns:
+//   - bool: 같은 파일(true), 다른 파일 또는 삭제됨(false)
+func sameFile(file *os.File, path string) bool {
+	openInfo, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	pathInfo, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(openInfo, pathInfo)
+}
@@ -0,0 +1,548 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package process
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+const (
+	// procPath proc 파일 시스템 경로
+	procPath = "/proc"
+	// defaultClockTicks 초당 클럭 틱 수를 조회할 수 없을 경우 사용할 Linux 기본값
+	defaultClockTicks = 100
+	// atClkTck 보조 벡터(auxv)의 초당 클럭 틱 수 항목 (AT_CLKTCK)
+	atClkTck = 17
+)
+
+// baselineTTL 조회하지 않는 호출자의 CPU 사용률 기준 유지 시간
+const baselineTTL = 10 * time.Minute
+
+// clockTicks 초당 클럭 틱 수 (sysconf(_SC_CLK_TCK)와 같은 값, 처음 사용 시 한 번 조회)
+var clockTicks = sync.OnceValue(readClockTicks)
+
+// Process 프로세스 정보 구조체
+type Process struct {
+	PID     int    `json:"pid"`
+	PPID    int    `json:"ppid"`
+	UID     uint32 `json:"uid"`
+	User    string `json:"user"`
+	Name    string `json:"name"`
+	Cmdline string `json:"cmdline"`
+	// 상태 (R: 실행, S: 대기, D: 디스크 대기, Z: 좀비, T: 정지, I: 유휴 등)
+	State string `json:"state"`
+	// CPU 사용률 (단일 코어 기준 %, 멀티 스레드 프로세스는 100을 넘을 수 있음)
+	CPUPercent float64 `json:"cpuPercent"`
+	// 실제 메모리 사용량 (바이트)
+	RSS       int64     `json:"rss"`
+	StartTime time.Time `json:"startTime"`
+	Threads   int       `json:"threads"`
+	Nice      int       `json:"nice"`
+	// 트리 보기 시 자식 프로세스
+	Children []*Process `json:"children,omitempty"`
+
+	// CPU 사용률 계산용 누적 CPU 시간(틱) 및 시작 시각(부팅 후 틱)
+	cpuTicks   uint64
+	startTicks uint64
+}
+
+// sample 이전 조회 시점의 프로세스 CPU 시간
+type sample struct {
+	cpuTicks   uint64
+	startTicks uint64
+}
+
+// baseline 호출자별 이전 조회 시점의 프로세스 CPU 시간
+type baseline struct {
+	samples map[int]sample
+	time    time.Time
+}
+
+// Sampler 조회 간 CPU 시간 차이로 프로세스별 CPU 사용률을 계산하는 구조체
+//
+// CPU 사용률은 같은 호출자의 이전 조회 이후 사용률이므로 다른 호출자의 조회 주기에 영향을 받지 않는다.
+// 처음 조회하는 프로세스는 ps와 같이 시작 이후 평균 사용률을 사용한다.
+type Sampler struct {
+	mu        sync.Mutex
+	baselines map[string]*baseline
+	names     map[uint32]string
+}
+
+// NewSampler 프로세스 CPU 사용률 계산 구조체 생성
+//
+// Returns:
+//   - *Sampler
+func NewSampler() *Sampler {
+	return &Sampler{
+		baselines: make(map[string]*baseline),
+		names:     make(map[uint32]string),
+	}
+}
+
+// List 모든 프로세스 정보 조회 (PID 순 정렬)
+//
+// Parameters:
+//   - caller: 호출자 식별자 (로그인 세션 등, 호출자별로 CPU 사용률 기준을 유지)
+//
+// Returns:
+//   - []*Process: 프로세스 목록
+//   - error: 성공(nil), 실패(error)
+func (s *Sampler) List(caller string) ([]*Process, error) {
+	entries, err := os.ReadDir(procPath)
+	if err != nil {
+		return nil, err
+	}
+	boot, err := bootTime()
+	if err != nil {
+		return nil, err
+	}
+	uptime, err := uptimeSeconds()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.pruneBaselines(now)
+	prevBase, ok := s.baselines[caller]
+	if !ok {
+		prevBase = &baseline{}
+	}
+	elapsed := now.Sub(prevBase.time).Seconds()
+	current := make(map[int]sample, len(entries))
+	procs := make([]*Process, 0, len(entries))
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+
+		p, err := readProcess(pid, boot)
+		if err != nil {
+			// 조회 도중 종료된 프로세스는 제외
+			continue
+		}
+		p.User = s.userName(p.UID)
+
+		prev, ok := prevBase.samples[pid]
+		if ok && prev.startTicks == p.startTicks && elapsed > 0 && p.cpuTicks >= prev.cpuTicks {
+			p.CPUPercent = float64(p.cpuTicks-prev.cpuTicks) / float64(clockTicks()) / elapsed * 100
+		} else if running := uptime - float64(p.startTicks)/float64(clockTicks()); running > 0 {
+			p.CPUPercent = float64(p.cpuTicks) / float64(clockTicks()) / running * 100
+		}
+		p.CPUPercent = float64(int64(p.CPUPercent*10+0.5)) / 10
+
+		current[pid] = sample{cpuTicks: p.cpuTicks, startTicks: p.startTicks}
+		procs = append(procs, p)
+	}
+
+	s.baselines[caller] = &baseline{samples: current, time: now}
+
+	// 디렉터리 이름은 문자열 순으로 정렬되므로 PID 순으로 재정렬
+	sort.Slice(procs, func(i, j int) bool { return procs[i].PID < procs[j].PID })
+	return procs, nil
+}
+
+// pruneBaselines 오랫동안 조회하지 않은 호출자의 CPU 사용률 기준 삭제 (s.mu 잠금 상태에서 호출)
+//
+// Parameters:
+//   - now: 현재 시각
+func (s *Sampler) pruneBaselines(now time.Time) {
+	for caller, base := range s.baselines {
+		if now.Sub(base.time) > baselineTTL {
+			delete(s.baselines, caller)
+		}
+	}
+}
+
+// userName uid에 해당하는 계정 이름 반환 (s.mu 잠금 상태에서 호출)
+//
+// Parameters:
+//   - uid: 계정 ID
+//
+// Returns:
+//   - string: 계정 이름 (없을 경우 uid 문자열)
+func (s *Sampler) userName(uid uint32) string {
+	if name, ok := s.names[uid]; ok {
+		return name
+	}
+	name := strconv.FormatUint(uint64(uid), 10)
+	if u, err := user.LookupId(name); err == nil {
+		name = u.Username
+	}
+	s.names[uid] = name
+	return name
+}
+
+// ReadProcess 프로세스 하나의 정보 조회 (CPU 사용률 제외)
+//
+// Parameters:
+//   - pid: 프로세스 ID
+//
+// Returns:
+//   - *Process: 프로세스 정보
+//   - error: 성공(nil), 실패(fs.ErrNotExist 등)
+func ReadProcess(pid int) (*Process, error) {
+	boot, err := bootTime()
+	if err != nil {
+		return nil, err
+	}
+	p, err := readProcess(pid, boot)
+	if err != nil {
+		return nil, err
+	}
+	p.User = strconv.FormatUint(uint64(p.UID), 10)
+	if u, err := user.LookupId(p.User); err == nil {
+		p.User = u.Username
+	}
+	return p, nil
+}
+
+// readProcess /proc/<pid>의 stat, status, cmdline 파일로 프로세스 정보 생성
+//
+// Parameters:
+//   - pid: 프로세스 ID
+//   - boot: 시스템 부팅 시각
+//
+// Returns:
+//   - *Process: 프로세스 정보
+//   - error: 성공(nil), 실패(error)
+func readProcess(pid int, boot time.Time) (*Process, error) {
+	dir := procPath + "/" + strconv.Itoa(pid)
+	stat, err := os.ReadFile(dir + "/stat")
+	if err != nil {
+		return nil, err
+	}
+
+	// 프로세스 이름에 공백이나 괄호가 포함될 수 있으므로 마지막 ')' 기준으로 분리
+	open := bytes.IndexByte(stat, '(')
+	end := bytes.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return nil, fmt.Errorf("invalid stat format (pid:%d)", pid)
+	}
+	fields := strings.Fields(string(stat[end+1:]))
+	// fields[0]은 stat의 3번째 항목(state)이며, 22번째 항목(starttime)까지 필요
+	if len(fields) < 22 {
+		return nil, fmt.Errorf("invalid stat format (pid:%d)", pid)
+	}
+	field := func(n int) int64 {
+		v, _ := strconv.ParseInt(fields[n-3], 10, 64)
+		return v
+	}
+
+	p := &Process{
+		PID:        pid,
+		PPID:       int(field(4)),
+		Name:       string(stat[open+1 : end]),
+		State:      fields[0],
+		Nice:       int(field(19)),
+		Threads:    int(field(20)),
+		RSS:        field(24) * int64(os.Getpagesize()),
+		cpuTicks:   uint64(field(14) + field(15)),
+		startTicks: uint64(field(22)),
+	}
+	p.StartTime = boot.Add(time.Duration(p.startTicks) * time.Second / time.Duration(clockTicks()))
+
+	uid, err := readUID(dir + "/status")
+	if err != nil {
+		return nil, err
+	}
+	p.UID = uid
+
+	cmdline, err := readCmdline(dir)
+	if err != nil && !errors.Is(err, fs.ErrPermission) {
+		return nil, err
+	}
+	p.SetCmdline(cmdline)
+
+	return p, nil
+}
+
+// SetCmdline 명령행 설정
+//
+// Parameters:
+//   - cmdline: 명령행 (빈 문자열일 경우 커널 스레드와 같이 이름을 괄호로 표시)
+func (p *Process) SetCmdline(cmdline string) {
+	p.Cmdline = cmdline
+	if p.Cmdline == "" {
+		// 커널 스레드는 명령행이 없으므로 ps와 같이 이름을 괄호로 표시
+		p.Cmdline = "[" + p.Name + "]"
+	}
+}
+
+// VisibleCmdlines 현재 프로세스 권한으로 조회할 수 있는 프로세스의 명령행 목록 조회
+//
+// /proc의 hidepid 옵션 등으로 조회할 수 없는 프로세스는 목록에서 제외된다.
+//
+// Returns:
+//   - map[int]string: PID별 명령행
+//   - error: 성공(nil), 실패(error)
+func VisibleCmdlines() (map[int]string, error) {
+	entries, err := os.ReadDir(procPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cmdlines := make(map[int]string, len(entries))
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+		cmdline, err := readCmdline(procPath + "/" + entry.Name())
+		if err != nil {
+			continue
+		}
+		cmdlines[pid] = cmdline
+	}
+	return cmdlines, nil
+}
+
+// readCmdline /proc/<pid>/cmdline의 명령행 조회 (인자는 공백으로 구분)
+//
+// Parameters:
+//   - dir: /proc/<pid> 경로
+//
+// Returns:
+//   - string: 명령행 (커널 스레드는 빈 문자열)
+//   - error: 성공(nil), 실패(error)
+func readCmdline(dir string) (string, error) {
+	cmdline, err := os.ReadFile(dir + "/cmdline")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(bytes.ReplaceAll(cmdline, []byte{0}, []byte{' '}))), nil
+}
+
+// readUID /proc/<pid>/status에서 유효 uid 조회
+//
+// Parameters:
+//   - path: status 파일 경로
+//
+// Returns:
+//   - uint32: 유효 uid
+//   - error: 성공(nil), 실패(error)
+func readUID(path string) (uint32, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		value, found := strings.CutPrefix(line, "Uid:")
+		if !found {
+			continue
+		}
+		// 실제, 유효, 저장, 파일 시스템 uid 순서
+		ids := strings.Fields(value)
+		if len(ids) < 2 {
+			break
+		}
+		uid, err := strconv.ParseUint(ids[1], 10, 32)
+		if err != nil {
+			break
+		}
+		return uint32(uid), nil
+	}
+	return 0, fmt.Errorf("uid not found in %s", path)
+}
+
+// readClockTicks /proc/self/auxv의 AT_CLKTCK 항목으로 초당 클럭 틱 수 조회
+//
+// cgo 없이 sysconf를 호출할 수 없으므로 커널이 전달한 보조 벡터를 직접 읽는다.
+//
+// Returns:
+//   - uint64: 초당 클럭 틱 수 (조회할 수 없을 경우 defaultClockTicks)
+func readClockTicks() uint64 {
+	data, err := os.ReadFile(procPath + "/self/auxv")
+	if err != nil {
+		return defaultClockTicks
+	}
+
+	// 보조 벡터는 (유형, 값) 쌍의 배열이며 각 항목은 워드 크기
+	wordSize := int(unsafe.Sizeof(uintptr(0)))
+	word := func(b []byte) uint64 {
+		if wordSize == 8 {
+			return binary.NativeEndian.Uint64(b)
+		}
+		return uint64(binary.NativeEndian.Uint32(b))
+	}
+	for i := 0; i+2*wordSize <= len(data); i += 2 * wordSize {
+		key, value := word(data[i:]), word(data[i+wordSize:])
+		if key == atClkTck && value > 0 {
+			return value
+		}
+		if key == 0 {
+			break
+		}
+	}
+	return defaultClockTicks
+}
+
+// bootTime /proc/stat의 btime으로 시스템 부팅 시각 조회
+//
+// Returns:
+//   - time.Time: 부팅 시각
+//   - error: 성공(nil), 실패(error)
+func bootTime() (time.Time, error) {
+	data, err := os.ReadFile(procPath + "/stat")
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, found := strings.CutPrefix(line, "btime "); found {
+			sec, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err != nil {
+				break
+			}
+			return time.Unix(sec, 0), nil
+		}
+	}
+	return time.Time{}, errors.New("btime not found in /proc/stat")
+}
+
+// uptimeSeconds /proc/uptime으로 부팅 후 경과 시간 조회
+//
+// Returns:
+//   - float64: 경과 시간(초)
+//   - error: 성공(nil), 실패(error)
+func uptimeSeconds() (float64, error) {
+	data, err := os.ReadFile(procPath + "/uptime")
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, errors.New("invalid /proc/uptime format")
+	}
+	return strconv.ParseFloat(fields[0], 64)
+}
+
+// BuildTree 프로세스 목록을 부모-자식 트리로 구성
+//
+// 부모 프로세스가 목록에 없는 프로세스(필터링된 경우 포함)는 최상위 항목이 된다.
+//
+// Parameters:
+//   - procs: 프로세스 목록
+//
+// Returns:
+//   - []*Process: 최상위 프로세스 목록 (PID 순 정렬)
+func BuildTree(procs []*Process) []*Process {
+	byPID := make(map[int]*Process, len(procs))
+	for _, p := range procs {
+		p.Children = nil
+		byPID[p.PID] = p
+	}
+
+	var roots []*Process
+	for _, p := range procs {
+		if parent, ok := byPID[p.PPID]; ok && p.PPID != p.PID {
+			parent.Children = append(parent.Children, p)
+		} else {
+			roots = append(roots, p)
+		}
+	}
+
+	sortTree(roots)
+	return roots
+}
+
+// sortTree 트리의 각 단계를 PID 순으로 정렬
+//
+// Parameters:
+//   - procs: 프로세스 목록
+func sortTree(procs []*Process) {
+	sort.Slice(procs, func(i, j int) bool { return procs[i].PID < procs[j].PID })
+	for _, p := range procs {
+		sortTree(p.Children)
+	}
+}
+
+// signalNames 시그널 이름 목록
+var signalNames = map[string]syscall.Signal{
+	"HUP":   syscall.SIGHUP,
+	"INT":   syscall.SIGINT,
+	"QUIT":  syscall.SIGQUIT,
+	"KILL":  syscall.SIGKILL,
+	"USR1":  syscall.SIGUSR1,
+	"USR2":  syscall.SIGUSR2,
+	"ALRM":  syscall.SIGALRM,
+	"TERM":  syscall.SIGTERM,
+	"CONT":  syscall.SIGCONT,
+	"STOP":  syscall.SIGSTOP,
+	"TSTP":  syscall.SIGTSTP,
+	"WINCH": syscall.SIGWINCH,
+}
+
+// ParseSignal 시그널 이름 또는 번호를 시그널로 변환 (예: "TERM", "SIGKILL", "9")
+//
+// Parameters:
+//   - name: 시그널 이름 또는 번호
+//
+// Returns:
+//   - syscall.Signal: 시그널
+//   - error: 성공(nil), 실패(error)
+func ParseSignal(name string) (syscall.Signal, error) {
+	if num, err := strconv.Atoi(name); err == nil {
+		if num < 1 || num > 64 {
+			return 0, fmt.Errorf("invalid signal number: %d", num)
+		}
+		// 32, 33은 glibc(NPTL)가 내부적으로 사용하는 실시간 시그널
+		if num == 32 || num == 33 {
+			return 0, fmt.Errorf("signal %d is reserved by the C library", num)
+		}
+		return syscall.Signal(num), nil
+	}
+
+	upper := strings.TrimPrefix(strings.ToUpper(name), "SIG")
+	if sig, ok := signalNames[upper]; ok {
+		return sig, nil
+	}
+	return 0, fmt.Errorf("unknown signal: %s", name)
+}
+
+// SetNice 프로세스 우선순위(nice) 변경
+//
+// Parameters:
+//   - pid: 프로세스 ID
+//   - nice: nice 값 (-20 ~ 19)
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func SetNice(pid, nice int) error {
+	if nice < -20 || nice > 19 {
+		return fmt.Errorf("nice value out of range: %d: %w", nice, syscall.EINVAL)
+	}
+	if err := syscall.Setpriority(syscall.PRIO_PROCESS, pid, nice); err != nil {
+		return fmt.Errorf("failed to set priority: %w", err)
+	}
+	return nil
+}
@@ -20,45 +20,172 @@ Package process 프로세스 처리 범용 패키지
 package process
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"sync"
 	"syscall"
 )
 
+// 데몬 프로세스 생성 단계 (환경 변수로 자식 프로세스에 전달하는 표식)
+const (
+	// daemonStageEnv 데몬 프로세스 생성 단계 환경 변수 이름
+	daemonStageEnv = "DAEMONIZE_STAGE"
+	// stageSession 새 세션의 리더 프로세스 (데몬 프로세스 생성 후 바로 종료)
+	stageSession = "session"
+	// stageDaemon 데몬 프로세스
+	stageDaemon = "daemon"
+)
+
+const (
+	// readyFd 가동 완료 알림 파이프의 파일 디스크립터 (stdin, stdout, stderr 다음)
+	readyFd = 3
+	// readyMessage 가동 완료 알림 메시지 (그 외 메시지는 가동 실패 사유)
+	readyMessage = "READY"
+	// daemonUmask 데몬 프로세스 umask
+	daemonUmask = 0022
+)
+
+// startDir 프로세스 가동 시 작업 경로 (명령행의 상대 경로 기준)
+var startDir, _ = os.Getwd()
+
+var (
+	// readyPipe 부모 프로세스에 가동 결과를 알릴 파이프 (데몬 프로세스가 아닐 경우 nil)
+	readyPipe *os.File
+	readyOnce sync.Once
+)
+
 // DaemonizeProcess 데몬 프로세스 생성
 //
+// 실행 파일을 두 번 다시 실행하여(double-fork) 새 세션의 리더가 아닌 데몬 프로세스를 생성한다.
+// 부모 프로세스는 데몬 프로세스가 NotifyReady로 가동 결과를 알릴 때까지 대기하며,
+// 가동에 성공하면 종료(exit 0)하고 실패하면 데몬 프로세스가 알린 오류를 반환한다.
+// 데몬 프로세스는 umask를 설정하고 현재 작업 경로에서 시작하며, keepStdio가 false일 경우
+// 표준 입출력은 /dev/null로 연결된다.
+//
+// Parameters:
+//   - keepStdio: 데몬 프로세스의 stdout, stderr를 현재 프로세스와 공유 (디버그 모드)
+//
 // Returns:
-//   - error: 성공(nil), 실패(error)
-func DaemonizeProcess() error {
-	// PID가 1인 경우 이미 데몬 프로세스임
-	if os.Getppid() != 1 {
-		// 현재 프로세스의 절대 경로 획득
-		exePath, err := os.Executable()
-		if err != nil {
-			return fmt.Errorf("failed to get absolute path: %s", err)
-		}
+//   - error: 데몬 프로세스에서 성공(nil), 실패(error)
+func DaemonizeProcess(keepStdio bool) error {
+	switch os.Getenv(daemonStageEnv) {
+	case stageDaemon:
+		// 데몬 프로세스에서 실행하는 프로세스(헬퍼, 터미널 등)에 표식과 파이프가 전달되지 않도록 정리
+		os.Unsetenv(daemonStageEnv)
+		syscall.CloseOnExec(readyFd)
+		readyPipe = os.NewFile(readyFd, "ready")
+		syscall.Umask(daemonUmask)
+		return nil
 
-		// 자식 프로세스 생성
-		cmd := exec.Command(exePath, os.Args[1:]...)
-		cmd.SysProcAttr = &syscall.SysProcAttr{
-			Setsid: true,
+	case stageSession:
+		// 세션 리더는 터미널을 다시 할당받을 수 있으므로 데몬 프로세스를 생성한 후 종료
+		pipe := os.NewFile(readyFd, "ready")
+		if _, err := spawnStage(stageDaemon, pipe, keepStdio); err != nil {
+			pipe.WriteString(err.Error())
+			os.Exit(1)
 		}
-		cmd.Stdin = nil
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
+		os.Exit(0)
+	}
 
-		// 데몬 프로세스 가동
-		err = cmd.Start()
-		if err != nil {
-			return fmt.Errorf("failed to start daemon process: %s", err)
-		}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("failed to create pipe: %s", err)
+	}
+	defer r.Close()
 
+	cmd, err := spawnStage(stageSession, w, keepStdio)
+	// 데몬 프로세스가 종료되면 EOF를 읽을 수 있도록 쓰기 파이프는 바로 닫음
+	w.Close()
+	if err != nil {
+		return err
+	}
+	// 세션 리더 프로세스 종료 대기 (좀비 프로세스 방지)
+	cmd.Wait()
+
+	// 데몬 프로세스의 가동 결과 대기
+	msg, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read daemon process status: %s", err)
+	}
+	switch string(msg) {
+	case readyMessage:
 		// 부모 프로세스 종료
 		os.Exit(0)
+	case "":
+		return fmt.Errorf("daemon process exited before it was ready")
+	}
+	return fmt.Errorf("daemon process failed to start: %s", msg)
+}
+
+// NotifyReady 부모 프로세스에 데몬 프로세스의 가동 결과 알림 (최초 호출만 유효)
+//
+// 데몬 프로세스가 아닐 경우 아무 동작도 하지 않는다. 데몬 프로세스가 DaemonizeProcess
+// 호출 전에 가동을 중단하는 경우에도 가동 결과를 알릴 수 있다.
+//
+// Parameters:
+//   - err: 가동 성공(nil), 가동 실패 사유(error)
+func NotifyReady(err error) {
+	readyOnce.Do(func() {
+		if readyPipe == nil {
+			if os.Getenv(daemonStageEnv) != stageDaemon {
+				return
+			}
+			readyPipe = os.NewFile(readyFd, "ready")
+		}
+		msg := readyMessage
+		if err != nil {
+			msg = err.Error()
+		}
+		readyPipe.WriteString(msg)
+		readyPipe.Close()
+	})
+}
+
+// spawnStage 데몬 프로세스 생성 단계의 자식 프로세스 가동
+//
+// Parameters:
+//   - stage: 자식 프로세스의 생성 단계
+//   - pipe: 가동 완료 알림 파이프 (자식 프로세스의 readyFd로 전달)
+//   - keepStdio: 자식 프로세스의 stdout, stderr를 현재 프로세스와 공유
+//
+// Returns:
+//   - *exec.Cmd: 가동한 자식 프로세스 정보
+//   - error: 성공(nil), 실패(error)
+func spawnStage(stage string, pipe *os.File, keepStdio bool) (*exec.Cmd, error) {
+	// 현재 프로세스의 절대 경로 획득
+	exePath, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %s", err)
+	}
+	// 명령행의 상대 경로(--config 등)를 같게 해석하도록 명령어를 실행한 경로에서 가동
+	// (자식 프로세스도 가동 후 파일 배치 방식의 작업 경로로 변경함)
+	workDir := startDir
+	if workDir == "" {
+		return nil, fmt.Errorf("failed to get work directory")
 	}
 
-	return nil
+	cmd := exec.Command(exePath, os.Args[1:]...)
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), daemonStageEnv+"="+stage)
+	cmd.ExtraFiles = []*os.File{pipe}
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setsid: stage == stageSession,
+	}
+	// 지정하지 않은 표준 입출력은 /dev/null로 연결됨
+	if keepStdio {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start daemon process: %s", err)
+	}
+
+	return cmd, nil
 }
 
 // IsProcessRun 프로세스가 동작 중인지 확인하는 함수
@@ -95,10 +222,13 @@ func SendSignal(pid int, sig syscall.Signal) error {
 		return fmt.Errorf("failed to find process: %s", err)
 	}
 
-	// 시그널 전송
+	// 시그널 전송 (errors.Is로 syscall.EPERM 등을 확인할 수 있도록 errno 유지)
 	err = proc.Signal(sig)
+	if errors.Is(err, os.ErrProcessDone) {
+		err = syscall.ESRCH
+	}
 	if err != nil {
-		return fmt.Errorf("failed to send signal: %s", err)
+		return fmt.Errorf("failed to send signal: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,220 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package sysstat
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+const (
+	// sysBlockPath 블록 장치(파티션 제외) 목록 경로
+	sysBlockPath = "/sys/block"
+	// sectorSize /proc/diskstats 섹터 단위 (장치와 무관하게 항상 512바이트)
+	sectorSize = 512
+)
+
+// pseudoFilesystems 사용량 조회에서 제외하는 가상 파일 시스템 유형
+var pseudoFilesystems = map[string]bool{
+	"proc": true, "sysfs": true, "devtmpfs": true, "devpts": true, "tmpfs": true,
+	"securityfs": true, "cgroup": true, "cgroup2": true, "pstore": true, "bpf": true,
+	"debugfs": true, "tracefs": true, "mqueue": true, "hugetlbfs": true, "configfs": true,
+	"fusectl": true, "autofs": true, "binfmt_misc": true, "rpc_pipefs": true, "nsfs": true,
+	"ramfs": true, "squashfs": true, "efivarfs": true, "selinuxfs": true, "fuse.lxcfs": true,
+}
+
+// networkFilesystems 사용량 조회에서 제외하는 네트워크 파일 시스템 유형
+// (서버가 응답하지 않을 경우 statfs 호출이 무기한 대기할 수 있음)
+var networkFilesystems = map[string]bool{
+	"nfs": true, "nfs4": true, "cifs": true, "smb3": true, "smbfs": true,
+	"fuse.sshfs": true, "9p": true, "ceph": true, "glusterfs": true,
+}
+
+// DiskStat 블록 장치 누적 I/O 통계 구조체 (/proc/diskstats)
+type DiskStat struct {
+	Name       string
+	Reads      uint64
+	ReadBytes  uint64
+	Writes     uint64
+	WriteBytes uint64
+	// I/O 처리 중이었던 누적 시간(밀리초)
+	IOTime uint64
+}
+
+// Filesystem 마운트된 파일 시스템 사용량 구조체 (바이트)
+type Filesystem struct {
+	Device     string `json:"device"`
+	MountPoint string `json:"mountPoint"`
+	Type       string `json:"type"`
+	Total      uint64 `json:"total"`
+	Used       uint64 `json:"used"`
+	// 일반 계정이 사용 가능한 공간 (root 예약 공간 제외)
+	Available   uint64  `json:"available"`
+	UsedPercent float64 `json:"usedPercent"`
+	Inodes      uint64  `json:"inodes"`
+	InodesFree  uint64  `json:"inodesFree"`
+}
+
+// ReadDiskStats 블록 장치별 누적 I/O 통계 조회
+//
+// 파티션과 loop, ram 장치는 제외하고 /sys/block에 존재하는 장치만 조회한다.
+//
+// Returns:
+//   - []DiskStat: 장치별 I/O 통계
+//   - error: 성공(nil), 실패(error)
+func ReadDiskStats() ([]DiskStat, error) {
+	data, err := os.ReadFile(procPath + "/diskstats")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read diskstats: %s", err)
+	}
+
+	// "major minor name reads merged sectors ms writes merged sectors ms in_flight io_ms ..." 형식
+	var disks []DiskStat
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 13 {
+			continue
+		}
+		name := fields[2]
+		if strings.HasPrefix(name, "loop") || strings.HasPrefix(name, "ram") || !isBlockDevice(name) {
+			continue
+		}
+
+		values := make([]uint64, 10)
+		for i := range values {
+			values[i], err = strconv.ParseUint(fields[i+3], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid diskstats format: %s", scanner.Text())
+			}
+		}
+		disks = append(disks, DiskStat{
+			Name:       name,
+			Reads:      values[0],
+			ReadBytes:  values[2] * sectorSize,
+			Writes:     values[4],
+			WriteBytes: values[6] * sectorSize,
+			IOTime:     values[9],
+		})
+	}
+
+	return disks, nil
+}
+
+// isBlockDevice 파티션이 아닌 블록 장치인지 확인
+//
+// Parameters:
+//   - name: 장치 이름 (cciss/c0d0 등 '/'가 포함된 이름은 /sys/block에서 '!'로 표기)
+//
+// Returns:
+//   - bool: 블록 장치(true), 파티션 또는 없는 장치(false)
+func isBlockDevice(name string) bool {
+	_, err := os.Stat(sysBlockPath + "/" + strings.ReplaceAll(name, "/", "!"))
+	return err == nil
+}
+
+// ReadFilesystems 마운트된 파일 시스템별 사용량 조회
+//
+// 가상 파일 시스템과 네트워크 파일 시스템은 제외하며, 같은 장치가 여러 경로에
+// 마운트된 경우(bind mount 등) 처음 마운트된 경로만 조회한다.
+//
+// Returns:
+//   - []Filesystem: 파일 시스템별 사용량
+//   - error: 성공(nil), 실패(error)
+func ReadFilesystems() ([]Filesystem, error) {
+	data, err := os.ReadFile(procPath + "/self/mounts")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mounts: %s", err)
+	}
+
+	var filesystems []Filesystem
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		// "device mountpoint type options dump pass" 형식
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		device, mountPoint, fsType := unescapeMount(fields[0]), unescapeMount(fields[1]), fields[2]
+		if pseudoFilesystems[fsType] || networkFilesystems[fsType] {
+			continue
+		}
+		if strings.HasPrefix(device, "/") {
+			if seen[device] {
+				continue
+			}
+			seen[device] = true
+		}
+
+		var stat syscall.Statfs_t
+		if err := syscall.Statfs(mountPoint, &stat); err != nil || stat.Blocks == 0 {
+			continue
+		}
+
+		blockSize := uint64(stat.Bsize)
+		total := stat.Blocks * blockSize
+		free := stat.Bfree * blockSize
+		available := stat.Bavail * blockSize
+		used := total - free
+		filesystems = append(filesystems, Filesystem{
+			Device:     device,
+			MountPoint: mountPoint,
+			Type:       fsType,
+			Total:      total,
+			Used:       used,
+			Available:  available,
+			// df와 같이 root 예약 공간을 제외한 기준으로 사용률 계산
+			UsedPercent: Percent(used, used+available),
+			Inodes:      stat.Files,
+			InodesFree:  stat.Ffree,
+		})
+	}
+
+	return filesystems, nil
+}
+
+// unescapeMount 마운트 정보의 8진수 이스케이프 문자 변환 (예: "\040" -> " ")
+//
+// Parameters:
+//   - s: 마운트 정보 필드
+//
+// Returns:
+//   - string: 변환된 문자열
+func unescapeMount(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if value, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(value))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package sysstat
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// NetDev 네트워크 인터페이스 누적 송수신 통계 구조체 (/proc/net/dev)
+type NetDev struct {
+	Name      string
+	RxBytes   uint64
+	RxPackets uint64
+	RxErrors  uint64
+	RxDropped uint64
+	TxBytes   uint64
+	TxPackets uint64
+	TxErrors  uint64
+	TxDropped uint64
+}
+
+// ReadNetDev 네트워크 인터페이스별 누적 송수신 통계 조회 (loopback 제외)
+//
+// Returns:
+//   - []NetDev: 인터페이스별 송수신 통계
+//   - error: 성공(nil), 실패(error)
+func ReadNetDev() ([]NetDev, error) {
+	data, err := os.ReadFile(procPath + "/net/dev")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read net dev: %s", err)
+	}
+
+	// 두 줄의 헤더 이후 "  eth0: rx_bytes rx_packets rx_errs rx_drop ... tx_bytes tx_packets tx_errs tx_drop ..." 형식
+	var devs []NetDev
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		name, rest, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		name = strings.TrimSpace(name)
+		if name == "lo" {
+			continue
+		}
+
+		fields := strings.Fields(rest)
+		if len(fields) < 12 {
+			return nil, fmt.Errorf("invalid net dev format: %s", scanner.Text())
+		}
+		values := make([]uint64, 12)
+		for i := range values {
+			values[i], err = strconv.ParseUint(fields[i], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid net dev format: %s", scanner.Text())
+			}
+		}
+		devs = append(devs, NetDev{
+			Name:      name,
+			RxBytes:   values[0],
+			RxPackets: values[1],
+			RxErrors:  values[2],
+			RxDropped: values[3],
+			TxBytes:   values[8],
+			TxPackets: values[9],
+			TxErrors:  values[10],
+			TxDropped: values[11],
+		})
+	}
+
+	return devs, nil
+}
@@ -0,0 +1,244 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+/*
+Package sysstat 시스템 자원 사용량(/proc) 조회 범용 패키지
+
+누적 카운터(CPU 시간, 송수신 바이트 등)는 조회 시점의 값을 그대로 반환하며,
+사용률과 초당 변화량은 두 시점의 값 차이로 호출하는 쪽에서 계산한다.
+*/
+package sysstat
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// procPath proc 파일 시스템 경로
+const procPath = "/proc"
+
+// CPUTimes CPU 누적 사용 시간(틱) 구조체 (/proc/stat)
+type CPUTimes struct {
+	// cpu(전체) 또는 cpu0, cpu1 등
+	Name    string
+	User    uint64
+	Nice    uint64
+	System  uint64
+	Idle    uint64
+	IOWait  uint64
+	IRQ     uint64
+	SoftIRQ uint64
+	Steal   uint64
+}
+
+// Total 전체 누적 시간 반환 (guest 시간은 user에 포함되어 있으므로 제외)
+//
+// Returns:
+//   - uint64: 전체 누적 시간(틱)
+func (c *CPUTimes) Total() uint64 {
+	return c.User + c.Nice + c.System + c.Idle + c.IOWait + c.IRQ + c.SoftIRQ + c.Steal
+}
+
+// Memory 메모리 사용량 구조체 (/proc/meminfo, 바이트)
+type Memory struct {
+	Total     uint64 `json:"total"`
+	Free      uint64 `json:"free"`
+	Available uint64 `json:"available"`
+	Buffers   uint64 `json:"buffers"`
+	Cached    uint64 `json:"cached"`
+	Shared    uint64 `json:"shared"`
+	// 사용 중인 메모리 (Total - Available)
+	Used        uint64  `json:"used"`
+	UsedPercent float64 `json:"usedPercent"`
+	SwapTotal   uint64  `json:"swapTotal"`
+	SwapFree    uint64  `json:"swapFree"`
+	SwapUsed    uint64  `json:"swapUsed"`
+}
+
+// Load 시스템 부하 구조체 (/proc/loadavg)
+type Load struct {
+	Load1  float64 `json:"load1"`
+	Load5  float64 `json:"load5"`
+	Load15 float64 `json:"load15"`
+	// 실행 중인 스케줄링 단위(스레드) 수
+	Running int `json:"running"`
+	// 전체 스케줄링 단위(스레드) 수
+	Total int `json:"total"`
+}
+
+// ReadCPU CPU 누적 사용 시간 조회
+//
+// Returns:
+//   - []CPUTimes: 전체 CPU(첫 번째 항목) 및 코어별 누적 사용 시간
+//   - error: 성공(nil), 실패(error)
+func ReadCPU() ([]CPUTimes, error) {
+	data, err := os.ReadFile(procPath + "/stat")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cpu stat: %s", err)
+	}
+
+	var cpus []CPUTimes
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 9 || !strings.HasPrefix(fields[0], "cpu") {
+			continue
+		}
+
+		values := make([]uint64, 8)
+		for i := range values {
+			values[i], err = strconv.ParseUint(fields[i+1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cpu stat format: %s", scanner.Text())
+			}
+		}
+		cpus = append(cpus, CPUTimes{
+			Name:    fields[0],
+			User:    values[0],
+			Nice:    values[1],
+			System:  values[2],
+			Idle:    values[3],
+			IOWait:  values[4],
+			IRQ:     values[5],
+			SoftIRQ: values[6],
+			Steal:   values[7],
+		})
+	}
+
+	if len(cpus) == 0 || cpus[0].Name != "cpu" {
+		return nil, fmt.Errorf("cpu stat not found in %s/stat", procPath)
+	}
+	return cpus, nil
+}
+
+// ReadMemory 메모리 사용량 조회
+//
+// Returns:
+//   - *Memory: 메모리 사용량
+//   - error: 성공(nil), 실패(error)
+func ReadMemory() (*Memory, error) {
+	data, err := os.ReadFile(procPath + "/meminfo")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read meminfo: %s", err)
+	}
+
+	// "MemTotal:       16305836 kB" 형식
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, rest, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) > 1 && fields[1] == "kB" {
+			value *= 1024
+		}
+		values[key] = value
+	}
+
+	mem := &Memory{
+		Total:     values["MemTotal"],
+		Free:      values["MemFree"],
+		Buffers:   values["Buffers"],
+		Cached:    values["Cached"] + values["SReclaimable"],
+		Shared:    values["Shmem"],
+		SwapTotal: values["SwapTotal"],
+		SwapFree:  values["SwapFree"],
+	}
+	if mem.Total == 0 {
+		return nil, fmt.Errorf("MemTotal not found in %s/meminfo", procPath)
+	}
+
+	// MemAvailable이 없는 오래된 커널은 free, buffers, cached 합계로 대체
+	if available, ok := values["MemAvailable"]; ok {
+		mem.Available = available
+	} else {
+		mem.Available = min(mem.Free+mem.Buffers+mem.Cached, mem.Total)
+	}
+	mem.Used = mem.Total - min(mem.Available, mem.Total)
+	mem.UsedPercent = Percent(mem.Used, mem.Total)
+	mem.SwapUsed = mem.SwapTotal - min(mem.SwapFree, mem.SwapTotal)
+
+	return mem, nil
+}
+
+// ReadLoad 시스템 부하 조회
+//
+// Returns:
+//   - *Load: 시스템 부하
+//   - error: 성공(nil), 실패(error)
+func ReadLoad() (*Load, error) {
+	data, err := os.ReadFile(procPath + "/loadavg")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read loadavg: %s", err)
+	}
+
+	// "0.20 0.18 0.12 1/80 11206" 형식
+	fields := strings.Fields(string(data))
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("invalid loadavg format: %s", data)
+	}
+
+	var load Load
+	for i, dst := range []*float64{&load.Load1, &load.Load5, &load.Load15} {
+		if *dst, err = strconv.ParseFloat(fields[i], 64); err != nil {
+			return nil, fmt.Errorf("invalid loadavg format: %s", data)
+		}
+	}
+	running, total, _ := strings.Cut(fields[3], "/")
+	load.Running, _ = strconv.Atoi(running)
+	load.Total, _ = strconv.Atoi(total)
+
+	return &load, nil
+}
+
+// Percent 비율을 백분율로 변환 (소수점 첫째 자리 반올림)
+//
+// Parameters:
+//   - part: 부분 값
+//   - total: 전체 값
+//
+// Returns:
+//   - float64: 백분율 (전체 값이 0일 경우 0)
+func Percent(part, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return Round(float64(part) * 100 / float64(total))
+}
+
+// Round 소수점 첫째 자리 반올림
+//
+// Parameters:
+//   - value: 값
+//
+// Returns:
+//   - float64: 반올림된 값
+func Round(value float64) float64 {
+	return float64(int64(value*10+0.5)) / 10
+}
@@ -0,0 +1,175 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+/*
+Package systemd systemd 서비스 연동 범용 패키지
+
+sd_notify 프로토콜($NOTIFY_SOCKET)로 서비스 상태와 watchdog을 알리고,
+소켓 활성화(LISTEN_FDS)로 전달받은 리스너를 제공한다.
+systemd 환경이 아닐 경우 모든 함수는 아무 동작도 하지 않는다.
+*/
+package systemd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// 소켓 활성화로 전달받는 첫 번째 파일 디스크립터 (SD_LISTEN_FDS_START)
+const listenFdsStart = 3
+
+// sd_notify 상태 메시지
+const (
+	// StateReady 서비스 가동 완료
+	StateReady = "READY=1"
+	// StateStopping 서비스 정지 시작
+	StateStopping = "STOPPING=1"
+	// StateWatchdog watchdog 갱신
+	StateWatchdog = "WATCHDOG=1"
+)
+
+// Notify systemd에 서비스 상태 알림 ($NOTIFY_SOCKET이 없을 경우 무시)
+//
+// Parameters:
+//   - states: 상태 메시지 목록 (예: READY=1, STATUS=...)
+//
+// Returns:
+//   - error: 성공(nil), 실패(error)
+func Notify(states ...string) error {
+	path := os.Getenv("NOTIFY_SOCKET")
+	if path == "" {
+		return nil
+	}
+
+	// '@'로 시작하는 추상 소켓 이름은 net 패키지가 처리함
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("failed to connect to notify socket (%s): %s", path, err)
+	}
+	defer conn.Close()
+
+	var msg []byte
+	for _, state := range states {
+		msg = append(msg, state...)
+		msg = append(msg, '\n')
+	}
+	if _, err := conn.Write(msg); err != nil {
+		return fmt.Errorf("failed to send notify message: %s", err)
+	}
+
+	return nil
+}
+
+// Status 서비스 상태 설명 메시지 반환 (systemctl status에 표시)
+//
+// Parameters:
+//   - format: 메시지 형식
+//   - args: 메시지 인자
+//
+// Returns:
+//   - string: 상태 메시지 (STATUS=...)
+func Status(format string, args ...interface{}) string {
+	return "STATUS=" + fmt.Sprintf(format, args...)
+}
+
+// WatchdogInterval systemd watchdog 타임아웃 반환 (WATCHDOG_USEC)
+//
+// Returns:
+//   - time.Duration: watchdog 타임아웃 (watchdog을 사용하지 않을 경우 0)
+func WatchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+
+	// 다른 프로세스를 대상으로 설정된 watchdog은 무시
+	if pidStr := os.Getenv("WATCHDOG_PID"); pidStr != "" {
+		pid, err := strconv.Atoi(pidStr)
+		if err != nil || pid != os.Getpid() {
+			return 0
+		}
+	}
+
+	return time.Duration(usec) * time.Microsecond
+}
+
+// Watchdog watchdog 타임아웃의 절반 주기로 watchdog 갱신 (고루틴 작업용)
+//
+// Parameters:
+//   - ctx: 작업 컨텍스트 (취소 시 종료)
+func Watchdog(ctx context.Context) {
+	interval := WatchdogInterval()
+	if interval == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+
+	for {
+		Notify(StateWatchdog)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Listeners 소켓 활성화로 전달받은 리스너 목록 반환 (LISTEN_PID, LISTEN_FDS)
+//
+// 실행하는 자식 프로세스에 전달되지 않도록 관련 환경 변수와 파일 디스크립터를 정리한다.
+//
+// Returns:
+//   - []net.Listener: 리스너 목록 (소켓 활성화가 아닐 경우 nil)
+//   - error: 성공(nil), 실패(error)
+func Listeners() ([]net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count <= 0 {
+		return nil, nil
+	}
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	var listeners []net.Listener
+	for fd := listenFdsStart; fd < listenFdsStart+count; fd++ {
+		syscall.CloseOnExec(fd)
+		file := os.NewFile(uintptr(fd), "listen-fd-"+strconv.Itoa(fd))
+		// FileListener는 파일 디스크립터를 복제하므로 원본은 닫음
+		listener, err := net.FileListener(file)
+		file.Close()
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, fmt.Errorf("failed to use inherited socket (fd:%d): %s", fd, err)
+		}
+		listeners = append(listeners, listener)
+	}
+
+	return listeners, nil
+}
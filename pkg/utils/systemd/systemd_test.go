@@ -0,0 +1,164 @@
+// Copyright 2024 JongHoon Shim and The weblin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package systemd
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// listenNotify 테스트용 NOTIFY_SOCKET 수신 소켓 생성
+//
+// Parameters:
+//   - t: 테스트 정보
+//
+// Returns:
+//   - *net.UnixConn: 알림 메시지 수신 소켓
+func listenNotify(t *testing.T) *net.UnixConn {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	t.Setenv("NOTIFY_SOCKET", path)
+
+	return conn
+}
+
+// readNotify 알림 메시지 1개 수신
+//
+// Parameters:
+//   - t: 테스트 정보
+//   - conn: 알림 메시지 수신 소켓
+//
+// Returns:
+//   - string: 수신한 메시지
+func readNotify(t *testing.T, conn *net.UnixConn) string {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	buf := make([]byte, 4096)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("failed to read notify message: %s", err)
+	}
+	return string(buf[:n])
+}
+
+func TestNotify(t *testing.T) {
+	conn := listenNotify(t)
+
+	tests := []struct {
+		name   string
+		states []string
+		want   string
+	}{
+		{"ready", []string{StateReady, Status("Listening on %s", ":8080")}, "READY=1\nSTATUS=Listening on :8080\n"},
+		{"status", []string{Status("Failed to reload configuration: %s", "bad")}, "STATUS=Failed to reload configuration: bad\n"},
+		{"stopping", []string{StateStopping, Status("Stopping")}, "STOPPING=1\nSTATUS=Stopping\n"},
+		{"watchdog", []string{StateWatchdog}, "WATCHDOG=1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Notify(tt.states...); err != nil {
+				t.Fatalf("Notify() error = %s", err)
+			}
+			if got := readNotify(t, conn); got != tt.want {
+				t.Errorf("Notify() sent %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotifyWithoutSocket(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	if err := Notify(StateReady); err != nil {
+		t.Errorf("Notify() error = %s, want nil", err)
+	}
+
+	t.Setenv("NOTIFY_SOCKET", filepath.Join(t.TempDir(), "missing.sock"))
+	if err := Notify(StateReady); err == nil {
+		t.Errorf("Notify() error = nil, want error for missing socket")
+	}
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	pid := strconv.Itoa(os.Getpid())
+	otherPid := strconv.Itoa(os.Getpid() + 1)
+
+	tests := []struct {
+		name string
+		usec string
+		pid  string
+		want time.Duration
+	}{
+		{"no watchdog", "", "", 0},
+		{"without pid", "2000000", "", 2 * time.Second},
+		{"pid match", "2000000", pid, 2 * time.Second},
+		{"pid mismatch", "2000000", otherPid, 0},
+		{"invalid pid", "2000000", "abc", 0},
+		{"invalid usec", "abc", pid, 0},
+		{"zero usec", "0", pid, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("WATCHDOG_USEC", tt.usec)
+			t.Setenv("WATCHDOG_PID", tt.pid)
+			if got := WatchdogInterval(); got != tt.want {
+				t.Errorf("WatchdogInterval() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListenersPidMismatch(t *testing.T) {
+	tests := []struct {
+		name string
+		pid  string
+		fds  string
+	}{
+		{"no socket activation", "", ""},
+		{"pid mismatch", strconv.Itoa(os.Getpid() + 1), "1"},
+		{"invalid pid", "abc", "1"},
+		{"no fds", strconv.Itoa(os.Getpid()), "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LISTEN_PID", tt.pid)
+			t.Setenv("LISTEN_FDS", tt.fds)
+
+			listeners, err := Listeners()
+			if err != nil || listeners != nil {
+				t.Fatalf("Listeners() = %v, %v, want nil, nil", listeners, err)
+			}
+			// 다른 프로세스 대상의 환경 변수는 그대로 유지
+			if got := os.Getenv("LISTEN_PID"); got != tt.pid {
+				t.Errorf("LISTEN_PID = %q, want %q", got, tt.pid)
+			}
+			if got := os.Getenv("LISTEN_FDS"); got != tt.fds {
+				t.Errorf("LISTEN_FDS = %q, want %q", got, tt.fds)
+			}
+		})
+	}
+}